@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/usecase"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RunHoldReaper expires holds that ran out every interval until ctx is done
+func RunHoldReaper(ctx context.Context, uc usecase.AppointmentUsecase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reapHolds(uc)
+		}
+	}
+}
+
+// reapHolds runs a single reaping with its own traceparent
+func reapHolds(uc usecase.AppointmentUsecase) {
+	ftx, err := factory.NewFactoryFromTraceParent(middleware.GenerateTraceParent())
+	if err != nil {
+		return
+	}
+
+	expired, err := uc.ReapHolds(ftx)
+	if err != nil {
+		ftx.Logger().Error("Hold reaping failed", zap.Error(err))
+		return
+	}
+	if expired > 0 {
+		ftx.Logger().Info("Expired slot holds", zap.Int64("count", expired))
+	}
+}
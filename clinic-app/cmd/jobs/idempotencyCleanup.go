@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/usecase"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RunIdempotencyCleanup removes expired idempotency keys every interval until ctx is done
+func RunIdempotencyCleanup(ctx context.Context, uc usecase.IdempotencyUsecase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purgeIdempotencyKeys(uc)
+		}
+	}
+}
+
+// purgeIdempotencyKeys runs a single cleanup with its own traceparent
+func purgeIdempotencyKeys(uc usecase.IdempotencyUsecase) {
+	ftx, err := factory.NewFactoryFromTraceParent(middleware.GenerateTraceParent())
+	if err != nil {
+		return
+	}
+
+	deleted, err := uc.PurgeExpired(ftx)
+	if err != nil {
+		ftx.Logger().Error("Idempotency key cleanup failed", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		ftx.Logger().Info("Removed expired idempotency keys", zap.Int64("count", deleted))
+	}
+}
@@ -0,0 +1,41 @@
+package jobs
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/usecase"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RunScheduleMaterialiser keeps the schedules materialised from the working hours up to the horizon,
+// once at startup and then every interval until ctx is done.
+func RunScheduleMaterialiser(ctx context.Context, uc usecase.ScheduleUsecase, interval time.Duration) {
+	materialiseSchedules(uc)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			materialiseSchedules(uc)
+		}
+	}
+}
+
+// materialiseSchedules runs a single materialisation with its own traceparent
+func materialiseSchedules(uc usecase.ScheduleUsecase) {
+	ftx, err := factory.NewFactoryFromTraceParent(middleware.GenerateTraceParent())
+	if err != nil {
+		return
+	}
+
+	if err := uc.MaterialiseSchedules(ftx); err != nil {
+		ftx.Logger().Error("Schedule materialisation failed", zap.Error(err))
+	}
+}
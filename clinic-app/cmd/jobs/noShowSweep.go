@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/usecase"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RunNoShowSweep marks past appointments nobody checked in for as no-show every interval until ctx is done.
+// Appointments get grace after their end before they are swept, so late check-ins are still possible.
+func RunNoShowSweep(ctx context.Context, uc usecase.AppointmentUsecase, interval, grace time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sweepNoShows(uc, grace)
+		}
+	}
+}
+
+// sweepNoShows runs a single sweep with its own traceparent
+func sweepNoShows(uc usecase.AppointmentUsecase, grace time.Duration) {
+	ftx, err := factory.NewFactoryFromTraceParent(middleware.GenerateTraceParent())
+	if err != nil {
+		return
+	}
+
+	marked, err := uc.SweepNoShows(ftx, grace)
+	if err != nil {
+		ftx.Logger().Error("No-show sweep failed", zap.Error(err))
+		return
+	}
+	if marked > 0 {
+		ftx.Logger().Info("Marked appointments as no-show", zap.Int64("count", marked))
+	}
+}
@@ -0,0 +1,68 @@
+package jobs
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/usecase"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RunWaitlistPromotion offers freed slots to waitlisted patients as soon as capacity is released and every interval
+// until ctx is done. The scheduled runs pick up what the releases miss, such as expired offers.
+func RunWaitlistPromotion(ctx context.Context, uc usecase.WaitlistUsecase, interval time.Duration, released <-chan models.ReleasedCapacity) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case capacity := <-released:
+			promoteReleased(uc, capacity)
+		case <-ticker.C:
+			promoteWaitlist(uc)
+		}
+	}
+}
+
+// promoteWaitlist runs a single promotion with its own traceparent
+func promoteWaitlist(uc usecase.WaitlistUsecase) {
+	ftx, err := factory.NewFactoryFromTraceParent(middleware.GenerateTraceParent())
+	if err != nil {
+		return
+	}
+
+	offered, err := uc.Promote(ftx)
+	if err != nil {
+		ftx.Logger().Error("Waitlist promotion failed", zap.Error(err))
+		return
+	}
+	if offered > 0 {
+		ftx.Logger().Info("Offered freed slots to waitlisted patients", zap.Int("count", offered))
+	}
+}
+
+// promoteReleased offers released capacity under the traceparent of the request that released it
+func promoteReleased(uc usecase.WaitlistUsecase, capacity models.ReleasedCapacity) {
+	traceparent := capacity.TraceParent
+	if traceparent == "" {
+		traceparent = middleware.GenerateTraceParent()
+	}
+	ftx, err := factory.NewFactoryFromTraceParent(traceparent)
+	if err != nil {
+		return
+	}
+
+	offered, err := uc.PromoteReleased(ftx, capacity)
+	if err != nil {
+		ftx.Logger().Error("Waitlist promotion of released capacity failed", zap.Int("DoctorID", capacity.DoctorID), zap.Error(err))
+		return
+	}
+	if offered > 0 {
+		ftx.Logger().Info("Offered released capacity to waitlisted patients", zap.Int("DoctorID", capacity.DoctorID), zap.Int("count", offered))
+	}
+}
@@ -1,20 +1,44 @@
 package main
 
 import (
+	"clinic-app/cmd/jobs"
 	"clinic-app/cmd/rest"
+	"clinic-app/cmd/rest/handler"
+	"clinic-app/cmd/rest/middleware"
 	"clinic-app/internal/config"
 	"clinic-app/internal/constants"
 	"clinic-app/pkg/adapters"
 	"clinic-app/pkg/infra"
 	adminRepo "clinic-app/pkg/repository/admin"
 	appointmentsRepo "clinic-app/pkg/repository/appointments"
+	appointmentTypesRepo "clinic-app/pkg/repository/appointmenttypes"
 	authenticationRepo "clinic-app/pkg/repository/authentication"
+	authorizationRepo "clinic-app/pkg/repository/authorization"
 	doctorRepo "clinic-app/pkg/repository/doctor"
+	idempotencyRepo "clinic-app/pkg/repository/idempotency"
+	policyRepo "clinic-app/pkg/repository/policy"
+	scheduleRepo "clinic-app/pkg/repository/schedule"
+	timeOffRepo "clinic-app/pkg/repository/timeoff"
+	waitlistRepo "clinic-app/pkg/repository/waitlist"
 	"clinic-app/pkg/services"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/clinictime"
+	"clinic-app/pkg/services/lockout"
+	"clinic-app/pkg/services/password"
+	"clinic-app/pkg/services/releases"
+	"clinic-app/pkg/services/token"
+	"clinic-app/pkg/services/totp"
 	adminUsecase "clinic-app/pkg/usecase/admin"
 	appointmentsUsecase "clinic-app/pkg/usecase/appointments"
+	appointmentTypesUsecase "clinic-app/pkg/usecase/appointmenttypes"
 	authenticationUsecase "clinic-app/pkg/usecase/authentication"
 	doctorUsecase "clinic-app/pkg/usecase/doctor"
+	idempotencyUsecase "clinic-app/pkg/usecase/idempotency"
+	policyUsecase "clinic-app/pkg/usecase/policy"
+	scheduleUsecase "clinic-app/pkg/usecase/schedule"
+	timeOffUsecase "clinic-app/pkg/usecase/timeoff"
+	waitlistUsecase "clinic-app/pkg/usecase/waitlist"
+	"context"
 	"log"
 	"os"
 	"os/signal"
@@ -27,11 +51,16 @@ func main() {
 	// ========= Load Environment Variables =========
 	cfg := config.LoadConfig()
 
+	// ========= Setup Clinic Timezone =========
+	clinictime.SetUpLocation(cfg.ClinicLocation)
+
 	// ========= Setup Adapters =========
 	adpt, err := adapters.SetupAdapters(&adapters.Options{
 		DBConnStr:      cfg.DBConnStr,
 		DBMaxIdleConns: cfg.DBMaxIdleConns,
 		DBMaxOpenConns: cfg.DBMaxOpenConns,
+		DBTimeZone:     cfg.ClinicLocation.String(),
+		Mail:           cfg.Mail,
 	})
 	if err != nil {
 		log.Fatal("Error setting up adapters", zap.Error(err))
@@ -52,6 +81,13 @@ func main() {
 	authRepo := authenticationRepo.New()
 	aptmtRepo := appointmentsRepo.New()
 	doctorRepo := doctorRepo.New()
+	authzRepo := authorizationRepo.New()
+	scheduleRepo := scheduleRepo.New()
+	timeOffRepo := timeOffRepo.New()
+	policyRepo := policyRepo.New()
+	idempotencyRepo := idempotencyRepo.New()
+	waitlistRepo := waitlistRepo.New()
+	aptmtTypeRepo := appointmentTypesRepo.New()
 
 	// ========= Setup Services =========
 	err = services.SetupService(&services.Options{
@@ -62,27 +98,125 @@ func main() {
 		log.Fatal("Error setting up services", zap.Error(err))
 	}
 
+	// ========= Setup Token Signing Keys =========
+	signingKeys, err := token.NewKeySet(cfg.JWT)
+	if err != nil {
+		log.Fatal("Error loading token signing keys", zap.Error(err))
+	}
+
+	// ========= Setup Two-Factor Secret Encryption =========
+	mfaCipher, err := totp.NewCipher(cfg.MFA.EncryptionKey)
+	if err != nil {
+		log.Fatal("Error setting up two-factor secret encryption", zap.Error(err))
+	}
+
+	// ========= Setup Authorization Policy =========
+	authorizer := authz.New(authzRepo)
+
+	// ========= Setup Waitlist Releases =========
+	released := releases.New(100) // Cancellations and capacity changes queued for the waitlist promotion
+
 	// ========= Setup Usecases =========
 	adminUsecase := adminUsecase.New(
 		adminRepo,
 	)
 	authUsecase := authenticationUsecase.New(
 		authRepo,
+		password.NewHasher(cfg.PasswordHashCost),
+		token.NewManager(signingKeys, cfg.AccessTokenTTL, cfg.RefreshTokenTTL),
+		token.NewRevocationCache(cfg.RevocationCacheTTL),
+		cfg.InvitationTTL,
+		adpt.Mailer,
+		cfg.EmailVerificationTTL,
+		cfg.PasswordResetTTL,
+		cfg.AppBaseURL,
+		mfaCipher,
+		cfg.MFA.Issuer,
+		cfg.MFA.RequiredRoles,
+		cfg.MFA.ChallengeTTL,
+		lockout.NewMemoryLimiter(lockout.Policy{
+			Threshold:       cfg.LoginLockout.MaxFailures,
+			BaseDelay:       cfg.LoginLockout.BackoffBase,
+			MaxDelay:        cfg.LoginLockout.BackoffMax,
+			LockoutDuration: cfg.LoginLockout.LockoutDuration,
+			Window:          cfg.LoginLockout.FailureWindow,
+		}),
+		lockout.NewMemoryLimiter(lockout.Policy{
+			Threshold:       cfg.LoginLockout.MaxFailuresPerIP,
+			BaseDelay:       cfg.LoginLockout.BackoffBase,
+			MaxDelay:        cfg.LoginLockout.BackoffMax,
+			LockoutDuration: cfg.LoginLockout.LockoutDuration,
+			Window:          cfg.LoginLockout.FailureWindow,
+		}),
 	)
 	aptmtsUsecase := appointmentsUsecase.New(
 		aptmtRepo,
+		aptmtTypeRepo,
+		authorizer,
+		cfg.SlotHoldTTL,
+		released,
 	)
 	doctorUsecase := doctorUsecase.New(
 		doctorRepo,
+		authorizer,
+	)
+	scheduleUsecase := scheduleUsecase.New(
+		scheduleRepo,
+		authorizer,
+		cfg.ScheduleHorizonDays,
+		released,
+	)
+	timeOffUsecase := timeOffUsecase.New(
+		timeOffRepo,
+		aptmtsUsecase,
+		authorizer,
+		released,
+	)
+	policyUsecase := policyUsecase.New(
+		policyRepo,
+		authorizer,
+		cfg.ScheduleHorizonDays,
+		released,
+	)
+	idempotencyUsecase := idempotencyUsecase.New(
+		idempotencyRepo,
+		cfg.IdempotencyKeyTTL,
+	)
+	waitlistUsecase := waitlistUsecase.New(
+		waitlistRepo,
+		aptmtsUsecase,
+		authorizer,
+		adpt.Mailer,
+		cfg.WaitlistOfferTTL,
+		cfg.AppBaseURL,
+	)
+	aptmtTypeUsecase := appointmentTypesUsecase.New(
+		aptmtTypeRepo,
+		authorizer,
 	)
 
+	// ========= Setup Authentication =========
+	middleware.SetUpAuthentication(authUsecase)
+	middleware.SetUpAuthorization(authorizer)
+	middleware.SetUpIdempotency(idempotencyUsecase)
+
 	// ========= Setup Handler =========
 	restHandler := rest.NewRestHandler(
-		authUsecase, aptmtsUsecase, doctorUsecase, adminUsecase)
+		authUsecase, aptmtsUsecase, doctorUsecase, adminUsecase, scheduleUsecase, timeOffUsecase, policyUsecase,
+		waitlistUsecase, aptmtTypeUsecase,
+		handler.CookieOptions{Domain: cfg.CookieDomain, Secure: cfg.CookieSecure})
 
 	// ========= Setup Router =========
 	r := restHandler.SetupRouter(infrastructure.Logger)
 
+	// ========= Start Background Jobs =========
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	go jobs.RunNoShowSweep(jobsCtx, aptmtsUsecase, cfg.NoShowSweepInterval, cfg.NoShowGrace)
+	go jobs.RunScheduleMaterialiser(jobsCtx, scheduleUsecase, cfg.ScheduleMaterialiseInterval)
+	go jobs.RunIdempotencyCleanup(jobsCtx, idempotencyUsecase, cfg.IdempotencyCleanupInterval)
+	go jobs.RunWaitlistPromotion(jobsCtx, waitlistUsecase, cfg.WaitlistPromotionInterval, released)
+	go jobs.RunHoldReaper(jobsCtx, aptmtsUsecase, cfg.SlotHoldReapInterval)
+
 	// ========= Start Server =========
 	go func() {
 		infrastructure.Logger.Info("Starting server on port 8080")
@@ -96,5 +230,6 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	infrastructure.Logger.Info("Shutting down server")
+	stopJobs()
 
 }
@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"clinic-app/cmd/rest/middleware"
 	"clinic-app/pkg/domain/errors"
 	"clinic-app/pkg/domain/models"
 	"clinic-app/pkg/services/factory"
 	"clinic-app/pkg/usecase"
+	stderrors "errors"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -36,28 +39,56 @@ func (h *AppointmentHandler) Book(c *gin.Context) {
 	}
 
 	err := h.AptmtUsecase.Book(ftx, aptmt) // Call use case to book appointment
+	if conflict := (*errors.ConflictError)(nil); stderrors.As(err, &conflict) {
+		respondConflict(c, conflict) // Return conflict naming the overlapping appointment
+		return
+	}
 
 	switch err {
 	case errors.ErrDatabase:
 		ftx.Logger().Error("Booking failed", zap.Error(err))                     // Log database error
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Booking failed"}) // Return internal server error
 
-	case errors.ErrAppointmentExists:
-		ftx.Logger().Info("Appointment already exists for this time slot")                             // Log appointment exists error
-		c.JSON(http.StatusConflict, gin.H{"message": "Appointment already exists for this time slot"}) // Return conflict error
-
 	case errors.ErrDuration:
-		ftx.Logger().Info("Appointment duration is too long")                                                                          // Log invalid duration error
-		c.JSON(http.StatusNotAcceptable, gin.H{"message": "Appointment duration is invalid. Minimum - 15 minutes, Maximum - 2 hours"}) // Return not acceptable error
+		ftx.Logger().Info("Appointment duration is invalid")                           // Log invalid duration error
+		c.JSON(http.StatusNotAcceptable, gin.H{"message": errors.ErrDuration.Message}) // Return not acceptable error
+
+	case errors.ErrBeyondHorizon:
+		ftx.Logger().Info("Appointment beyond booking horizon")                             // Log booking horizon error
+		c.JSON(http.StatusNotAcceptable, gin.H{"message": errors.ErrBeyondHorizon.Message}) // Return not acceptable error
 
 	case errors.ErrNoSchedule:
 		ftx.Logger().Info("Schedule not found for Doctor")                                  // Log no schedule error
 		c.JSON(http.StatusNotAcceptable, gin.H{"message": "Schedule not found for Doctor"}) // Return not acceptable error
 
+	case errors.ErrOutsideHours:
+		ftx.Logger().Info("Appointment outside working hours")                             // Log outside working hours error
+		c.JSON(http.StatusNotAcceptable, gin.H{"message": errors.ErrOutsideHours.Message}) // Return not acceptable error
+
+	case errors.ErrDoctorOnTimeOff:
+		ftx.Logger().Info("Appointment during doctor time off")                               // Log time off error
+		c.JSON(http.StatusNotAcceptable, gin.H{"message": errors.ErrDoctorOnTimeOff.Message}) // Return not acceptable error
+
+	case errors.ErrSlotHeld:
+		ftx.Logger().Info("Slot held for another patient")                        // Log hold error
+		c.JSON(http.StatusConflict, gin.H{"message": errors.ErrSlotHeld.Message}) // Return conflict error
+
 	case errors.ErrDoctorOverbooked:
 		ftx.Logger().Info("All Appointments Booked")                                                               // Log overbooked error
 		c.JSON(http.StatusNotAcceptable, gin.H{"message": "Cannot Schedule Appointment. All Appointments Booked"}) // Return not acceptable error
 
+	case errors.ErrEmailNotVerified:
+		ftx.Logger().Info("Booking attempted with an unverified email")                  // Log unverified email error
+		c.JSON(http.StatusForbidden, gin.H{"error": errors.ErrEmailNotVerified.Message}) // Return forbidden error
+
+	case errors.ErrTypeNotOffered:
+		ftx.Logger().Info("Appointment type not offered by doctor")                          // Log type error
+		c.JSON(http.StatusNotAcceptable, gin.H{"message": errors.ErrTypeNotOffered.Message}) // Return not acceptable error
+
+	case errors.ErrTypeDuration:
+		ftx.Logger().Info("Appointment duration outside its type")                         // Log type duration error
+		c.JSON(http.StatusNotAcceptable, gin.H{"message": errors.ErrTypeDuration.Message}) // Return not acceptable error
+
 	default:
 		if err != nil {
 			ftx.Logger().Error("Unknown error occurred", zap.Error(err))                        // Log unknown error
@@ -83,13 +114,16 @@ func (h *AppointmentHandler) View(c *gin.Context) {
 	if err == errors.ErrNotFound {
 		c.JSON(http.StatusOK, gin.H{"message": "Appointment does not exist"}) // Return appointment not found
 		return
+	} else if err == errors.ErrForbidden {
+		c.JSON(http.StatusForbidden, gin.H{"error": errors.ErrForbidden.Message}) // Return forbidden if the caller is not a party to the appointment
+		return
 	} else if err != nil {
 		ftx.Logger().Error("Failed to retrieve appointment", zap.Error(err))                     // Log retrieval error
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve appointment"}) // Return internal server error
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"appointment": appointment}) // Return appointment details
+	c.JSON(http.StatusOK, gin.H{"appointment": appointment.InZone(middleware.ResponseZone(c))}) // Return appointment details
 }
 
 // PatientHistoryForDoctor handles retrieving a patient's appointment history for a specific doctor
@@ -104,8 +138,18 @@ func (h *AppointmentHandler) PatientHistoryForDoctor(c *gin.Context) {
 		return
 	}
 
-	appointments, err := h.AptmtUsecase.PatientHistoryForDoctor(ftx, patientID) // Call use case to retrieve patient history for doctor
+	includeCanceled, err := includeCanceledParam(c) // Read whether cancelled appointments are wanted
 	if err != nil {
+		ftx.Logger().Error("Invalid include_canceled", zap.Error(err))                          // Log invalid parameter error
+		c.JSON(http.StatusBadRequest, gin.H{"error": "include_canceled must be true or false"}) // Return bad request error
+		return
+	}
+
+	appointments, err := h.AptmtUsecase.PatientHistoryForDoctor(ftx, patientID, includeCanceled) // Call use case to retrieve patient history for doctor
+	if err == errors.ErrForbidden {
+		c.JSON(http.StatusForbidden, gin.H{"error": errors.ErrForbidden.Message}) // Return forbidden if the doctor has not treated the patient
+		return
+	} else if err != nil {
 		ftx.Logger().Error("Failed to retrieve patient history", zap.Error(err))                     // Log retrieval error
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve patient history"}) // Return internal server error
 		return
@@ -116,15 +160,25 @@ func (h *AppointmentHandler) PatientHistoryForDoctor(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"appointments": appointments}) // Return patient history for doctor
+	c.JSON(http.StatusOK, gin.H{"appointments": models.InZone(appointments, middleware.ResponseZone(c))}) // Return patient history for doctor
 }
 
 // PatientHistory handles retrieving a patient's full appointment history
 func (h *AppointmentHandler) PatientHistory(c *gin.Context) {
 	ftx := c.MustGet("ftx").(factory.Service) // Extract service from context
 
-	appointments, err := h.AptmtUsecase.PatientHistory(ftx) // Call use case to retrieve patient history
+	includeCanceled, err := includeCanceledParam(c) // Read whether cancelled appointments are wanted
 	if err != nil {
+		ftx.Logger().Error("Invalid include_canceled", zap.Error(err))                          // Log invalid parameter error
+		c.JSON(http.StatusBadRequest, gin.H{"error": "include_canceled must be true or false"}) // Return bad request error
+		return
+	}
+
+	appointments, err := h.AptmtUsecase.PatientHistory(ftx, includeCanceled) // Call use case to retrieve patient history
+	if err == errors.ErrForbidden {
+		c.JSON(http.StatusForbidden, gin.H{"error": errors.ErrForbidden.Message}) // Return forbidden if the caller is not a patient
+		return
+	} else if err != nil {
 		ftx.Logger().Error("Failed to retrieve patient history", zap.Error(err))                     // Log retrieval error
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve patient history"}) // Return internal server error
 		return
@@ -135,7 +189,7 @@ func (h *AppointmentHandler) PatientHistory(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"appointments": appointments}) // Return patient history
+	c.JSON(http.StatusOK, gin.H{"appointments": models.InZone(appointments, middleware.ResponseZone(c))}) // Return patient history
 }
 
 // Cancel handles canceling an appointment
@@ -149,8 +203,31 @@ func (h *AppointmentHandler) Cancel(c *gin.Context) {
 		return
 	}
 
-	err = h.AptmtUsecase.Cancel(ftx, appointmentID) // Call use case to cancel appointment
-	if err != nil {
+	var cancel models.CancelAppointment
+	if err := c.ShouldBindJSON(&cancel); err != nil && err != io.EOF { // Bind the optional JSON body holding the reason
+		ftx.Logger().Error("Invalid input", zap.Error(err))                                                      // Log error if JSON binding fails
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input, the reason can be at most 255 characters"}) // Return bad request error
+		return
+	}
+	cancel.AppointmentID = appointmentID
+
+	// Occurrences of a series can be canceled together
+	if scope := c.DefaultQuery("scope", models.ScopeThis); scope != models.ScopeThis {
+		h.cancelSeries(c, ftx, cancel, scope)
+		return
+	}
+
+	err = h.AptmtUsecase.Cancel(ftx, cancel) // Call use case to cancel appointment
+	if err == errors.ErrNotFound {
+		c.JSON(http.StatusOK, gin.H{"message": "Appointment does not exist"}) // Return appointment not found
+		return
+	} else if err == errors.ErrForbidden {
+		c.JSON(http.StatusForbidden, gin.H{"error": errors.ErrForbidden.Message}) // Return forbidden if the appointment belongs to someone else or has already started
+		return
+	} else if stderrors.Is(err, errors.ErrInvalidTransition) {
+		respondInvalidTransition(c, err) // Return conflict if the appointment has already started or ended
+		return
+	} else if err != nil {
 		ftx.Logger().Error("Cancellation failed", zap.Error(err))                     // Log cancellation error
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Cancellation failed"}) // Return internal server error
 		return
@@ -158,3 +235,154 @@ func (h *AppointmentHandler) Cancel(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Appointment canceled successfully"}) // Return success message
 }
+
+// includeCanceledParam reads the include_canceled query parameter, cancelled appointments are included unless it is false
+func includeCanceledParam(c *gin.Context) (bool, error) {
+	value := c.Query("include_canceled")
+	if value == "" {
+		return true, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// Reschedule handles moving an appointment to a new time, optionally with another doctor
+func (h *AppointmentHandler) Reschedule(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Extract service from context
+
+	appointmentID, err := strconv.Atoi(c.Param("id")) // Convert appointment ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid appointment ID", zap.Error(err))            // Log invalid appointment ID error
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid appointment ID"}) // Return bad request error
+		return
+	}
+
+	var reschedule models.RescheduleAppointment
+	if err := c.ShouldBindJSON(&reschedule); err != nil { // Bind JSON input to reschedule model
+		ftx.Logger().Error("Invalid input", zap.Error(err))            // Log error if JSON binding fails
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // Return bad request error
+		return
+	}
+	reschedule.AppointmentID = appointmentID
+
+	// Occurrences of a series can be moved together
+	if scope := c.DefaultQuery("scope", models.ScopeThis); scope != models.ScopeThis {
+		h.rescheduleSeries(c, ftx, reschedule, scope)
+		return
+	}
+
+	newID, err := h.AptmtUsecase.Reschedule(ftx, reschedule) // Call use case to reschedule appointment
+	if conflict := (*errors.ConflictError)(nil); stderrors.As(err, &conflict) {
+		respondConflict(c, conflict) // Return conflict naming the overlapping appointment
+		return
+	}
+
+	switch err {
+	case nil:
+		c.JSON(http.StatusOK, gin.H{"message": "Appointment rescheduled successfully", "appointment_id": newID}) // Return the new booking
+
+	case errors.ErrNotFound:
+		c.JSON(http.StatusOK, gin.H{"message": "Appointment does not exist"}) // Return appointment not found
+
+	case errors.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": errors.ErrForbidden.Message}) // Return forbidden if the appointment belongs to someone else or has already started
+
+	case errors.ErrNotReschedulable:
+		c.JSON(http.StatusConflict, gin.H{"error": errors.ErrNotReschedulable.Message}) // Return conflict if the appointment is not scheduled anymore
+
+	case errors.ErrDuration:
+		c.JSON(http.StatusNotAcceptable, gin.H{"message": errors.ErrDuration.Message}) // Return not acceptable error
+
+	case errors.ErrBeyondHorizon:
+		c.JSON(http.StatusNotAcceptable, gin.H{"message": errors.ErrBeyondHorizon.Message}) // Return not acceptable error
+
+	case errors.ErrNoSchedule:
+		c.JSON(http.StatusNotAcceptable, gin.H{"message": "Schedule not found for Doctor"}) // Return not acceptable error
+
+	case errors.ErrOutsideHours:
+		c.JSON(http.StatusNotAcceptable, gin.H{"message": errors.ErrOutsideHours.Message}) // Return not acceptable error
+
+	case errors.ErrDoctorOnTimeOff:
+		c.JSON(http.StatusNotAcceptable, gin.H{"message": errors.ErrDoctorOnTimeOff.Message}) // Return not acceptable error
+
+	case errors.ErrSlotHeld:
+		c.JSON(http.StatusConflict, gin.H{"message": errors.ErrSlotHeld.Message}) // Return conflict error
+
+	case errors.ErrDoctorOverbooked:
+		c.JSON(http.StatusNotAcceptable, gin.H{"message": "Cannot Schedule Appointment. All Appointments Booked"}) // Return not acceptable error
+
+	default:
+		ftx.Logger().Error("Rescheduling failed", zap.Error(err))                     // Log unknown error
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Rescheduling failed"}) // Return internal server error
+	}
+}
+
+// CheckIn handles checking a patient in for their appointment
+func (h *AppointmentHandler) CheckIn(c *gin.Context) {
+	h.changeStatus(c, h.AptmtUsecase.CheckIn, "Patient checked in successfully")
+}
+
+// Start handles starting the consultation
+func (h *AppointmentHandler) Start(c *gin.Context) {
+	h.changeStatus(c, h.AptmtUsecase.Start, "Appointment started successfully")
+}
+
+// Complete handles finishing the consultation
+func (h *AppointmentHandler) Complete(c *gin.Context) {
+	h.changeStatus(c, h.AptmtUsecase.Complete, "Appointment completed successfully")
+}
+
+// NoShow handles recording that the patient did not come
+func (h *AppointmentHandler) NoShow(c *gin.Context) {
+	h.changeStatus(c, h.AptmtUsecase.MarkNoShow, "Appointment marked as no-show")
+}
+
+// changeStatus runs a status transition of the appointment in the URL and responds with its outcome
+func (h *AppointmentHandler) changeStatus(c *gin.Context, transition func(ftx factory.Service, appointmentId int) error, message string) {
+	ftx := c.MustGet("ftx").(factory.Service) // Extract service from context
+
+	appointmentID, err := strconv.Atoi(c.Param("id")) // Convert appointment ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid appointment ID", zap.Error(err))            // Log invalid appointment ID error
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid appointment ID"}) // Return bad request error
+		return
+	}
+
+	err = transition(ftx, appointmentID) // Call use case to change the status
+	if err == errors.ErrNotFound {
+		c.JSON(http.StatusOK, gin.H{"message": "Appointment does not exist"}) // Return appointment not found
+		return
+	} else if err == errors.ErrForbidden {
+		c.JSON(http.StatusForbidden, gin.H{"error": errors.ErrForbidden.Message}) // Return forbidden if the appointment belongs to someone else
+		return
+	} else if stderrors.Is(err, errors.ErrInvalidTransition) {
+		respondInvalidTransition(c, err) // Return conflict if the current status does not allow the change
+		return
+	} else if err == errors.ErrNoShowTooEarly || err == errors.ErrOutsideVisit {
+		c.JSON(http.StatusConflict, gin.H{"error": err.(*errors.ClinicAppError).Message}) // Return conflict if the time of the appointment does not allow the change
+		return
+	} else if err != nil {
+		ftx.Logger().Error("Status change failed", zap.Error(err))                     // Log status change error
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Status change failed"}) // Return internal server error
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message}) // Return success message
+}
+
+// respondInvalidTransition responds with a 409, naming both statuses when they are known
+func respondInvalidTransition(c *gin.Context, err error) {
+	if transition := (*errors.InvalidTransitionError)(nil); stderrors.As(err, &transition) {
+		c.JSON(http.StatusConflict, gin.H{"error": transition.Error(), "status": transition.From})
+		return
+	}
+	c.JSON(http.StatusConflict, gin.H{"error": errors.ErrInvalidTransition.Message})
+}
+
+// respondConflict responds with a 409 naming the appointment a booking overlaps
+func respondConflict(c *gin.Context, conflict *errors.ConflictError) {
+	body := gin.H{"error": errors.ErrAppointmentExists.Message}
+	if conflict.AppointmentID != 0 {
+		body["conflicting_appointment_id"] = conflict.AppointmentID
+	}
+	c.JSON(http.StatusConflict, body)
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"bytes"
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/internal/config"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/repository"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/services/token"
+	"clinic-app/pkg/usecase/appointments"
+	"clinic-app/pkg/usecase/authentication"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// fakeTokenStore never revokes a token
+type fakeTokenStore struct {
+	repository.AuthenticationRepository
+}
+
+func (fakeTokenStore) IsAccessTokenRevoked(ftx factory.Service, jti string) (bool, error) {
+	return false, nil
+}
+
+// noOwnership panics if the authorizer has to look up who owns a resource, the callers only act on their own
+type noOwnership struct {
+	repository.AuthorizationRepository
+}
+
+// interleavedAppointments keeps the appointments of every patient in memory. Like the database repository it books
+// for and reads the history of the caller of the service, and every call waits until the other requests got as far,
+// so the requests are always in flight together.
+type interleavedAppointments struct {
+	repository.AppointmentRepository
+	booking sync.WaitGroup
+	reading sync.WaitGroup
+
+	mu     sync.Mutex
+	booked map[int][]models.Appointment
+	nextID int
+}
+
+func newInterleavedAppointments(requests int) *interleavedAppointments {
+	r := &interleavedAppointments{booked: map[int][]models.Appointment{}, nextID: 100}
+	r.booking.Add(requests)
+	r.reading.Add(requests)
+	return r
+}
+
+func (r *interleavedAppointments) BookAppointment(ftx factory.Service, aptmt models.BookAppointment) error {
+	meet(&r.booking)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	patientId := ftx.Principal().UserID
+	r.nextID++
+	r.booked[patientId] = append(r.booked[patientId], models.Appointment{
+		AppointmentID: r.nextID,
+		PatientID:     patientId,
+		DoctorID:      aptmt.DoctorID,
+		StartTime:     aptmt.StartTime,
+		EndTime:       aptmt.EndTime,
+		Status:        models.StatusScheduled,
+	})
+	return nil
+}
+
+func (r *interleavedAppointments) GetPatientAppointmentHistory(ftx factory.Service, includeCanceled bool) ([]models.Appointment, error) {
+	meet(&r.reading)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.booked[ftx.Principal().UserID], nil
+}
+
+// meet waits for the other requests to arrive, giving up after a while when one of them failed before
+func meet(arrivals *sync.WaitGroup) {
+	arrivals.Done()
+	arrived := make(chan struct{})
+	go func() {
+		arrivals.Wait()
+		close(arrived)
+	}()
+	select {
+	case <-arrived:
+	case <-time.After(5 * time.Second):
+	}
+}
+
+// patientRouter serves booking and history behind the middleware of the API, authenticating with signed tokens
+func patientRouter(t *testing.T, repo repository.AppointmentRepository) (*gin.Engine, *token.Manager) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	factory.SetUpDependencies(nil) // The requests never reach a database
+
+	keys, err := token.NewKeySet(config.JWTConfig{
+		Algorithm:   "HS256",
+		ActiveKeyID: "test",
+		Keys:        []config.SigningKeyConfig{{ID: "test", Secret: bytes.Repeat([]byte("k"), 32)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := token.NewManager(keys, time.Hour, time.Hour)
+	authUc := authentication.New(fakeTokenStore{}, nil, tokens, token.NewRevocationCache(time.Minute), 0, nil, 0, 0, "", nil, "", nil, 0, nil, nil)
+	authorizer := authz.New(noOwnership{})
+	middleware.SetUpAuthentication(authUc)
+	middleware.SetUpAuthorization(authorizer)
+
+	h := NewAppointmentHandler(appointments.New(repo, nil, authorizer, time.Minute, nil))
+	router := gin.New()
+	router.Use(middleware.TraceMiddleware(zap.NewNop()))
+	router.POST("/appointment/", middleware.Authorize(authz.AppointmentBook), h.Book)
+	router.GET("/appointment/history", middleware.Authorize(authz.PatientHistoryRead), h.PatientHistory)
+	return router, tokens
+}
+
+// request sends a request with the bearer token and decodes the JSON response
+func request(router *gin.Engine, method, path, accessToken string, body any, response any) int {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	json.Unmarshal(w.Body.Bytes(), response)
+	return w.Code
+}
+
+func TestInterleavedRequestsSeeOnlyTheirCallersAppointments(t *testing.T) {
+	patients := []int{10, 11}
+	repo := newInterleavedAppointments(len(patients))
+	router, tokens := patientRouter(t, repo)
+	start := time.Now().Add(72 * time.Hour).Truncate(time.Hour)
+
+	var wg sync.WaitGroup
+	for i, patientId := range patients {
+		wg.Add(1)
+		go func(i, patientId int) {
+			defer wg.Done()
+			accessToken, err := tokens.SignAccessToken(patientId, "patient", token.NewJTI(), time.Now().Add(time.Hour))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			// Each patient books their own hour with the same doctor
+			var booked gin.H
+			status := request(router, http.MethodPost, "/appointment/", accessToken, models.BookAppointment{
+				DoctorID:  20,
+				StartTime: start.Add(time.Duration(i) * time.Hour),
+				EndTime:   start.Add(time.Duration(i)*time.Hour + 30*time.Minute),
+			}, &booked)
+			if status != http.StatusOK {
+				t.Errorf("patient %d: booking status = %d, want %d (%v)", patientId, status, http.StatusOK, booked)
+				return
+			}
+
+			// And reads their history while the other patient reads theirs
+			var history struct {
+				Appointments []models.Appointment `json:"appointments"`
+			}
+			status = request(router, http.MethodGet, "/appointment/history", accessToken, nil, &history)
+			if status != http.StatusOK {
+				t.Errorf("patient %d: history status = %d, want %d", patientId, status, http.StatusOK)
+				return
+			}
+			if len(history.Appointments) != 1 {
+				t.Errorf("patient %d sees %d appointments, want 1", patientId, len(history.Appointments))
+				return
+			}
+			got := history.Appointments[0]
+			if got.PatientID != patientId || !got.StartTime.Equal(start.Add(time.Duration(i)*time.Hour)) {
+				t.Errorf("patient %d sees the appointment of patient %d at %s", patientId, got.PatientID, got.StartTime)
+			}
+		}(i, patientId)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AppointmentTypeHandler struct holds the AppointmentTypeUsecase to manage appointment types
+type AppointmentTypeHandler struct {
+	TypeUsecase usecase.AppointmentTypeUsecase
+}
+
+// NewAppointmentTypeHandler initializes a new AppointmentTypeHandler with the provided usecase
+func NewAppointmentTypeHandler(uc usecase.AppointmentTypeUsecase) *AppointmentTypeHandler {
+	return &AppointmentTypeHandler{
+		TypeUsecase: uc,
+	}
+}
+
+// ViewAll handles retrieving the appointment types
+func (h *AppointmentTypeHandler) ViewAll(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	types, err := h.TypeUsecase.Types(ftx) // Call usecase to get the types
+	if err != nil {
+		ftx.Logger().Error("Failed to retrieve appointment types", zap.Error(err))                     // Log error if retrieval fails
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve appointment types"}) // Return internal server error
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"appointment_types": models.InZone(types, middleware.ResponseZone(c))}) // Return the types
+}
+
+// Create handles adding an appointment type
+func (h *AppointmentTypeHandler) Create(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	var aptmtType models.AppointmentType
+	if err := c.ShouldBindJSON(&aptmtType); err != nil { // Bind JSON input to the type
+		ftx.Logger().Error("Invalid input", zap.Error(err))            // Log error if JSON binding fails
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // Return bad request error
+		return
+	}
+
+	created, err := h.TypeUsecase.CreateType(ftx, aptmtType) // Call usecase to add the type
+	if err != nil {
+		respondTypeError(c, ftx, err, "Failed to create appointment type")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"appointment_type": created.InZone(middleware.ResponseZone(c))}) // Return the new type
+}
+
+// Update handles changing an appointment type
+func (h *AppointmentTypeHandler) Update(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	typeId, err := strconv.Atoi(c.Param("id")) // Convert type ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid appointment type ID", zap.Error(err))            // Log error for invalid ID
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid appointment type ID"}) // Return bad request error
+		return
+	}
+
+	var aptmtType models.AppointmentType
+	if err := c.ShouldBindJSON(&aptmtType); err != nil { // Bind JSON input to the type
+		ftx.Logger().Error("Invalid input", zap.Error(err))            // Log error if JSON binding fails
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // Return bad request error
+		return
+	}
+	aptmtType.ID = typeId
+
+	updated, err := h.TypeUsecase.UpdateType(ftx, aptmtType) // Call usecase to change the type
+	if err != nil {
+		respondTypeError(c, ftx, err, "Failed to update appointment type")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"appointment_type": updated.InZone(middleware.ResponseZone(c))}) // Return the changed type
+}
+
+// Deactivate handles stopping an appointment type from being booked
+func (h *AppointmentTypeHandler) Deactivate(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	typeId, err := strconv.Atoi(c.Param("id")) // Convert type ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid appointment type ID", zap.Error(err))            // Log error for invalid ID
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid appointment type ID"}) // Return bad request error
+		return
+	}
+
+	if err := h.TypeUsecase.DeactivateType(ftx, typeId); err != nil { // Call usecase to deactivate the type
+		respondTypeError(c, ftx, err, "Failed to deactivate appointment type")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Appointment type deactivated"}) // Return success message
+}
+
+// ViewForDoctor handles retrieving the appointment types a doctor can be booked for
+func (h *AppointmentTypeHandler) ViewForDoctor(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	doctorId, err := strconv.Atoi(c.Param("id")) // Convert doctor ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid doctor ID", zap.Error(err))            // Log error for invalid ID
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor ID"}) // Return bad request error
+		return
+	}
+
+	types, err := h.TypeUsecase.DoctorTypes(ftx, doctorId) // Call usecase to get the types
+	if err != nil {
+		ftx.Logger().Error("Failed to retrieve appointment types", zap.Error(err))                     // Log error if retrieval fails
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve appointment types"}) // Return internal server error
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"appointment_types": models.InZone(types, middleware.ResponseZone(c))}) // Return the types
+}
+
+// SetForDoctor handles choosing the appointment types a doctor offers
+func (h *AppointmentTypeHandler) SetForDoctor(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	doctorId, err := strconv.Atoi(c.Param("id")) // Convert doctor ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid doctor ID", zap.Error(err))            // Log error for invalid ID
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor ID"}) // Return bad request error
+		return
+	}
+
+	var offered models.DoctorAppointmentTypes
+	if err := c.ShouldBindJSON(&offered); err != nil { // Bind JSON input to the type IDs
+		ftx.Logger().Error("Invalid input", zap.Error(err))            // Log error if JSON binding fails
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // Return bad request error
+		return
+	}
+
+	if err := h.TypeUsecase.SetDoctorTypes(ftx, doctorId, offered.TypeIDs); err != nil { // Call usecase to set the types
+		respondTypeError(c, ftx, err, "Failed to set doctor appointment types")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Doctor appointment types updated successfully"}) // Return success message
+}
+
+// respondTypeError writes the response for an error of the appointment type usecase
+func respondTypeError(c *gin.Context, ftx factory.Service, err error, failure string) {
+	switch err {
+	case errors.ErrInvalidAptmtType:
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.ErrInvalidAptmtType.Message}) // Return bad request error
+
+	case errors.ErrAptmtTypeExists:
+		c.JSON(http.StatusConflict, gin.H{"error": errors.ErrAptmtTypeExists.Message}) // Return conflict error
+
+	case errors.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Doctor or appointment type not found"}) // Return not found error
+
+	case errors.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": errors.ErrForbidden.Message}) // Return forbidden error
+
+	default:
+		ftx.Logger().Error(failure, zap.Error(err))                     // Log error if the change fails
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failure}) // Return internal server error
+	}
+}
@@ -1,34 +1,41 @@
 package handler
 
 import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
 	"clinic-app/pkg/domain/models"
 	"clinic-app/pkg/services/factory"
 	"clinic-app/pkg/usecase"
+	stderrors "errors"
+	"math"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt"
 	"go.uber.org/zap"
 )
 
-// Secret key for JWT
-var jwtKey = []byte("your_secret_key")
+// Name of the cookie holding the refresh token
+const refreshTokenCookie = "refresh_token"
 
-// Variables to store user role and ID globally
-var role = ""
-var UserID = 0
+// CookieOptions controls how the token cookies are set
+type CookieOptions struct {
+	Domain string // Empty for host-only cookies
+	Secure bool   // Only send the cookies over HTTPS
+}
 
 // AuthHandler struct holds the AuthUsecase to handle authentication
 type AuthHandler struct {
 	AuthUsecase usecase.AuthUsecase
+	Cookies     CookieOptions
 }
 
 // NewAuthHandler initializes a new AuthHandler with the provided usecase
-func NewAuthHandler(uc usecase.AuthUsecase) *AuthHandler {
+func NewAuthHandler(uc usecase.AuthUsecase, cookies CookieOptions) *AuthHandler {
 	return &AuthHandler{
 		AuthUsecase: uc,
+		Cookies:     cookies,
 	}
 }
 
@@ -46,7 +53,13 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	// Call usecase to register the user
 	userID, err := h.AuthUsecase.RegisterUser(ftx, user)
-	if err != nil {
+	if err == errors.ErrWeakPassword {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.ErrWeakPassword.Message}) // Return password policy violation
+		return
+	} else if err == errors.ErrRoleNotAllowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": errors.ErrRoleNotAllowed.Message}) // Return forbidden for staff roles
+		return
+	} else if err != nil {
 		ftx.Logger().Error("Registration failed", zap.Error(err))                     // Log registration failure
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Registration failed"}) // Return internal server error
 		return
@@ -72,51 +85,258 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Call usecase to login user
+	// Call usecase to login user, failed attempts are also counted per client IP
+	credentials.ClientIP = c.ClientIP()
 	user, err := h.AuthUsecase.LoginUser(ftx, credentials)
+	if locked := (*errors.LockedError)(nil); stderrors.As(err, &locked) {
+		abortTooManyAttempts(c, locked) // Return too many requests while backing off or locked out
+		return
+	}
 	if err != nil {
-		ftx.Logger().Error("Login failed", zap.Error(err))                     // Log login failure
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"}) // Return unauthorized error
+		ftx.Logger().Error("Login failed", zap.Error(err))                      // Log login failure
+		middleware.AbortUnauthorized(c, "invalid_grant", "Invalid credentials") // Return unauthorized error
 		return
 	}
 
-	// Set role and user ID globally
-	role = user.Role
-	UserID = user.ID
-
-	// Create JWT token with expiration time
-	expirationTime := time.Now().Add(24 * time.Hour)
-	claims := &models.Claims{
-		UserID: user.ID,
-		Role:   user.Role,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: expirationTime.Unix(), // Token expiration
-		},
+	// Users with two-factor authentication, or whose role requires it, get a challenge instead of tokens
+	challenge, err := h.AuthUsecase.LoginChallenge(ftx, user)
+	if err != nil {
+		ftx.Logger().Error("Failed to check two-factor authentication", zap.Error(err)) // Log two-factor lookup failure
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not log in"})      // Return internal server error
+		return
+	}
+	if challenge != nil {
+		message := "Enter the code from your authenticator app at /login/mfa"
+		if challenge.Purpose == models.ChallengeEnrollMFA {
+			message = "Two-factor authentication is required for your role, set it up at /mfa/enroll"
+		}
+		c.JSON(http.StatusOK, gin.H{"message": message, "mfa_required": true, "challenge": challenge.InZone(middleware.ResponseZone(c))})
+		return
 	}
 
-	// Generate JWT token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(jwtKey)                         // Sign token with secret key
-	c.SetCookie("token", tokenString, 3600, "/", "localhost", false, true) // Set token in cookie
-
-	// Add token to Authorization header
-	c.Request.Header.Add("Authorization", tokenString)
+	// Issue a short-lived access token and a refresh token
+	tokens, err := h.AuthUsecase.IssueTokens(ftx, user)
 	if err != nil {
 		ftx.Logger().Error("Failed to create JWT token", zap.Error(err))           // Log token creation failure
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not log in"}) // Return internal server error
 		return
 	}
+	h.setTokenCookies(c, tokens)
+
+	// Return login success response, with the tokens for clients that cannot use cookies
+	respondWithTokens(c, gin.H{"message": "Login Successful"}, tokens)
+}
+
+// Refresh handles exchanging a refresh token for a new token pair
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	// Call usecase to rotate the refresh token
+	tokens, err := h.AuthUsecase.RefreshTokens(ftx, refreshTokenFromRequest(c))
+	switch err {
+	case nil:
+		h.setTokenCookies(c, tokens)
+		respondWithTokens(c, gin.H{"message": "Token refreshed"}, tokens) // Return refresh success response
+
+	case errors.ErrInvalidToken, errors.ErrTokenReused:
+		h.clearTokenCookies(c)
+		middleware.AbortUnauthorized(c, "invalid_token", err.(*errors.ClinicAppError).Message) // Return unauthorized error
+
+	default:
+		ftx.Logger().Error("Token refresh failed", zap.Error(err))                        // Log refresh failure
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not refresh token"}) // Return internal server error
+	}
+}
+
+// Logout handles revoking the caller's tokens
+func (h *AuthHandler) Logout(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	accessToken, _ := middleware.AccessTokenFromRequest(c)
+
+	// Call usecase to revoke both tokens
+	if err := h.AuthUsecase.Logout(ftx, accessToken, refreshTokenFromRequest(c)); err != nil {
+		ftx.Logger().Error("Logout failed", zap.Error(err))                         // Log logout failure
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not log out"}) // Return internal server error
+		return
+	}
+
+	h.clearTokenCookies(c)
+	c.JSON(http.StatusOK, gin.H{"message": "Logout Successful"}) // Return logout success response
+}
+
+// JWKS handles publishing the public token verification keys
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	// Let verifiers cache the key set, a rotated key is published before it starts signing
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.AuthUsecase.PublicKeys(ftx))
+}
+
+// VerifyEmail handles confirming an email address with the token from the verification email
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	var verify models.VerifyEmail
+	if err := c.ShouldBindJSON(&verify); err != nil {
+		ftx.Logger().Error("Invalid input", zap.Error(err))            // Log error
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // Return bad request
+		return
+	}
+
+	// Call usecase to verify the address
+	err := h.AuthUsecase.VerifyEmail(ftx, verify.Token)
+	switch err {
+	case nil:
+		c.JSON(http.StatusOK, gin.H{"message": "Email verified"})
+
+	case errors.ErrLinkInvalid:
+		c.JSON(http.StatusGone, gin.H{"error": errors.ErrLinkInvalid.Message}) // Return gone for unusable tokens
+
+	default:
+		ftx.Logger().Error("Email verification failed", zap.Error(err))                  // Log verification failure
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify email"}) // Return internal server error
+	}
+}
+
+// ResendVerification handles mailing the caller a new verification link
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	// Call usecase to send a new link
+	err := h.AuthUsecase.ResendVerification(ftx)
+	switch err {
+	case nil:
+		c.JSON(http.StatusAccepted, gin.H{"message": "A verification email is on its way unless the address is already verified"})
+
+	case errors.ErrMailDelivery:
+		c.JSON(http.StatusBadGateway, gin.H{"error": errors.ErrMailDelivery.Message}) // Return bad gateway when the mail server fails
+
+	default:
+		ftx.Logger().Error("Resending verification failed", zap.Error(err))                         // Log resend failure
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not send verification email"}) // Return internal server error
+	}
+}
+
+// ForgotPassword handles requesting a password reset link
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	var forgot models.ForgotPassword
+	if err := c.ShouldBindJSON(&forgot); err != nil {
+		ftx.Logger().Error("Invalid input", zap.Error(err))            // Log error
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // Return bad request
+		return
+	}
+
+	// Call usecase to mail the reset links
+	if err := h.AuthUsecase.ForgotPassword(ftx, forgot.Email); err != nil {
+		ftx.Logger().Error("Password reset request failed", zap.Error(err))                        // Log request failure
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not request password reset"}) // Return internal server error
+		return
+	}
+
+	// Same answer whether or not the address belongs to an account
+	c.JSON(http.StatusAccepted, gin.H{"message": "If an account uses this email address, a password reset link is on its way"})
+}
+
+// ResetPassword handles choosing a new password with the token from the reset email
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	var reset models.ResetPassword
+	if err := c.ShouldBindJSON(&reset); err != nil {
+		ftx.Logger().Error("Invalid input", zap.Error(err))            // Log error
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // Return bad request
+		return
+	}
+
+	// Call usecase to replace the password
+	err := h.AuthUsecase.ResetPassword(ftx, reset)
+	switch err {
+	case nil:
+		h.clearTokenCookies(c)
+		c.JSON(http.StatusOK, gin.H{"message": "Password reset, please log in again"})
+
+	case errors.ErrWeakPassword:
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.ErrWeakPassword.Message}) // Return password policy violation
+
+	case errors.ErrLinkInvalid:
+		c.JSON(http.StatusGone, gin.H{"error": errors.ErrLinkInvalid.Message}) // Return gone for unusable tokens
+
+	default:
+		ftx.Logger().Error("Password reset failed", zap.Error(err))                        // Log reset failure
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not reset password"}) // Return internal server error
+	}
+}
+
+// Unlock handles lifting the lockout of a username or a client IP
+func (h *AuthHandler) Unlock(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	// Call usecase to lift the lockout named by the route
+	var err error
+	if username := c.Param("username"); username != "" {
+		err = h.AuthUsecase.UnlockUser(ftx, username)
+	} else {
+		err = h.AuthUsecase.UnlockIP(ftx, c.Param("ip"))
+	}
+	if err != nil {
+		ftx.Logger().Error("Unlock failed", zap.Error(err))                              // Log unlock failure
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not lift lockout"}) // Return internal server error
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Lockout lifted"})
+}
+
+// abortTooManyAttempts stops the request with a 429 telling the client when it may try again
+func abortTooManyAttempts(c *gin.Context, locked *errors.LockedError) {
+	retryAfter := int(math.Ceil(locked.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+		"error":       errors.ErrTooManyAttempts.Message,
+		"retry_after": retryAfter,
+	})
+}
+
+// refreshTokenFromRequest reads the refresh token from its cookie, or from the JSON body for clients without cookies
+func refreshTokenFromRequest(c *gin.Context) string {
+	if refreshToken, err := c.Cookie(refreshTokenCookie); err == nil && refreshToken != "" {
+		return refreshToken
+	}
+
+	var body struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	_ = c.ShouldBindJSON(&body)
+	return body.RefreshToken
+}
+
+// respondWithTokens returns a success body, adding the tokens and their expiry when the client asks for them with ?include_token=true
+func respondWithTokens(c *gin.Context, body gin.H, tokens models.TokenPair) {
+	if c.Query("include_token") == "true" {
+		tokens = tokens.InZone(middleware.ResponseZone(c))
+		body["token_type"] = "Bearer"
+		body["access_token"] = tokens.AccessToken
+		body["expires_in"] = int(time.Until(tokens.AccessExpiresAt).Seconds())
+		body["access_expires_at"] = tokens.AccessExpiresAt
+		body["refresh_token"] = tokens.RefreshToken
+		body["refresh_expires_at"] = tokens.RefreshExpiresAt
+	}
 
-	// Return login success response
-	c.JSON(http.StatusOK, gin.H{"message": "Login Successful"})
+	c.JSON(http.StatusOK, body)
 }
 
-// GetRole returns the current role of the user
-func GetRole() string {
-	return role
+// setTokenCookies stores a token pair in HTTP-only cookies
+func (h *AuthHandler) setTokenCookies(c *gin.Context, tokens models.TokenPair) {
+	c.SetCookie(middleware.AccessTokenCookie, tokens.AccessToken, int(time.Until(tokens.AccessExpiresAt).Seconds()), "/", h.Cookies.Domain, h.Cookies.Secure, true)
+	c.SetCookie(refreshTokenCookie, tokens.RefreshToken, int(time.Until(tokens.RefreshExpiresAt).Seconds()), "/", h.Cookies.Domain, h.Cookies.Secure, true)
 }
 
-// GetUserId returns the current user ID
-func GetUserId() int {
-	return UserID
+// clearTokenCookies removes both token cookies
+func (h *AuthHandler) clearTokenCookies(c *gin.Context) {
+	c.SetCookie(middleware.AccessTokenCookie, "", -1, "/", h.Cookies.Domain, h.Cookies.Secure, true)
+	c.SetCookie(refreshTokenCookie, "", -1, "/", h.Cookies.Domain, h.Cookies.Secure, true)
 }
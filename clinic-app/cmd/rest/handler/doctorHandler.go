@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/models"
 	"clinic-app/pkg/services/factory"
 	"clinic-app/pkg/usecase"
 	"net/http"
@@ -76,29 +78,29 @@ func (h *DoctorHandler) Slots(c *gin.Context) {
 		return
 	}
 
-	// Check user role to determine if the user is a doctor or admin
-	var role = GetRole()
-	check := false
-	if role == "doctor" || role == "admin" {
-		check = true // Allow specific operations based on the role
-	} else {
-		check = false
-	}
-
 	// Call usecase to get available slots for the doctor
-	slots, err := h.DocUsecase.Slots(ftx, doctorId, check)
+	slots, err := h.DocUsecase.Slots(ftx, doctorId)
 	if err != nil {
 		ftx.Logger().Error("Failed to retrieve slots for doctor", zap.Error(err))                     // Log error if retrieval fails
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve slots for doctor"}) // Return internal server error
 		return
 	}
 
-	// If no slots are available, return a specific message
+	// If nothing is booked yet, point to the free slots instead
 	if slots == nil {
-		c.JSON(http.StatusOK, gin.H{"message": "No appointments made, all slots available"})
+		c.JSON(http.StatusOK, gin.H{"message": "No appointments booked, see /doctors/" + doctorIdStr + "/free-slots for bookable times"})
 		return
 	}
 
 	// Return the available slots
+	zone := middleware.ResponseZone(c)
+	for i, slot := range slots {
+		switch slot := slot.(type) {
+		case models.SlotDoc:
+			slots[i] = slot.InZone(zone)
+		case models.SlotPat:
+			slots[i] = slot.InZone(zone)
+		}
+	}
 	c.JSON(http.StatusOK, gin.H{"slots": slots})
 }
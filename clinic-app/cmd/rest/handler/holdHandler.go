@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// HoldSlot handles holding a time for the calling patient while they complete the booking
+func (h *AppointmentHandler) HoldSlot(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Extract service from context
+
+	var aptmt models.BookAppointment
+	if err := c.ShouldBindJSON(&aptmt); err != nil { // Bind JSON input to aptmt model
+		ftx.Logger().Error("Invalid input", zap.Error(err))            // Log error if JSON binding fails
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // Return bad request error
+		return
+	}
+
+	hold, err := h.AptmtUsecase.Hold(ftx, aptmt) // Call use case to hold the time
+	if err != nil {
+		respondHoldError(c, ftx, err, "Failed to hold time")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"hold": hold.InZone(middleware.ResponseZone(c))}) // Return the hold and when it expires
+}
+
+// ConfirmHold handles turning a hold into an appointment
+func (h *AppointmentHandler) ConfirmHold(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Extract service from context
+
+	holdID, err := strconv.Atoi(c.Param("id")) // Convert hold ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid hold ID", zap.Error(err))            // Log invalid ID error
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid hold ID"}) // Return bad request error
+		return
+	}
+
+	appointmentID, err := h.AptmtUsecase.ConfirmHold(ftx, holdID) // Call use case to book the held time
+	if err != nil {
+		respondHoldError(c, ftx, err, "Booking failed")
+		return
+	}
+
+	// Return the appointment booked for the hold
+	c.JSON(http.StatusCreated, gin.H{"message": "Appointment booked successfully", "appointment_id": appointmentID})
+}
+
+// ReleaseHold handles giving a held time back before the hold expires
+func (h *AppointmentHandler) ReleaseHold(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Extract service from context
+
+	holdID, err := strconv.Atoi(c.Param("id")) // Convert hold ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid hold ID", zap.Error(err))            // Log invalid ID error
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid hold ID"}) // Return bad request error
+		return
+	}
+
+	if err := h.AptmtUsecase.ReleaseHold(ftx, holdID); err != nil { // Call use case to release the hold
+		respondHoldError(c, ftx, err, "Failed to release hold")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Hold released"}) // Return success message
+}
+
+// respondHoldError writes the response for an error of the hold use cases.
+// Holding and confirming follow the booking rules, so booking errors are passed on with their own status.
+func respondHoldError(c *gin.Context, ftx factory.Service, err error, failure string) {
+	if conflict := (*errors.ConflictError)(nil); stderrors.As(err, &conflict) {
+		respondConflict(c, conflict) // Return conflict naming the overlapping appointment
+		return
+	}
+
+	switch err {
+	case errors.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Hold does not exist"}) // Return hold not found
+
+	case errors.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": errors.ErrForbidden.Message}) // Return forbidden if the time is held for another patient
+
+	case errors.ErrDatabase:
+		ftx.Logger().Error(failure, zap.Error(err))                     // Log database error
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failure}) // Return internal server error
+
+	default:
+		if appErr, ok := err.(*errors.ClinicAppError); ok {
+			c.JSON(appErr.Code, gin.H{"error": appErr.Message}) // Return the status of the application error
+			return
+		}
+		ftx.Logger().Error(failure, zap.Error(err))                     // Log unexpected error
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failure}) // Return internal server error
+	}
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// InvitationHandler struct holds the AuthUsecase to manage staff invitations
+type InvitationHandler struct {
+	AuthUsecase usecase.AuthUsecase
+}
+
+// NewInvitationHandler initializes a new InvitationHandler with the provided usecase
+func NewInvitationHandler(uc usecase.AuthUsecase) *InvitationHandler {
+	return &InvitationHandler{
+		AuthUsecase: uc,
+	}
+}
+
+// Create handles inviting a doctor or admin
+func (h *InvitationHandler) Create(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	var invite models.NewInvitation
+	if err := c.ShouldBindJSON(&invite); err != nil {
+		ftx.Logger().Error("Invalid input", zap.Error(err))            // Log error
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // Return bad request
+		return
+	}
+
+	// Call usecase to create and mail the invitation
+	invitation, err := h.AuthUsecase.InviteStaff(ftx, invite)
+	switch err {
+	case nil:
+		c.JSON(http.StatusCreated, gin.H{"invitation": invitation.InZone(middleware.ResponseZone(c))})
+
+	case errors.ErrInvalidRole:
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.ErrInvalidRole.Message}) // Return bad request for roles that cannot be invited
+
+	case errors.ErrMailDelivery:
+		c.JSON(http.StatusBadGateway, gin.H{ // The invitation exists but nobody received it, it can be revoked and sent again
+			"error":      "Invitation created but the email could not be sent",
+			"invitation": invitation,
+		})
+
+	default:
+		ftx.Logger().Error("Invitation failed", zap.Error(err))                               // Log invitation failure
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create invitation"}) // Return internal server error
+	}
+}
+
+// ViewAll handles listing all invitations
+func (h *InvitationHandler) ViewAll(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	// Call usecase to list the invitations
+	invitations, err := h.AuthUsecase.Invitations(ftx)
+	if err != nil {
+		ftx.Logger().Error("Failed to retrieve invitations", zap.Error(err))                     // Log retrieval failure
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve invitations"}) // Return internal server error
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invitations": models.InZone(invitations, middleware.ResponseZone(c))})
+}
+
+// Events handles retrieving the audit trail of an invitation
+func (h *InvitationHandler) Events(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	invitationID, err := strconv.Atoi(c.Param("id")) // Convert invitation ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid invitation ID", zap.Error(err))            // Log invalid ID error
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invitation ID"}) // Return bad request error
+		return
+	}
+
+	// Call usecase to retrieve the events
+	events, err := h.AuthUsecase.InvitationEvents(ftx, invitationID)
+	if err != nil {
+		ftx.Logger().Error("Failed to retrieve invitation events", zap.Error(err))                     // Log retrieval failure
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve invitation events"}) // Return internal server error
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": models.InZone(events, middleware.ResponseZone(c))})
+}
+
+// Revoke handles revoking an open invitation
+func (h *InvitationHandler) Revoke(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	invitationID, err := strconv.Atoi(c.Param("id")) // Convert invitation ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid invitation ID", zap.Error(err))            // Log invalid ID error
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invitation ID"}) // Return bad request error
+		return
+	}
+
+	// Call usecase to revoke the invitation
+	err = h.AuthUsecase.RevokeInvitation(ftx, invitationID)
+	if err == errors.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No open invitation with this ID"}) // Return not found for unknown or closed invitations
+		return
+	} else if err != nil {
+		ftx.Logger().Error("Failed to revoke invitation", zap.Error(err))                     // Log revocation failure
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke invitation"}) // Return internal server error
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation revoked"})
+}
+
+// Accept handles completing a staff account from an invitation
+func (h *InvitationHandler) Accept(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	var accept models.AcceptInvitation
+	if err := c.ShouldBindJSON(&accept); err != nil {
+		ftx.Logger().Error("Invalid input", zap.Error(err))            // Log error
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // Return bad request
+		return
+	}
+
+	// Call usecase to create the account
+	userID, err := h.AuthUsecase.AcceptInvitation(ftx, accept)
+	switch err {
+	case nil:
+		ftx.Logger().Info("Invitation accepted", zap.Int("UserID", userID))
+		c.JSON(http.StatusOK, gin.H{"message": "Registration successful"})
+
+	case errors.ErrWeakPassword:
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.ErrWeakPassword.Message}) // Return password policy violation
+
+	case errors.ErrInvitationInvalid:
+		c.JSON(http.StatusGone, gin.H{"error": errors.ErrInvitationInvalid.Message}) // Return gone for unusable invitations
+
+	default:
+		ftx.Logger().Error("Invitation acceptance failed", zap.Error(err))            // Log acceptance failure
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Registration failed"}) // Return internal server error
+	}
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	stderrors "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// LoginMFA handles the second login step, exchanging a challenge token and a code for tokens
+func (h *AuthHandler) LoginMFA(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	var login models.MFALogin
+	if err := c.ShouldBindJSON(&login); err != nil {
+		ftx.Logger().Error("Invalid input", zap.Error(err))            // Log error
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // Return bad request
+		return
+	}
+
+	// Call usecase to check the code
+	user, err := h.AuthUsecase.CompleteMFALogin(ftx, login)
+	if locked := (*errors.LockedError)(nil); stderrors.As(err, &locked) {
+		abortTooManyAttempts(c, locked) // Return too many requests after repeated wrong codes
+		return
+	}
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidToken:
+			middleware.AbortUnauthorized(c, "invalid_token", "Challenge token is invalid or expired, please log in again") // Return unauthorized error
+
+		case errors.ErrInvalidMFACode, errors.ErrMFANotEnabled:
+			middleware.AbortUnauthorized(c, "invalid_grant", errors.ErrInvalidMFACode.Message) // Return unauthorized error
+
+		default:
+			ftx.Logger().Error("Two-factor login failed", zap.Error(err))              // Log login failure
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not log in"}) // Return internal server error
+		}
+		return
+	}
+
+	// Issue a short-lived access token and a refresh token
+	tokens, err := h.AuthUsecase.IssueTokens(ftx, user)
+	if err != nil {
+		ftx.Logger().Error("Failed to create JWT token", zap.Error(err))           // Log token creation failure
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not log in"}) // Return internal server error
+		return
+	}
+	h.setTokenCookies(c, tokens)
+
+	respondWithTokens(c, gin.H{"message": "Login Successful"}, tokens)
+}
+
+// EnrollMFA handles creating a TOTP secret for the caller
+func (h *AuthHandler) EnrollMFA(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	// Call usecase to create the secret
+	enrollment, err := h.AuthUsecase.EnrollMFA(ftx)
+	switch err {
+	case nil:
+		c.JSON(http.StatusOK, gin.H{
+			"message":    "Add the account to your authenticator app, then confirm a code at /mfa/activate",
+			"enrollment": enrollment,
+		})
+
+	case errors.ErrMFAAlreadyEnabled:
+		c.JSON(http.StatusConflict, gin.H{"error": errors.ErrMFAAlreadyEnabled.Message}) // Return conflict if already enabled
+
+	default:
+		ftx.Logger().Error("Two-factor enrollment failed", zap.Error(err))                                   // Log enrollment failure
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not set up two-factor authentication"}) // Return internal server error
+	}
+}
+
+// ActivateMFA handles enabling two-factor authentication with a first code.
+// Callers that came with an enrollment challenge are logged in once it succeeds.
+func (h *AuthHandler) ActivateMFA(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	var confirm models.MFACode
+	if err := c.ShouldBindJSON(&confirm); err != nil {
+		ftx.Logger().Error("Invalid input", zap.Error(err))            // Log error
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // Return bad request
+		return
+	}
+
+	// Call usecase to enable two-factor authentication
+	codes, err := h.AuthUsecase.ActivateMFA(ftx, confirm.Code)
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidMFACode, errors.ErrMFANotEnabled, errors.ErrMFAAlreadyEnabled:
+			appErr := err.(*errors.ClinicAppError)
+			c.JSON(appErr.Code, gin.H{"error": appErr.Message}) // Return the matching client error
+
+		default:
+			ftx.Logger().Error("Two-factor activation failed", zap.Error(err))                                   // Log activation failure
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not enable two-factor authentication"}) // Return internal server error
+		}
+		return
+	}
+
+	body := gin.H{
+		"message":        "Two-factor authentication enabled, store the recovery codes somewhere safe",
+		"recovery_codes": codes,
+	}
+
+	// A caller with an access token is done
+	value, fromChallenge := c.Get(middleware.ChallengeKey)
+	if !fromChallenge {
+		c.JSON(http.StatusOK, body)
+		return
+	}
+
+	// A caller with an enrollment challenge has now completed the login
+	claims := value.(*models.Claims)
+	if err := h.AuthUsecase.ConsumeChallenge(ftx, claims); err != nil {
+		ftx.Logger().Error("Failed to consume challenge token", zap.Error(err))    // Log revocation failure
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not log in"}) // Return internal server error
+		return
+	}
+	tokens, err := h.AuthUsecase.IssueTokens(ftx, models.User{ID: claims.UserID, Role: claims.Role})
+	if err != nil {
+		ftx.Logger().Error("Failed to create JWT token", zap.Error(err))           // Log token creation failure
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not log in"}) // Return internal server error
+		return
+	}
+	h.setTokenCookies(c, tokens)
+
+	respondWithTokens(c, body, tokens)
+}
+
+// RegenerateRecoveryCodes handles replacing the caller's recovery codes
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	var confirm models.MFACode
+	if err := c.ShouldBindJSON(&confirm); err != nil {
+		ftx.Logger().Error("Invalid input", zap.Error(err))            // Log error
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // Return bad request
+		return
+	}
+
+	// Call usecase to replace the codes
+	codes, err := h.AuthUsecase.RegenerateRecoveryCodes(ftx, confirm.Code)
+	switch err {
+	case nil:
+		c.JSON(http.StatusOK, gin.H{"message": "Previous recovery codes no longer work", "recovery_codes": codes})
+
+	case errors.ErrInvalidMFACode, errors.ErrMFANotEnabled:
+		appErr := err.(*errors.ClinicAppError)
+		c.JSON(appErr.Code, gin.H{"error": appErr.Message}) // Return the matching client error
+
+	default:
+		ftx.Logger().Error("Recovery code regeneration failed", zap.Error(err))                    // Log regeneration failure
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not replace recovery codes"}) // Return internal server error
+	}
+}
+
+// DisableMFA handles turning two-factor authentication off for the caller
+func (h *AuthHandler) DisableMFA(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	var confirm models.MFACode
+	if err := c.ShouldBindJSON(&confirm); err != nil {
+		ftx.Logger().Error("Invalid input", zap.Error(err))            // Log error
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // Return bad request
+		return
+	}
+
+	// Call usecase to disable two-factor authentication
+	err := h.AuthUsecase.DisableMFA(ftx, confirm.Code)
+	switch err {
+	case nil:
+		c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+
+	case errors.ErrInvalidMFACode, errors.ErrMFANotEnabled, errors.ErrMFAMandatory:
+		appErr := err.(*errors.ClinicAppError)
+		c.JSON(appErr.Code, gin.H{"error": appErr.Message}) // Return the matching client error
+
+	default:
+		ftx.Logger().Error("Disabling two-factor authentication failed", zap.Error(err))                      // Log failure
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not disable two-factor authentication"}) // Return internal server error
+	}
+}
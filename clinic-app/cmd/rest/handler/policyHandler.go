@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// BookingPolicyHandler struct holds the BookingPolicyUsecase to manage booking rules
+type BookingPolicyHandler struct {
+	PolicyUsecase usecase.BookingPolicyUsecase
+}
+
+// NewBookingPolicyHandler initializes a new BookingPolicyHandler with the provided usecase
+func NewBookingPolicyHandler(uc usecase.BookingPolicyUsecase) *BookingPolicyHandler {
+	return &BookingPolicyHandler{
+		PolicyUsecase: uc,
+	}
+}
+
+// ViewAll handles retrieving the default policy and the policies of the doctors that override it
+func (h *BookingPolicyHandler) ViewAll(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	defaultPolicy, doctorPolicies, err := h.PolicyUsecase.Policies(ftx) // Call usecase to get the policies
+	if err != nil {
+		ftx.Logger().Error("Failed to retrieve booking policies", zap.Error(err))                     // Log error if retrieval fails
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve booking policies"}) // Return internal server error
+		return
+	}
+
+	// Return the policies
+	c.JSON(http.StatusOK, gin.H{"default": defaultPolicy.InZone(middleware.ResponseZone(c)), "doctors": models.InZone(doctorPolicies, middleware.ResponseZone(c))})
+}
+
+// SetDefault handles changing the clinic-wide default policy
+func (h *BookingPolicyHandler) SetDefault(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	var policy models.BookingPolicy
+	if err := c.ShouldBindJSON(&policy); err != nil { // Bind JSON input to the policy
+		ftx.Logger().Error("Invalid input", zap.Error(err))            // Log error if JSON binding fails
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // Return bad request error
+		return
+	}
+
+	err := h.PolicyUsecase.SetDefaultPolicy(ftx, policy) // Call usecase to change the default
+	respondPolicyChange(c, ftx, err, "Default booking policy updated successfully")
+}
+
+// ViewForDoctor handles retrieving the rules that apply to a doctor
+func (h *BookingPolicyHandler) ViewForDoctor(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	doctorId, err := strconv.Atoi(c.Param("id")) // Convert doctor ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid doctor ID", zap.Error(err))            // Log error for invalid ID
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor ID"}) // Return bad request error
+		return
+	}
+
+	policy, err := h.PolicyUsecase.EffectivePolicy(ftx, doctorId) // Call usecase to get the rules
+	if err != nil {
+		ftx.Logger().Error("Failed to retrieve booking policy", zap.Error(err))                     // Log error if retrieval fails
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve booking policy"}) // Return internal server error
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"booking_policy": policy.InZone(middleware.ResponseZone(c))}) // Return the rules
+}
+
+// SetForDoctor handles setting the policy of a doctor
+func (h *BookingPolicyHandler) SetForDoctor(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	doctorId, err := strconv.Atoi(c.Param("id")) // Convert doctor ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid doctor ID", zap.Error(err))            // Log error for invalid ID
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor ID"}) // Return bad request error
+		return
+	}
+
+	var policy models.BookingPolicy
+	if err := c.ShouldBindJSON(&policy); err != nil { // Bind JSON input to the policy
+		ftx.Logger().Error("Invalid input", zap.Error(err))            // Log error if JSON binding fails
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // Return bad request error
+		return
+	}
+	policy.DoctorID = &doctorId
+
+	err = h.PolicyUsecase.SetDoctorPolicy(ftx, policy) // Call usecase to set the policy
+	respondPolicyChange(c, ftx, err, "Doctor booking policy updated successfully")
+}
+
+// ClearForDoctor handles removing the policy of a doctor
+func (h *BookingPolicyHandler) ClearForDoctor(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	doctorId, err := strconv.Atoi(c.Param("id")) // Convert doctor ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid doctor ID", zap.Error(err))            // Log error for invalid ID
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor ID"}) // Return bad request error
+		return
+	}
+
+	err = h.PolicyUsecase.ClearDoctorPolicy(ftx, doctorId) // Call usecase to remove the policy
+	respondPolicyChange(c, ftx, err, "Doctor booking policy removed, the default applies")
+}
+
+// respondPolicyChange reports the outcome of changing a policy
+func respondPolicyChange(c *gin.Context, ftx factory.Service, err error, success string) {
+	switch err {
+	case nil:
+		c.JSON(http.StatusOK, gin.H{"message": success}) // Return success message
+
+	case errors.ErrInvalidPolicy:
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.ErrInvalidPolicy.Message}) // Return bad request error
+
+	case errors.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Doctor or booking policy not found"}) // Return not found error
+
+	case errors.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": errors.ErrForbidden.Message}) // Return forbidden error
+
+	default:
+		ftx.Logger().Error("Failed to update booking policy", zap.Error(err))                     // Log error if the update fails
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update booking policy"}) // Return internal server error
+	}
+}
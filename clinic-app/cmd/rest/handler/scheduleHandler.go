@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ScheduleHandler struct holds the ScheduleUsecase to manage working hours
+type ScheduleHandler struct {
+	ScheduleUsecase usecase.ScheduleUsecase
+}
+
+// NewScheduleHandler initializes a new ScheduleHandler with the provided usecase
+func NewScheduleHandler(uc usecase.ScheduleUsecase) *ScheduleHandler {
+	return &ScheduleHandler{
+		ScheduleUsecase: uc,
+	}
+}
+
+// WorkingHours handles retrieving the working hours of a doctor
+func (h *ScheduleHandler) WorkingHours(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	// Get doctor ID from URL parameters and convert to integer
+	doctorId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		ftx.Logger().Error("Invalid doctor ID", zap.Error(err))            // Log error for invalid ID
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor ID"}) // Return bad request error
+		return
+	}
+
+	// Call usecase to get the working hours
+	hours, err := h.ScheduleUsecase.WorkingHours(ftx, doctorId)
+	if err != nil {
+		ftx.Logger().Error("Failed to retrieve working hours", zap.Error(err))                     // Log error if retrieval fails
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve working hours"}) // Return internal server error
+		return
+	}
+
+	// Return the working hours
+	c.JSON(http.StatusOK, gin.H{"working_hours": hours})
+}
+
+// SetWorkingHours handles replacing the working hours of a doctor
+func (h *ScheduleHandler) SetWorkingHours(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	// Get doctor ID from URL parameters and convert to integer
+	doctorId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		ftx.Logger().Error("Invalid doctor ID", zap.Error(err))            // Log error for invalid ID
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor ID"}) // Return bad request error
+		return
+	}
+
+	var set models.SetWorkingHours
+	if err := c.ShouldBindJSON(&set); err != nil { // Bind JSON input to the working hours model
+		ftx.Logger().Error("Invalid input", zap.Error(err))            // Log error if JSON binding fails
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // Return bad request error
+		return
+	}
+	set.DoctorID = doctorId
+
+	err = h.ScheduleUsecase.SetWorkingHours(ftx, set) // Call usecase to replace the working hours
+	switch err {
+	case nil:
+		c.JSON(http.StatusOK, gin.H{"message": "Working hours updated successfully"}) // Return success message
+
+	case errors.ErrInvalidHours:
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.ErrInvalidHours.Message}) // Return bad request error
+
+	case errors.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Doctor not found"}) // Return not found if the user is not a doctor
+
+	case errors.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": errors.ErrForbidden.Message}) // Return forbidden if doctors set someone else's hours
+
+	default:
+		ftx.Logger().Error("Failed to update working hours", zap.Error(err))                     // Log error if the update fails
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update working hours"}) // Return internal server error
+	}
+}
+
+// FreeSlots handles computing the bookable slots of a doctor between two days
+func (h *ScheduleHandler) FreeSlots(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	// Get doctor ID from URL parameters and convert to integer
+	doctorId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		ftx.Logger().Error("Invalid doctor ID", zap.Error(err))            // Log error for invalid ID
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor ID"}) // Return bad request error
+		return
+	}
+
+	var query models.FreeSlotQuery
+	if err := c.ShouldBindQuery(&query); err != nil { // Bind query parameters to the free slot query
+		ftx.Logger().Error("Invalid input", zap.Error(err))                               // Log error if binding fails
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.ErrInvalidSlotQuery.Message}) // Return bad request error
+		return
+	}
+	query.DoctorID = doctorId
+
+	// Call usecase to compute the free slots
+	slots, err := h.ScheduleUsecase.FreeSlots(ftx, query)
+	switch err {
+	case nil:
+		c.JSON(http.StatusOK, gin.H{"slots": models.InZone(slots, middleware.ResponseZone(c))}) // Return the free slots
+
+	case errors.ErrInvalidSlotQuery:
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.ErrInvalidSlotQuery.Message}) // Return bad request error
+
+	default:
+		ftx.Logger().Error("Failed to compute free slots", zap.Error(err))                     // Log error if the computation fails
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute free slots"}) // Return internal server error
+	}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// BookSeries handles booking a recurring appointment
+func (h *AppointmentHandler) BookSeries(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Extract service from context
+
+	var series models.BookSeries
+	if err := c.ShouldBindJSON(&series); err != nil { // Bind JSON input to the series model
+		ftx.Logger().Error("Invalid input", zap.Error(err))            // Log error if JSON binding fails
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // Return bad request error
+		return
+	}
+
+	booked, occurrences, err := h.AptmtUsecase.BookSeries(ftx, series) // Call use case to book the series
+	switch err {
+	case nil:
+		c.JSON(http.StatusCreated, gin.H{"series": booked.InZone(middleware.ResponseZone(c)), "occurrences": models.InZone(occurrences, middleware.ResponseZone(c))}) // Return the series and what became of every occurrence
+
+	case errors.ErrSeriesNotBooked:
+		c.JSON(http.StatusConflict, gin.H{"error": errors.ErrSeriesNotBooked.Message, "occurrences": occurrences}) // Return why the occurrences could not be booked
+
+	default:
+		respondSeriesError(c, ftx, err, "Booking failed")
+	}
+}
+
+// cancelSeries handles canceling this and the following occurrences, or every upcoming occurrence, of a series
+func (h *AppointmentHandler) cancelSeries(c *gin.Context, ftx factory.Service, cancel models.CancelAppointment, scope string) {
+	occurrences, err := h.AptmtUsecase.CancelSeries(ftx, cancel, scope) // Call use case to cancel the occurrences
+	if err != nil {
+		respondSeriesError(c, ftx, err, "Cancellation failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Series canceled", "occurrences": occurrences}) // Return what became of every occurrence
+}
+
+// rescheduleSeries handles moving this and the following occurrences, or every upcoming occurrence, of a series
+func (h *AppointmentHandler) rescheduleSeries(c *gin.Context, ftx factory.Service, reschedule models.RescheduleAppointment, scope string) {
+	occurrences, err := h.AptmtUsecase.RescheduleSeries(ftx, reschedule, scope) // Call use case to move the occurrences
+	switch err {
+	case nil:
+		c.JSON(http.StatusOK, gin.H{"message": "Series rescheduled successfully", "occurrences": occurrences}) // Return the new bookings
+
+	case errors.ErrSeriesNotMoved:
+		c.JSON(http.StatusConflict, gin.H{"error": errors.ErrSeriesNotMoved.Message, "occurrences": occurrences}) // Return why the occurrences could not be moved
+
+	default:
+		respondSeriesError(c, ftx, err, "Rescheduling failed")
+	}
+}
+
+// respondSeriesError writes the response for an error of the series use cases
+func respondSeriesError(c *gin.Context, ftx factory.Service, err error, failure string) {
+	switch err {
+	case errors.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Appointment does not exist"}) // Return appointment not found
+
+	case errors.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": errors.ErrForbidden.Message}) // Return forbidden if an occurrence belongs to someone else
+
+	case errors.ErrEmailNotVerified:
+		c.JSON(http.StatusForbidden, gin.H{"error": errors.ErrEmailNotVerified.Message}) // Return forbidden error
+
+	case errors.ErrInvalidSeries, errors.ErrInvalidScope:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.(*errors.ClinicAppError).Message}) // Return bad request error
+
+	default:
+		ftx.Logger().Error(failure, zap.Error(err))                     // Log unknown error
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failure}) // Return internal server error
+	}
+}
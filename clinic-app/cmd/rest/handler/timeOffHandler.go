@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/usecase"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// TimeOffHandler struct holds the TimeOffUsecase to manage the time off of doctors
+type TimeOffHandler struct {
+	TimeOffUsecase usecase.TimeOffUsecase
+}
+
+// NewTimeOffHandler initializes a new TimeOffHandler with the provided usecase
+func NewTimeOffHandler(uc usecase.TimeOffUsecase) *TimeOffHandler {
+	return &TimeOffHandler{
+		TimeOffUsecase: uc,
+	}
+}
+
+// Request handles requesting time off for a doctor
+func (h *TimeOffHandler) Request(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	doctorId, err := strconv.Atoi(c.Param("id")) // Convert doctor ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid doctor ID", zap.Error(err))            // Log error for invalid ID
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor ID"}) // Return bad request error
+		return
+	}
+
+	var request models.RequestTimeOff
+	if err := c.ShouldBindJSON(&request); err != nil { // Bind JSON input to the time off request
+		ftx.Logger().Error("Invalid input", zap.Error(err))            // Log error if JSON binding fails
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // Return bad request error
+		return
+	}
+	request.DoctorID = doctorId
+
+	timeOff, affected, err := h.TimeOffUsecase.Request(ftx, request) // Call usecase to request the time off
+	if err != nil {
+		respondTimeOffError(c, ftx, err, "Failed to request time off")
+		return
+	}
+
+	// Return the time off with the appointments that fall into it
+	c.JSON(http.StatusCreated, gin.H{"time_off": timeOff.InZone(middleware.ResponseZone(c)), "affected_appointments": models.InZone(affected, middleware.ResponseZone(c))})
+}
+
+// ViewForDoctor handles retrieving the time off of a doctor
+func (h *TimeOffHandler) ViewForDoctor(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	doctorId, err := strconv.Atoi(c.Param("id")) // Convert doctor ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid doctor ID", zap.Error(err))            // Log error for invalid ID
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor ID"}) // Return bad request error
+		return
+	}
+
+	timeOffs, err := h.TimeOffUsecase.DoctorTimeOff(ftx, doctorId) // Call usecase to get the time off
+	if err != nil {
+		respondTimeOffError(c, ftx, err, "Failed to retrieve time off")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"time_off": models.InZone(timeOffs, middleware.ResponseZone(c))}) // Return the time off
+}
+
+// ViewByStatus handles retrieving the time off with a status, pending requests unless asked otherwise
+func (h *TimeOffHandler) ViewByStatus(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	status := c.DefaultQuery("status", models.TimeOffPending)
+	timeOffs, err := h.TimeOffUsecase.ByStatus(ftx, status) // Call usecase to get the time off
+	if err == errors.ErrBadRequest {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"}) // Return bad request for unknown statuses
+		return
+	} else if err != nil {
+		respondTimeOffError(c, ftx, err, "Failed to retrieve time off")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"time_off": models.InZone(timeOffs, middleware.ResponseZone(c))}) // Return the time off
+}
+
+// Approve handles granting a pending time off
+func (h *TimeOffHandler) Approve(c *gin.Context) {
+	h.changeStatus(c, h.TimeOffUsecase.Approve, "Time off approved")
+}
+
+// Reject handles turning a pending time off down
+func (h *TimeOffHandler) Reject(c *gin.Context) {
+	h.changeStatus(c, h.TimeOffUsecase.Reject, "Time off rejected")
+}
+
+// Withdraw handles taking back a pending or approved time off
+func (h *TimeOffHandler) Withdraw(c *gin.Context) {
+	h.changeStatus(c, h.TimeOffUsecase.Withdraw, "Time off withdrawn")
+}
+
+// changeStatus runs a status change of the time off named in the URL and reports the outcome
+func (h *TimeOffHandler) changeStatus(c *gin.Context, change func(factory.Service, int) error, success string) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	timeOffId, err := strconv.Atoi(c.Param("id")) // Convert time off ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid time off ID", zap.Error(err))            // Log error for invalid ID
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time off ID"}) // Return bad request error
+		return
+	}
+
+	if err := change(ftx, timeOffId); err != nil {
+		respondTimeOffError(c, ftx, err, "Failed to update time off")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": success}) // Return success message
+}
+
+// Affected handles listing the upcoming appointments that fall into a time off
+func (h *TimeOffHandler) Affected(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	timeOffId, err := strconv.Atoi(c.Param("id")) // Convert time off ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid time off ID", zap.Error(err))            // Log error for invalid ID
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time off ID"}) // Return bad request error
+		return
+	}
+
+	appointments, err := h.TimeOffUsecase.AffectedAppointments(ftx, timeOffId) // Call usecase to get the appointments
+	if err != nil {
+		respondTimeOffError(c, ftx, err, "Failed to retrieve affected appointments")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"appointments": models.InZone(appointments, middleware.ResponseZone(c))}) // Return the affected appointments
+}
+
+// CancelAffected handles canceling the upcoming appointments that fall into a time off
+func (h *TimeOffHandler) CancelAffected(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	timeOffId, err := strconv.Atoi(c.Param("id")) // Convert time off ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid time off ID", zap.Error(err))            // Log error for invalid ID
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time off ID"}) // Return bad request error
+		return
+	}
+
+	var cancel models.CancelAppointment
+	if err := c.ShouldBindJSON(&cancel); err != nil && err != io.EOF { // Bind the optional JSON body holding the reason
+		ftx.Logger().Error("Invalid input", zap.Error(err))                                                      // Log error if JSON binding fails
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input, the reason can be at most 255 characters"}) // Return bad request error
+		return
+	}
+
+	outcomes, err := h.TimeOffUsecase.CancelAffected(ftx, timeOffId, cancel.Reason) // Call usecase to cancel the appointments
+	if err != nil {
+		respondTimeOffError(c, ftx, err, "Failed to cancel affected appointments")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"appointments": outcomes}) // Return the outcome for every appointment
+}
+
+// ReassignAffected handles moving the upcoming appointments that fall into a time off to another doctor
+func (h *TimeOffHandler) ReassignAffected(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	timeOffId, err := strconv.Atoi(c.Param("id")) // Convert time off ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid time off ID", zap.Error(err))            // Log error for invalid ID
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time off ID"}) // Return bad request error
+		return
+	}
+
+	var reassign models.ReassignAppointments
+	if err := c.ShouldBindJSON(&reassign); err != nil { // Bind JSON input naming the other doctor
+		ftx.Logger().Error("Invalid input", zap.Error(err))                                   // Log error if JSON binding fails
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input, doctor_id is required"}) // Return bad request error
+		return
+	}
+
+	outcomes, err := h.TimeOffUsecase.ReassignAffected(ftx, timeOffId, reassign.DoctorID) // Call usecase to move the appointments
+	if err != nil {
+		respondTimeOffError(c, ftx, err, "Failed to reassign affected appointments")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"appointments": outcomes}) // Return the outcome for every appointment
+}
+
+// respondTimeOffError maps the errors shared by the time off endpoints to a response
+func respondTimeOffError(c *gin.Context, ftx factory.Service, err error, failure string) {
+	switch err {
+	case errors.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Time off or doctor not found"}) // Return not found error
+
+	case errors.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": errors.ErrForbidden.Message}) // Return forbidden if the time off belongs to another doctor
+
+	case errors.ErrInvalidTimeOff:
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.ErrInvalidTimeOff.Message}) // Return bad request error
+
+	case errors.ErrTimeOffStatus:
+		c.JSON(http.StatusConflict, gin.H{"error": errors.ErrTimeOffStatus.Message}) // Return conflict if the time off was already decided
+
+	default:
+		ftx.Logger().Error(failure, zap.Error(err))                     // Log unexpected error
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failure}) // Return internal server error
+	}
+}
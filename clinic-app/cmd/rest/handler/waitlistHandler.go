@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/usecase"
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// WaitlistHandler struct holds the WaitlistUsecase to manage the waitlists of doctors
+type WaitlistHandler struct {
+	WaitlistUsecase usecase.WaitlistUsecase
+}
+
+// NewWaitlistHandler initializes a new WaitlistHandler with the provided usecase
+func NewWaitlistHandler(uc usecase.WaitlistUsecase) *WaitlistHandler {
+	return &WaitlistHandler{
+		WaitlistUsecase: uc,
+	}
+}
+
+// Join handles putting the calling patient on the waitlist of a doctor
+func (h *WaitlistHandler) Join(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	var request models.JoinWaitlist
+	if err := c.ShouldBindJSON(&request); err != nil { // Bind JSON input to the waitlist request
+		ftx.Logger().Error("Invalid input", zap.Error(err))            // Log error if JSON binding fails
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // Return bad request error
+		return
+	}
+
+	entry, err := h.WaitlistUsecase.Join(ftx, request) // Call usecase to join the waitlist
+	if err != nil {
+		respondWaitlistError(c, ftx, err, "Failed to join waitlist")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"waitlist_entry": entry.InZone(middleware.ResponseZone(c))}) // Return the new entry
+}
+
+// ViewMine handles retrieving the waitlist entries of the calling patient with the offers made to them
+func (h *WaitlistHandler) ViewMine(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	entries, err := h.WaitlistUsecase.MyEntries(ftx) // Call usecase to get the entries
+	if err != nil {
+		respondWaitlistError(c, ftx, err, "Failed to retrieve waitlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"waitlist": models.InZone(entries, middleware.ResponseZone(c))}) // Return the entries
+}
+
+// ViewForDoctor handles retrieving the patients waiting for a doctor
+func (h *WaitlistHandler) ViewForDoctor(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	doctorId, err := strconv.Atoi(c.Param("id")) // Convert doctor ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid doctor ID", zap.Error(err))            // Log error for invalid ID
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor ID"}) // Return bad request error
+		return
+	}
+
+	entries, err := h.WaitlistUsecase.DoctorWaitlist(ftx, doctorId) // Call usecase to get the waitlist
+	if err != nil {
+		respondWaitlistError(c, ftx, err, "Failed to retrieve waitlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"waitlist": models.InZone(entries, middleware.ResponseZone(c))}) // Return the waitlist
+}
+
+// Leave handles taking an entry off the waitlist
+func (h *WaitlistHandler) Leave(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	entryId, err := strconv.Atoi(c.Param("id")) // Convert entry ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid waitlist entry ID", zap.Error(err))            // Log error for invalid ID
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid waitlist entry ID"}) // Return bad request error
+		return
+	}
+
+	if err := h.WaitlistUsecase.Leave(ftx, entryId); err != nil { // Call usecase to leave the waitlist
+		respondWaitlistError(c, ftx, err, "Failed to leave waitlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Left the waitlist"}) // Return success message
+}
+
+// Accept handles booking the slot offered to the calling patient
+func (h *WaitlistHandler) Accept(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	offerId, err := strconv.Atoi(c.Param("id")) // Convert offer ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid offer ID", zap.Error(err))            // Log error for invalid ID
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offer ID"}) // Return bad request error
+		return
+	}
+
+	appointmentId, err := h.WaitlistUsecase.AcceptOffer(ftx, offerId) // Call usecase to book the offered slot
+	if err != nil {
+		respondWaitlistError(c, ftx, err, "Failed to accept offer")
+		return
+	}
+
+	// Return the appointment booked for the offer
+	c.JSON(http.StatusCreated, gin.H{"message": "Appointment Booked Successfully", "appointment_id": appointmentId})
+}
+
+// Decline handles turning down the slot offered to the calling patient
+func (h *WaitlistHandler) Decline(c *gin.Context) {
+	ftx := c.MustGet("ftx").(factory.Service) // Get service from context
+
+	offerId, err := strconv.Atoi(c.Param("id")) // Convert offer ID from string to integer
+	if err != nil {
+		ftx.Logger().Error("Invalid offer ID", zap.Error(err))            // Log error for invalid ID
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offer ID"}) // Return bad request error
+		return
+	}
+
+	if err := h.WaitlistUsecase.DeclineOffer(ftx, offerId); err != nil { // Call usecase to decline the offer
+		respondWaitlistError(c, ftx, err, "Failed to decline offer")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Offer declined, you stay on the waitlist"}) // Return success message
+}
+
+// respondWaitlistError writes the response for an error of the waitlist usecase.
+// Accepting an offer books an appointment, so booking errors are passed on with their own status.
+func respondWaitlistError(c *gin.Context, ftx factory.Service, err error, failure string) {
+	if conflict := (*errors.ConflictError)(nil); stderrors.As(err, &conflict) {
+		respondConflict(c, conflict) // Return conflict naming the overlapping appointment
+		return
+	}
+
+	switch err {
+	case errors.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Waitlist entry, offer or doctor not found"}) // Return not found error
+
+	case errors.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": errors.ErrForbidden.Message}) // Return forbidden if the entry belongs to another patient
+
+	case errors.ErrDatabase:
+		ftx.Logger().Error(failure, zap.Error(err))                     // Log database error
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failure}) // Return internal server error
+
+	default:
+		if appErr, ok := err.(*errors.ClinicAppError); ok {
+			c.JSON(appErr.Code, gin.H{"error": appErr.Message}) // Return the status of the application error
+			return
+		}
+		ftx.Logger().Error(failure, zap.Error(err))                     // Log unexpected error
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failure}) // Return internal server error
+	}
+}
@@ -1,61 +1,129 @@
 package middleware
 
 import (
+	"clinic-app/pkg/domain/errors"
 	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	stderrors "errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt"
 )
 
-// AuthMiddleware checks if the user is authenticated and authorized
-func AuthMiddleware(requiredRoles ...string) gin.HandlerFunc {
+// Authenticator validates access tokens and login challenge tokens, including the revocation check
+type Authenticator interface {
+	Authenticate(ftx factory.Service, accessToken string) (*models.Claims, error)
+	AuthenticateChallenge(ftx factory.Service, challengeToken, purpose string) (*models.Claims, error)
+}
+
+// ChallengeKey is the context key under which MFAEnrollmentMiddleware stores the claims of an enrollment challenge
+const ChallengeKey = "challenge"
+
+var authenticator Authenticator
+
+// AccessTokenCookie is the cookie browsers keep the access token in
+const AccessTokenCookie = "token"
+
+// authRealm is the realm announced in WWW-Authenticate challenges
+const authRealm = "clinic-app"
+
+var errMalformedAuthorization = stderrors.New("malformed Authorization header")
+
+// SetUpAuthentication sets the authenticator used by Authorize and MFAEnrollmentMiddleware
+func SetUpAuthentication(auth Authenticator) {
+	authenticator = auth
+}
+
+// MFAEnrollmentMiddleware authenticates the two-factor setup endpoints. Besides access tokens it accepts the
+// enrollment challenge handed out at login to users who must set up two-factor authentication before they get one.
+func MFAEnrollmentMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Retrieve the token from cookies
-		tokenString, err := c.Cookie("token")
-		if err != nil {
-			fmt.Println("Token missing in cookie") // Log missing token
-			c.Abort()                              // Abort the request if token is not found
+		if _, ok := authenticate(c, true); !ok {
 			return
 		}
+		c.Next() // Proceed to the next handler
+	}
+}
 
-		if tokenString == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing token"}) // Respond with unauthorized if token is empty
-			c.Abort()                                                        // Abort the request
-			return
-		}
+// authenticate validates the caller's token and scopes the request's service to the caller.
+// acceptEnrollment also accepts enrollment challenges. On failure the request is aborted and false is returned.
+func authenticate(c *gin.Context, acceptEnrollment bool) (*models.Claims, bool) {
+	ftx := c.MustGet("ftx").(factory.Service) // Extract service from context
 
-		// Parse and validate the JWT token
-		claims := &models.Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte("your_secret_key"), nil // Return the secret key for token validation
-		})
+	// Retrieve the token from the Authorization header or the cookie
+	tokenString, err := AccessTokenFromRequest(c)
+	if err == errMalformedAuthorization {
+		AbortUnauthorized(c, "invalid_request", "Authorization header must use the Bearer scheme") // Respond with unauthorized if the header cannot be used
+		return nil, false
+	}
+	if tokenString == "" {
+		AbortUnauthorized(c, "", "Missing token") // Respond with unauthorized if no token was sent
+		return nil, false
+	}
 
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"}) // Respond with unauthorized if token is invalid
-			c.Abort()                                                        // Abort the request
-			return
+	// Parse and validate the JWT token, revoked tokens are rejected as well
+	claims, err := authenticator.Authenticate(ftx, tokenString)
+	if err == errors.ErrInvalidToken && acceptEnrollment {
+		// Try the token as an enrollment challenge
+		claims, err = authenticator.AuthenticateChallenge(ftx, tokenString, models.ChallengeEnrollMFA)
+		if err == nil {
+			c.Set(ChallengeKey, claims)
 		}
+	}
+	if err == errors.ErrDatabase {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not validate token"}) // Respond with internal server error if the revocation list is unavailable
+		c.Abort()                                                                          // Abort the request
+		return nil, false
+	}
+	if err != nil {
+		AbortUnauthorized(c, "invalid_token", "Invalid token") // Respond with unauthorized if token is invalid
+		return nil, false
+	}
 
-		// Check if the user's role is one of the required roles
-		roleAuthorized := false
-		for _, role := range requiredRoles {
-			if claims.Role == role {
-				roleAuthorized = true // User role is authorized
-				break
-			}
-		}
+	// Set userID and userRole in the context for further use
+	c.Set("userID", claims.UserID)
+	c.Set("userRole", claims.Role)
 
-		if !roleAuthorized {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to access this resource"}) // Respond with forbidden if user is not authorized
-			c.Abort()                                                                                         // Abort the request
-			return
+	// Scope the request's service to the caller so usecases and repositories never rely on shared state
+	c.Set("ftx", ftx.WithPrincipal(models.Principal{
+		UserID: claims.UserID,
+		Role:   claims.Role,
+	}))
+	return claims, true
+}
+
+// AccessTokenFromRequest returns the caller's access token.
+// An Authorization header takes precedence over the token cookie, so API clients are never
+// mistaken for a browser session that happens to share the cookie jar.
+func AccessTokenFromRequest(c *gin.Context) (string, error) {
+	if header := c.GetHeader("Authorization"); header != "" {
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+			return "", errMalformedAuthorization
 		}
+		return strings.TrimSpace(token), nil
+	}
 
-		// Set userID and userRole in the context for further use
-		c.Set("userID", claims.UserID)
-		c.Set("userRole", claims.Role)
-		c.Next() // Proceed to the next handler
+	token, err := c.Cookie(AccessTokenCookie)
+	if err != nil {
+		return "", nil
+	}
+	return token, nil
+}
+
+// AbortUnauthorized stops the request with a 401 JSON body and a matching WWW-Authenticate header.
+// code is the RFC 6750 error code and is left empty when the request carried no credentials at all.
+func AbortUnauthorized(c *gin.Context, code, description string) {
+	challenge := fmt.Sprintf(`Bearer realm=%q`, authRealm)
+	if code != "" {
+		challenge += fmt.Sprintf(`, error=%q, error_description=%q`, code, description)
+	}
+	c.Header("WWW-Authenticate", challenge)
+
+	if code == "" {
+		code = "missing_token"
 	}
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": description, "code": code})
 }
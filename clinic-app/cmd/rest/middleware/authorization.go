@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"clinic-app/pkg/services/authz"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Authorizer decides which roles may perform an action
+type Authorizer interface {
+	Permits(role string, action authz.Action) bool
+}
+
+var authorizer Authorizer
+
+// SetUpAuthorization sets the authorizer used by Authorize
+func SetUpAuthorization(auth Authorizer) {
+	authorizer = auth
+}
+
+// Authorize authenticates the caller and checks that their role may perform the action.
+// Whether the caller may perform it on the requested resource is decided by the usecase.
+func Authorize(action authz.Action) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := authenticate(c, false)
+		if !ok {
+			return
+		}
+
+		// Check if the user's role is granted the action
+		if !authorizer.Permits(claims.Role, action) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to access this resource"}) // Respond with forbidden if user is not authorized
+			c.Abort()                                                                                         // Abort the request
+			return
+		}
+
+		c.Next() // Proceed to the next handler
+	}
+}
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"bytes"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// IdempotencyKeyHeader is the header clients send to make a request safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyStore claims keys and keeps the responses replayed to retries
+type IdempotencyStore interface {
+	Begin(ftx factory.Service, request models.IdempotentRequest) (*models.IdempotentResponse, error)
+	Complete(ftx factory.Service, request models.IdempotentRequest, response models.IdempotentResponse) error
+	Abandon(ftx factory.Service, request models.IdempotentRequest) error
+}
+
+var idempotencyStore IdempotencyStore
+
+// SetUpIdempotency sets the store used by Idempotent
+func SetUpIdempotency(store IdempotencyStore) {
+	idempotencyStore = store
+}
+
+// Idempotent replays the first response to a request carrying an Idempotency-Key header when a client retries it.
+// Keys are scoped to the caller, so it goes after Authorize on authenticated routes. A key reused with
+// a different method, path or body is rejected. Anonymous callers cannot be told apart, so their keys are scoped
+// to the request itself and only an identical retry is replayed. Requests without the header run as usual.
+func Idempotent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next() // Proceed without idempotency
+			return
+		}
+		if len(key) > 255 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key can be at most 255 characters"}) // Respond with bad request if the key cannot be stored
+			return
+		}
+
+		ftx := c.MustGet("ftx").(factory.Service) // Extract service from context
+
+		// Read the body to fingerprint the request and put it back for the handler
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Could not read request body"}) // Respond with bad request if the body cannot be read
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		request := models.IdempotentRequest{
+			UserID:      ftx.Principal().UserID,
+			Key:         key,
+			RequestHash: requestHash(c.Request.Method, c.Request.URL.RequestURI(), body),
+		}
+		if !ftx.Principal().IsAuthenticated() {
+			request.Key = anonymousKey(request.RequestHash, key) // Keep anonymous callers sending the same key apart
+		}
+
+		stored, err := idempotencyStore.Begin(ftx, request)
+		switch {
+		case err == errors.ErrIdempotencyReuse:
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": errors.ErrIdempotencyReuse.Message}) // Respond with unprocessable entity if the key belongs to another request
+			return
+		case err == errors.ErrRequestInProgress:
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": errors.ErrRequestInProgress.Message}) // Respond with conflict while the first request is running
+			return
+		case err != nil:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not check Idempotency-Key"}) // Respond with internal server error if the store is unavailable
+			return
+		case stored != nil:
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(stored.StatusCode, stored.ContentType, stored.Body) // Replay the first response
+			c.Abort()
+			return
+		}
+
+		// Run the request and capture its response
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// Server errors are not replayed, a retry should get another chance
+		if recorder.Status() >= http.StatusInternalServerError {
+			if err := idempotencyStore.Abandon(ftx, request); err != nil {
+				ftx.Logger().Error("Could not release idempotency key", zap.Error(err))
+			}
+			return
+		}
+
+		err = idempotencyStore.Complete(ftx, request, models.IdempotentResponse{
+			StatusCode:  recorder.Status(),
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		})
+		if err != nil {
+			ftx.Logger().Error("Could not store idempotent response", zap.Error(err))
+		}
+	}
+}
+
+// requestHash fingerprints a request including its query, so a retry can be told apart from a reused key
+func requestHash(method, uri string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + uri + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// anonymousKey scopes the key of an anonymous request to the request, it fits the stored key column
+func anonymousKey(requestHash, key string) string {
+	hash := sha256.Sum256([]byte(requestHash + "\n" + key))
+	return hex.EncodeToString(hash[:])
+}
+
+// responseRecorder passes the response on to the client while keeping a copy of the body
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write copies the body before writing it to the client
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString copies the body before writing it to the client
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// storedKey is the primary key of the stored idempotency keys
+type storedKey struct {
+	userId int
+	key    string
+}
+
+// memoryEntry is a claimed key, the response is nil while its request is running
+type memoryEntry struct {
+	requestHash string
+	response    *models.IdempotentResponse
+}
+
+// fakeStore keeps idempotency keys in memory the way the database does
+type fakeStore struct {
+	entries map[storedKey]*memoryEntry
+}
+
+func (s *fakeStore) Begin(ftx factory.Service, request models.IdempotentRequest) (*models.IdempotentResponse, error) {
+	entry, ok := s.entries[storedKey{request.UserID, request.Key}]
+	if !ok {
+		s.entries[storedKey{request.UserID, request.Key}] = &memoryEntry{requestHash: request.RequestHash}
+		return nil, nil
+	}
+	if entry.requestHash != request.RequestHash {
+		return nil, errors.ErrIdempotencyReuse
+	}
+	if entry.response == nil {
+		return nil, errors.ErrRequestInProgress
+	}
+	return entry.response, nil
+}
+
+func (s *fakeStore) Complete(ftx factory.Service, request models.IdempotentRequest, response models.IdempotentResponse) error {
+	s.entries[storedKey{request.UserID, request.Key}].response = &response
+	return nil
+}
+
+func (s *fakeStore) Abandon(ftx factory.Service, request models.IdempotentRequest) error {
+	delete(s.entries, storedKey{request.UserID, request.Key})
+	return nil
+}
+
+// idempotentRouter serves a route behind Idempotent for the caller and counts how often its handler runs
+func idempotentRouter(t *testing.T, caller models.Principal, handled *int) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	SetUpIdempotency(&fakeStore{entries: map[storedKey]*memoryEntry{}})
+
+	router := gin.New()
+	router.POST("/register", func(c *gin.Context) {
+		ftx, err := factory.NewFactory(nil, context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Set("ftx", ftx.WithPrincipal(caller))
+	}, Idempotent(), func(c *gin.Context) {
+		*handled++
+		body, _ := io.ReadAll(c.Request.Body)
+		c.Data(http.StatusCreated, "application/json", body)
+	})
+	return router
+}
+
+func send(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(body))
+	req.Header.Set(IdempotencyKeyHeader, key)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotentKeepsAnonymousCallersApart(t *testing.T) {
+	handled := 0
+	router := idempotentRouter(t, models.Principal{}, &handled)
+
+	// Two people registering with the same key are different requests
+	first := send(router, "retry-1", `{"email":"ann@test.local"}`)
+	second := send(router, "retry-1", `{"email":"bob@test.local"}`)
+	if first.Code != http.StatusCreated || second.Code != http.StatusCreated {
+		t.Fatalf("status = %d and %d, want both %d", first.Code, second.Code, http.StatusCreated)
+	}
+	if second.Body.String() != `{"email":"bob@test.local"}` {
+		t.Errorf("second caller got %s", second.Body.String())
+	}
+
+	// A retry of the same registration is replayed
+	retry := send(router, "retry-1", `{"email":"ann@test.local"}`)
+	if retry.Header().Get("Idempotent-Replayed") != "true" || retry.Body.String() != `{"email":"ann@test.local"}` {
+		t.Errorf("retry was not replayed: %s", retry.Body.String())
+	}
+	if handled != 2 {
+		t.Errorf("handler ran %d times, want 2", handled)
+	}
+}
+
+func TestIdempotentRejectsReusedKeyOfCaller(t *testing.T) {
+	handled := 0
+	router := idempotentRouter(t, models.Principal{UserID: 10, Role: "patient"}, &handled)
+
+	send(router, "retry-1", `{"start_time":"09:00"}`)
+	reused := send(router, "retry-1", `{"start_time":"10:00"}`)
+	if reused.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want %d", reused.Code, http.StatusUnprocessableEntity)
+	}
+	if handled != 1 {
+		t.Errorf("handler ran %d times, want 1", handled)
+	}
+}
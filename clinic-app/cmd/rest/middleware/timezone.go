@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/clinictime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimeZoneHeader is the header clients send to get the times of a response in their own IANA timezone
+const TimeZoneHeader = "Time-Zone"
+
+// zoneKey is the context key of the zone responses are rendered in
+const zoneKey = "zone"
+
+// TimeZone resolves the timezone of the Time-Zone header for the handlers, which render the times of their responses
+// in it with ResponseZone. Without the header responses are given in the clinic's timezone.
+func TimeZone() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		zone := c.GetHeader(TimeZoneHeader)
+		if zone == "" {
+			c.Header(TimeZoneHeader, clinictime.Location().String())
+			c.Next()
+			return
+		}
+
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Time-Zone must be an IANA timezone such as Europe/Berlin"}) // Respond with bad request for unknown zones
+			return
+		}
+		c.Header(TimeZoneHeader, loc.String())
+		c.Set(zoneKey, models.Zone(func(t time.Time) time.Time { return t.In(loc) }))
+		c.Next()
+	}
+}
+
+// ResponseZone returns the zone the caller asked for with the Time-Zone header, or the clinic's
+func ResponseZone(c *gin.Context) models.Zone {
+	if zone, ok := c.Get(zoneKey); ok {
+		return zone.(models.Zone)
+	}
+	return clinictime.In
+}
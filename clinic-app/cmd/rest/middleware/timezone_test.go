@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/clinictime"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// zoneRouter serves a free slot at 08:00 UTC on 2024-07-01 behind TimeZone
+func zoneRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(TimeZone())
+	router.GET("/slots", func(c *gin.Context) {
+		start := time.Date(2024, time.July, 1, 8, 0, 0, 0, time.UTC)
+		slot := models.FreeSlot{
+			DoctorID:  1,
+			Date:      time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC),
+			StartTime: start,
+			EndTime:   start.Add(30 * time.Minute),
+		}
+		c.JSON(http.StatusOK, slot.InZone(ResponseZone(c)))
+	})
+	return router
+}
+
+func TestTimeZone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clinictime.SetUpLocation(newYork)
+	defer clinictime.SetUpLocation(time.UTC)
+
+	tests := []struct {
+		name     string
+		zone     string
+		status   int
+		wantZone string
+		wantBody string
+	}{
+		{"clinic zone without the header", "", http.StatusOK, "America/New_York",
+			`{"doctor_id":1,"appointment_date":"2024-07-01T00:00:00Z","start_time":"2024-07-01T04:00:00-04:00","end_time":"2024-07-01T04:30:00-04:00"}`},
+		{"zone of the caller", "Asia/Tokyo", http.StatusOK, "Asia/Tokyo",
+			`{"doctor_id":1,"appointment_date":"2024-07-01T00:00:00Z","start_time":"2024-07-01T17:00:00+09:00","end_time":"2024-07-01T17:30:00+09:00"}`},
+		{"unknown zone", "Mars/Olympus_Mons", http.StatusBadRequest, "",
+			`{"error":"Time-Zone must be an IANA timezone such as Europe/Berlin"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/slots", nil)
+			if tt.zone != "" {
+				req.Header.Set(TimeZoneHeader, tt.zone)
+			}
+			w := httptest.NewRecorder()
+			zoneRouter().ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if zone := w.Header().Get(TimeZoneHeader); zone != tt.wantZone {
+				t.Errorf("%s = %q, want %q", TimeZoneHeader, zone, tt.wantZone)
+			}
+			if w.Body.String() != tt.wantBody {
+				t.Errorf("body = %s, want %s", w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
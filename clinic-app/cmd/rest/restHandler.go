@@ -3,6 +3,7 @@ package rest
 import (
 	"clinic-app/cmd/rest/handler"
 	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/services/authz"
 	"clinic-app/pkg/usecase"
 
 	"github.com/gin-gonic/gin"
@@ -21,6 +22,12 @@ type restHandler struct {
 	appointmentHandler *handler.AppointmentHandler
 	doctorHandler      *handler.DoctorHandler
 	adminHandler       *handler.AdminHandler
+	invitationHandler  *handler.InvitationHandler
+	scheduleHandler    *handler.ScheduleHandler
+	timeOffHandler     *handler.TimeOffHandler
+	policyHandler      *handler.BookingPolicyHandler
+	waitlistHandler    *handler.WaitlistHandler
+	aptmtTypeHandler   *handler.AppointmentTypeHandler
 }
 
 // NewRestHandler creates a new instance of restHandler with the provided use cases
@@ -29,12 +36,24 @@ func NewRestHandler(
 	aptmtUc usecase.AppointmentUsecase,
 	docUc usecase.DoctorUsecase,
 	adminUc usecase.AdminUsecase,
+	scheduleUc usecase.ScheduleUsecase,
+	timeOffUc usecase.TimeOffUsecase,
+	policyUc usecase.BookingPolicyUsecase,
+	waitlistUc usecase.WaitlistUsecase,
+	aptmtTypeUc usecase.AppointmentTypeUsecase,
+	cookies handler.CookieOptions,
 ) RestHandler {
 	return &restHandler{
-		authHandler:        handler.NewAuthHandler(authUc),
+		authHandler:        handler.NewAuthHandler(authUc, cookies),
 		appointmentHandler: handler.NewAppointmentHandler(aptmtUc),
 		doctorHandler:      handler.NewDoctorHandler(docUc),
 		adminHandler:       handler.NewAdminHandler(adminUc),
+		invitationHandler:  handler.NewInvitationHandler(authUc),
+		scheduleHandler:    handler.NewScheduleHandler(scheduleUc),
+		timeOffHandler:     handler.NewTimeOffHandler(timeOffUc),
+		policyHandler:      handler.NewBookingPolicyHandler(policyUc),
+		waitlistHandler:    handler.NewWaitlistHandler(waitlistUc),
+		aptmtTypeHandler:   handler.NewAppointmentTypeHandler(aptmtTypeUc),
 	}
 }
 
@@ -43,64 +62,303 @@ func (h *restHandler) RegisterRoutes(router *gin.Engine) {
 	// Authentication Routes
 	authRoutes := router.Group("/")
 	{
-		authRoutes.POST("/register", h.authHandler.Register) // Register new user
-		authRoutes.GET("/login", h.authHandler.Login)        // User login
+		authRoutes.POST("/register", middleware.Idempotent(), h.authHandler.Register) // Register new user, safe to retry with an Idempotency-Key
+		authRoutes.GET("/login", h.authHandler.Login)                                 // User login
+		authRoutes.POST("/logout", h.authHandler.Logout)                              // Revoke the caller's tokens
+		authRoutes.POST("/token/refresh", h.authHandler.Refresh)                      // Exchange a refresh token for a new token pair
+		authRoutes.GET("/.well-known/jwks.json", h.authHandler.JWKS)                  // Public keys for verifying our tokens
+		authRoutes.POST("/email/verify", h.authHandler.VerifyEmail)                   // Confirm an email address
+		authRoutes.POST("/password/forgot", h.authHandler.ForgotPassword)             // Mail a password reset link
+		authRoutes.POST("/password/reset", h.authHandler.ResetPassword)               // Choose a new password
+		authRoutes.POST("/login/mfa", h.authHandler.LoginMFA)                         // Second login step with a TOTP or recovery code
+
+		authRoutes.POST("/email/verify/resend",
+			middleware.Authorize(authz.AccountManage), // Allow every role to manage their own account
+			h.authHandler.ResendVerification)          // Mail a new verification link
+	}
+
+	// Two-Factor Authentication Routes
+	mfaRoutes := router.Group("/mfa")
+	{
+		mfaRoutes.POST("/enroll",
+			middleware.MFAEnrollmentMiddleware(), // Accept access tokens and enrollment challenges
+			h.authHandler.EnrollMFA)              // Create a TOTP secret
+
+		mfaRoutes.POST("/activate",
+			middleware.MFAEnrollmentMiddleware(), // Accept access tokens and enrollment challenges
+			h.authHandler.ActivateMFA)            // Confirm the first code and enable two-factor authentication
+
+		mfaRoutes.POST("/recovery-codes",
+			middleware.Authorize(authz.AccountManage), // Allow every role to manage their own account
+			h.authHandler.RegenerateRecoveryCodes)     // Replace the recovery codes
+
+		mfaRoutes.DELETE("/",
+			middleware.Authorize(authz.AccountManage), // Allow every role to manage their own account
+			h.authHandler.DisableMFA)                  // Turn two-factor authentication off
+	}
+
+	// Lockout Routes
+	lockoutRoutes := router.Group("/lockouts")
+	{
+		lockoutRoutes.DELETE("/users/:username",
+			middleware.Authorize(authz.LockoutManage), // Allow admins to lift lockouts
+			h.authHandler.Unlock)                      // Lift the lockout of a username
+
+		lockoutRoutes.DELETE("/ips/:ip",
+			middleware.Authorize(authz.LockoutManage), // Allow admins to lift lockouts
+			h.authHandler.Unlock)                      // Lift the lockout of a client IP
+	}
+
+	// Invitation Routes
+	invitationRoutes := router.Group("/invitations")
+	{
+		invitationRoutes.POST("/",
+			middleware.Authorize(authz.InvitationManage), // Allow admins to manage invitations
+			h.invitationHandler.Create)                   // Invite a doctor or admin
+
+		invitationRoutes.GET("/",
+			middleware.Authorize(authz.InvitationManage), // Allow admins to manage invitations
+			h.invitationHandler.ViewAll)                  // List all invitations
+
+		invitationRoutes.GET("/:id/events",
+			middleware.Authorize(authz.InvitationManage), // Allow admins to manage invitations
+			h.invitationHandler.Events)                   // View the audit trail of an invitation
+
+		invitationRoutes.DELETE("/:id",
+			middleware.Authorize(authz.InvitationManage), // Allow admins to manage invitations
+			h.invitationHandler.Revoke)                   // Revoke an open invitation
+
+		invitationRoutes.POST("/accept", h.invitationHandler.Accept) // Complete a staff account from an invitation
 	}
 
 	// Appointment Routes
 	appointmentRoutes := router.Group("/appointment")
 	{
 		appointmentRoutes.POST("/",
-			middleware.AuthMiddleware("patient"), // Apply Authentication Middleware for patient role
-			h.appointmentHandler.Book)            // Book an appointment
+			middleware.Authorize(authz.AppointmentBook), // Allow patients to book
+			middleware.Idempotent(),                     // Replay the response when a client retries with the same Idempotency-Key
+			h.appointmentHandler.Book)                   // Book an appointment
+
+		appointmentRoutes.POST("/series",
+			middleware.Authorize(authz.AppointmentBook), // Allow patients to book
+			middleware.Idempotent(),                     // Replay the response when a client retries with the same Idempotency-Key
+			h.appointmentHandler.BookSeries)             // Book a weekly or bi-weekly recurring appointment
+
+		appointmentRoutes.POST("/holds",
+			middleware.Authorize(authz.AppointmentBook), // Allow patients to hold a time
+			middleware.Idempotent(),                     // Replay the response when a client retries with the same Idempotency-Key
+			h.appointmentHandler.HoldSlot)               // Hold a time while the booking is completed
+
+		appointmentRoutes.POST("/holds/:id/confirm",
+			middleware.Authorize(authz.HoldManage), // Allow the patient the time is held for
+			middleware.Idempotent(),                // Replay the response when a client retries with the same Idempotency-Key
+			h.appointmentHandler.ConfirmHold)       // Book the held time
+
+		appointmentRoutes.DELETE("/holds/:id",
+			middleware.Authorize(authz.HoldManage), // Allow the patient the time is held for
+			h.appointmentHandler.ReleaseHold)       // Give the held time back
 
 		appointmentRoutes.GET("/:id",
-			middleware.AuthMiddleware("doctor", "patient"), // Apply Authentication Middleware for doctor and patient roles
-			h.appointmentHandler.View)                      // View appointment details
+			middleware.Authorize(authz.AppointmentRead), // Allow the patient and doctor of the appointment
+			h.appointmentHandler.View)                   // View appointment details
 
 		appointmentRoutes.GET("/:id/history",
-			middleware.AuthMiddleware("doctor"),          // Apply Authentication Middleware for doctor role
-			h.appointmentHandler.PatientHistoryForDoctor) // View patient history for doctor
+			middleware.Authorize(authz.PatientHistoryRead), // Allow doctors who treated the patient
+			h.appointmentHandler.PatientHistoryForDoctor)   // View patient history for doctor
 
 		appointmentRoutes.GET("/history",
-			middleware.AuthMiddleware("patient"), // Apply Authentication Middleware for patient role
-			h.appointmentHandler.PatientHistory)  // View patient’s own appointment history
+			middleware.Authorize(authz.PatientHistoryRead), // Allow patients to read their own history
+			h.appointmentHandler.PatientHistory)            // View patient’s own appointment history
 
 		appointmentRoutes.DELETE("/:id",
-			middleware.AuthMiddleware("doctor", "admin"), // Apply Authentication Middleware for doctor and admin roles
-			h.appointmentHandler.Cancel)                  // Cancel an appointment
+			middleware.Authorize(authz.AppointmentCancel), // Allow the patient and doctor of the appointment and admins
+			middleware.Idempotent(),                       // Replay the response when a client retries with the same Idempotency-Key
+			h.appointmentHandler.Cancel)                   // Cancel an appointment, ?scope=following or all for the rest of its series
+
+		appointmentRoutes.PUT("/:id/reschedule",
+			middleware.Authorize(authz.AppointmentReschedule), // Allow the patient and doctor of the appointment and admins
+			middleware.Idempotent(),                           // Replay the response when a client retries with the same Idempotency-Key
+			h.appointmentHandler.Reschedule)                   // Move an appointment to a new time, ?scope=following or all for the rest of its series
+
+		appointmentRoutes.POST("/:id/check-in",
+			middleware.Authorize(authz.AppointmentCheckIn), // Allow the doctor of the appointment and admins
+			h.appointmentHandler.CheckIn)                   // Check the patient in
+
+		appointmentRoutes.POST("/:id/start",
+			middleware.Authorize(authz.AppointmentStart), // Allow the doctor of the appointment
+			h.appointmentHandler.Start)                   // Start the consultation
+
+		appointmentRoutes.POST("/:id/complete",
+			middleware.Authorize(authz.AppointmentComplete), // Allow the doctor of the appointment
+			h.appointmentHandler.Complete)                   // Finish the consultation
+
+		appointmentRoutes.POST("/:id/no-show",
+			middleware.Authorize(authz.AppointmentNoShow), // Allow the doctor of the appointment and admins
+			h.appointmentHandler.NoShow)                   // Record that the patient did not come
 	}
 
 	// Doctor Routes
 	doctorRoutes := router.Group("/doctors")
 	{
 		doctorRoutes.GET("/",
-			middleware.AuthMiddleware("patient", "doctor", "admin"), // Apply Authentication Middleware for patient, doctor, and admin roles
-			h.doctorHandler.ViewAll)                                 // View all doctors
+			middleware.Authorize(authz.DoctorRead), // Allow every role to view doctors
+			h.doctorHandler.ViewAll)                // View all doctors
 
 		doctorRoutes.GET("/:id",
-			middleware.AuthMiddleware("patient", "doctor", "admin"), // Apply Authentication Middleware for patient, doctor, and admin roles
-			h.doctorHandler.ViewById)                                // View a specific doctor by ID
+			middleware.Authorize(authz.DoctorRead), // Allow every role to view doctors
+			h.doctorHandler.ViewById)               // View a specific doctor by ID
 
 		doctorRoutes.GET("/:id/slots",
-			middleware.AuthMiddleware("patient", "doctor", "admin"), // Apply Authentication Middleware for patient, doctor, and admin roles
-			h.doctorHandler.Slots) // View available slots for a doctor
+			middleware.Authorize(authz.SlotRead), // Allow every role to view slots
+			h.doctorHandler.Slots)                // View available slots for a doctor
+
+		doctorRoutes.GET("/:id/free-slots",
+			middleware.Authorize(authz.SlotRead), // Allow every role to view slots
+			h.scheduleHandler.FreeSlots)          // Compute the bookable slots of a doctor between two days
+
+		doctorRoutes.GET("/:id/working-hours",
+			middleware.Authorize(authz.DoctorRead), // Allow every role to view doctors
+			h.scheduleHandler.WorkingHours)         // View the weekly working hours of a doctor
+
+		doctorRoutes.PUT("/:id/working-hours",
+			middleware.Authorize(authz.WorkingHoursManage), // Allow doctors for themselves and admins
+			h.scheduleHandler.SetWorkingHours)              // Replace the weekly working hours of a doctor
+
+		doctorRoutes.POST("/:id/time-off",
+			middleware.Authorize(authz.TimeOffRequest), // Allow doctors for themselves and admins
+			h.timeOffHandler.Request)                   // Request time off and list the appointments it affects
+
+		doctorRoutes.GET("/:id/time-off",
+			middleware.Authorize(authz.TimeOffRead), // Allow doctors for themselves and admins
+			h.timeOffHandler.ViewForDoctor)          // View the time off of a doctor
+
+		doctorRoutes.GET("/:id/booking-policy",
+			middleware.Authorize(authz.DoctorRead), // Allow every role to view doctors
+			h.policyHandler.ViewForDoctor)          // View the booking rules that apply to a doctor
+
+		doctorRoutes.PUT("/:id/booking-policy",
+			middleware.Authorize(authz.BookingPolicyManage), // Allow admins to manage booking policies
+			h.policyHandler.SetForDoctor)                    // Override the default booking rules for a doctor
+
+		doctorRoutes.DELETE("/:id/booking-policy",
+			middleware.Authorize(authz.BookingPolicyManage), // Allow admins to manage booking policies
+			h.policyHandler.ClearForDoctor)                  // Let the default booking rules apply to a doctor again
+
+		doctorRoutes.GET("/:id/waitlist",
+			middleware.Authorize(authz.WaitlistRead), // Allow doctors for themselves and admins
+			h.waitlistHandler.ViewForDoctor)          // View the patients waiting for a doctor
+
+		doctorRoutes.GET("/:id/appointment-types",
+			middleware.Authorize(authz.AppointmentTypeRead), // Allow all roles
+			h.aptmtTypeHandler.ViewForDoctor)                // View the appointment types a doctor can be booked for
+
+		doctorRoutes.PUT("/:id/appointment-types",
+			middleware.Authorize(authz.DoctorTypesManage), // Allow doctors for themselves and admins
+			h.aptmtTypeHandler.SetForDoctor)               // Choose the appointment types a doctor offers
+	}
+
+	// Waitlist Routes
+	waitlistRoutes := router.Group("/waitlist")
+	{
+		waitlistRoutes.POST("/",
+			middleware.Authorize(authz.WaitlistJoin), // Allow patients to join waitlists
+			h.waitlistHandler.Join)                   // Wait for a doctor between two days
+
+		waitlistRoutes.GET("/",
+			middleware.Authorize(authz.WaitlistJoin), // Allow patients to view their waitlist entries
+			h.waitlistHandler.ViewMine)               // View your waitlist entries and the slots offered to them
+
+		waitlistRoutes.DELETE("/:id",
+			middleware.Authorize(authz.WaitlistLeave), // Allow the patient of the entry and admins
+			h.waitlistHandler.Leave)                   // Leave the waitlist
+
+		waitlistRoutes.POST("/offers/:id/accept",
+			middleware.Authorize(authz.WaitlistRespond), // Allow the patient the slot was offered to
+			h.waitlistHandler.Accept)                    // Book the offered slot
+
+		waitlistRoutes.POST("/offers/:id/decline",
+			middleware.Authorize(authz.WaitlistRespond), // Allow the patient the slot was offered to
+			h.waitlistHandler.Decline)                   // Pass the offered slot on to the next patient
+	}
+
+	// Appointment Type Routes
+	aptmtTypeRoutes := router.Group("/appointment-types")
+	{
+		aptmtTypeRoutes.GET("/",
+			middleware.Authorize(authz.AppointmentTypeRead), // Allow all roles
+			h.aptmtTypeHandler.ViewAll)                      // View the appointment types, admins also see inactive ones
+
+		aptmtTypeRoutes.POST("/",
+			middleware.Authorize(authz.AppointmentTypeManage), // Allow admins to manage appointment types
+			h.aptmtTypeHandler.Create)                         // Add an appointment type
+
+		aptmtTypeRoutes.PUT("/:id",
+			middleware.Authorize(authz.AppointmentTypeManage), // Allow admins to manage appointment types
+			h.aptmtTypeHandler.Update)                         // Change an appointment type
+
+		aptmtTypeRoutes.DELETE("/:id",
+			middleware.Authorize(authz.AppointmentTypeManage), // Allow admins to manage appointment types
+			h.aptmtTypeHandler.Deactivate)                     // Stop an appointment type from being booked
+	}
+
+	// Booking Policy Routes
+	policyRoutes := router.Group("/booking-policies")
+	{
+		policyRoutes.GET("/",
+			middleware.Authorize(authz.BookingPolicyManage), // Allow admins to manage booking policies
+			h.policyHandler.ViewAll)                         // View the default and every doctor override
+
+		policyRoutes.PUT("/default",
+			middleware.Authorize(authz.BookingPolicyManage), // Allow admins to manage booking policies
+			h.policyHandler.SetDefault)                      // Change the clinic-wide default booking rules
+	}
+
+	// Time Off Routes
+	timeOffRoutes := router.Group("/time-off")
+	{
+		timeOffRoutes.GET("/",
+			middleware.Authorize(authz.TimeOffApprove), // Allow admins to decide on time off
+			h.timeOffHandler.ViewByStatus)              // View time off by status, pending requests by default
+
+		timeOffRoutes.POST("/:id/approve",
+			middleware.Authorize(authz.TimeOffApprove), // Allow admins to decide on time off
+			h.timeOffHandler.Approve)                   // Grant a time off
+
+		timeOffRoutes.POST("/:id/reject",
+			middleware.Authorize(authz.TimeOffApprove), // Allow admins to decide on time off
+			h.timeOffHandler.Reject)                    // Turn a time off down
+
+		timeOffRoutes.DELETE("/:id",
+			middleware.Authorize(authz.TimeOffManage), // Allow the doctor of the time off and admins
+			h.timeOffHandler.Withdraw)                 // Withdraw a time off
+
+		timeOffRoutes.GET("/:id/appointments",
+			middleware.Authorize(authz.TimeOffManage), // Allow the doctor of the time off and admins
+			h.timeOffHandler.Affected)                 // View the appointments that fall into a time off
+
+		timeOffRoutes.POST("/:id/appointments/cancel",
+			middleware.Authorize(authz.TimeOffManage), // Allow the doctor of the time off and admins
+			h.timeOffHandler.CancelAffected)           // Cancel the appointments that fall into a time off
+
+		timeOffRoutes.POST("/:id/appointments/reassign",
+			middleware.Authorize(authz.TimeOffManage), // Allow the doctor of the time off and admins
+			h.timeOffHandler.ReassignAffected)         // Move the appointments that fall into a time off to another doctor
 	}
 
 	// Admin Routes
 	adminRoutes := router.Group("/")
 	{
 		adminRoutes.GET("/doctors-availability",
-			middleware.AuthMiddleware("admin"), // Apply Authentication Middleware for admin role
-			h.adminHandler.Availability)        // View doctor availability
+			middleware.Authorize(authz.ReportRead), // Allow admins to view reports
+			h.adminHandler.Availability)            // View doctor availability
 
 		adminRoutes.GET("/doctors-most-appointments",
-			middleware.AuthMiddleware("admin"), // Apply Authentication Middleware for admin role
-			h.adminHandler.MostAppointments)    // View doctors with the most appointments
+			middleware.Authorize(authz.ReportRead), // Allow admins to view reports
+			h.adminHandler.MostAppointments)        // View doctors with the most appointments
 
 		adminRoutes.GET("/doctors-over-6-hours",
-			middleware.AuthMiddleware("admin"), // Apply Authentication Middleware for admin role
-			h.adminHandler.OverSixHours)        // View doctors with over 6 hours of appointments
+			middleware.Authorize(authz.ReportRead), // Allow admins to view reports
+			h.adminHandler.OverSixHours)            // View doctors with over 6 hours of appointments
 	}
 }
 
@@ -109,6 +367,7 @@ func (h *restHandler) SetupRouter(logger *zap.Logger) *gin.Engine {
 	router := gin.Default() // Create a new Gin router
 	gin.SetMode(gin.ReleaseMode)
 	router.Use(middleware.TraceMiddleware(logger)) // Apply Trace Middleware for logging
+	router.Use(middleware.TimeZone())              // Resolve the timezone response times are rendered in
 	h.RegisterRoutes(router)                       // Register all routes
 	return router
 }
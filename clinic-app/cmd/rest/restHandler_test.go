@@ -0,0 +1,197 @@
+package rest
+
+import (
+	"clinic-app/cmd/rest/handler"
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/factory"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// passes stands for any status but 401 and 403: the request got through authentication and authorization
+	// and reached its handler, which has no use cases behind it in this test
+	passes    = 0
+	forbidden = http.StatusForbidden
+)
+
+// roleTokens authenticates a token named after a role as a user with that role
+type roleTokens struct{}
+
+func (roleTokens) Authenticate(ftx factory.Service, accessToken string) (*models.Claims, error) {
+	switch accessToken {
+	case "patient", "doctor", "admin":
+		return &models.Claims{UserID: 1, Role: accessToken}, nil
+	}
+	return nil, errors.ErrInvalidToken
+}
+
+func (roleTokens) AuthenticateChallenge(ftx factory.Service, challengeToken, purpose string) (*models.Claims, error) {
+	return nil, errors.ErrInvalidToken
+}
+
+// routeRouter registers the routes of the API without use cases behind them, a handler that gets called fails with 500
+func routeRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	factory.SetUpDependencies(nil) // The requests never reach a database
+	middleware.SetUpAuthentication(roleTokens{})
+	middleware.SetUpAuthorization(authz.New(nil))
+
+	router := gin.New()
+	router.Use(gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, err any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	router.Use(middleware.TraceMiddleware(zap.NewNop()))
+	NewRestHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, handler.CookieOptions{}).RegisterRoutes(router)
+	return router
+}
+
+// routeParams fills in the parameters of a route
+var routeParams = strings.NewReplacer(":id", "1", ":username", "someone", ":ip", "192.0.2.1")
+
+// roleTable lists every route that needs a login with the status each role gets
+var roleTable = []struct {
+	method  string
+	path    string
+	patient int
+	doctor  int
+	admin   int
+}{
+	{http.MethodPost, "/email/verify/resend", passes, passes, passes},
+	{http.MethodPost, "/mfa/enroll", passes, passes, passes},
+	{http.MethodPost, "/mfa/activate", passes, passes, passes},
+	{http.MethodPost, "/mfa/recovery-codes", passes, passes, passes},
+	{http.MethodDelete, "/mfa/", passes, passes, passes},
+
+	{http.MethodDelete, "/lockouts/users/:username", forbidden, forbidden, passes},
+	{http.MethodDelete, "/lockouts/ips/:ip", forbidden, forbidden, passes},
+
+	{http.MethodPost, "/invitations/", forbidden, forbidden, passes},
+	{http.MethodGet, "/invitations/", forbidden, forbidden, passes},
+	{http.MethodGet, "/invitations/:id/events", forbidden, forbidden, passes},
+	{http.MethodDelete, "/invitations/:id", forbidden, forbidden, passes},
+
+	{http.MethodPost, "/appointment/", passes, forbidden, forbidden},
+	{http.MethodPost, "/appointment/series", passes, forbidden, forbidden},
+	{http.MethodPost, "/appointment/holds", passes, forbidden, forbidden},
+	{http.MethodPost, "/appointment/holds/:id/confirm", passes, forbidden, forbidden},
+	{http.MethodDelete, "/appointment/holds/:id", passes, forbidden, forbidden},
+	{http.MethodGet, "/appointment/:id", passes, passes, forbidden},
+	{http.MethodGet, "/appointment/:id/history", passes, passes, forbidden},
+	{http.MethodGet, "/appointment/history", passes, passes, forbidden},
+	{http.MethodDelete, "/appointment/:id", passes, passes, passes},
+	{http.MethodPut, "/appointment/:id/reschedule", passes, passes, passes},
+	{http.MethodPost, "/appointment/:id/check-in", forbidden, passes, passes},
+	{http.MethodPost, "/appointment/:id/start", forbidden, passes, forbidden},
+	{http.MethodPost, "/appointment/:id/complete", forbidden, passes, forbidden},
+	{http.MethodPost, "/appointment/:id/no-show", forbidden, passes, passes},
+
+	{http.MethodGet, "/doctors/", passes, passes, passes},
+	{http.MethodGet, "/doctors/:id", passes, passes, passes},
+	{http.MethodGet, "/doctors/:id/slots", passes, passes, passes},
+	{http.MethodGet, "/doctors/:id/free-slots", passes, passes, passes},
+	{http.MethodGet, "/doctors/:id/working-hours", passes, passes, passes},
+	{http.MethodPut, "/doctors/:id/working-hours", forbidden, passes, passes},
+	{http.MethodPost, "/doctors/:id/time-off", forbidden, passes, passes},
+	{http.MethodGet, "/doctors/:id/time-off", forbidden, passes, passes},
+	{http.MethodGet, "/doctors/:id/booking-policy", passes, passes, passes},
+	{http.MethodPut, "/doctors/:id/booking-policy", forbidden, forbidden, passes},
+	{http.MethodDelete, "/doctors/:id/booking-policy", forbidden, forbidden, passes},
+	{http.MethodGet, "/doctors/:id/waitlist", forbidden, passes, passes},
+	{http.MethodGet, "/doctors/:id/appointment-types", passes, passes, passes},
+	{http.MethodPut, "/doctors/:id/appointment-types", forbidden, passes, passes},
+
+	{http.MethodPost, "/waitlist/", passes, forbidden, forbidden},
+	{http.MethodGet, "/waitlist/", passes, forbidden, forbidden},
+	{http.MethodDelete, "/waitlist/:id", passes, forbidden, passes},
+	{http.MethodPost, "/waitlist/offers/:id/accept", passes, forbidden, forbidden},
+	{http.MethodPost, "/waitlist/offers/:id/decline", passes, forbidden, forbidden},
+
+	{http.MethodGet, "/appointment-types/", passes, passes, passes},
+	{http.MethodPost, "/appointment-types/", forbidden, forbidden, passes},
+	{http.MethodPut, "/appointment-types/:id", forbidden, forbidden, passes},
+	{http.MethodDelete, "/appointment-types/:id", forbidden, forbidden, passes},
+
+	{http.MethodGet, "/booking-policies/", forbidden, forbidden, passes},
+	{http.MethodPut, "/booking-policies/default", forbidden, forbidden, passes},
+
+	{http.MethodGet, "/time-off/", forbidden, forbidden, passes},
+	{http.MethodPost, "/time-off/:id/approve", forbidden, forbidden, passes},
+	{http.MethodPost, "/time-off/:id/reject", forbidden, forbidden, passes},
+	{http.MethodDelete, "/time-off/:id", forbidden, passes, passes},
+	{http.MethodGet, "/time-off/:id/appointments", forbidden, passes, passes},
+	{http.MethodPost, "/time-off/:id/appointments/cancel", forbidden, passes, passes},
+	{http.MethodPost, "/time-off/:id/appointments/reassign", forbidden, passes, passes},
+
+	{http.MethodGet, "/doctors-availability", forbidden, forbidden, passes},
+	{http.MethodGet, "/doctors-most-appointments", forbidden, forbidden, passes},
+	{http.MethodGet, "/doctors-over-6-hours", forbidden, forbidden, passes},
+}
+
+func TestRoutesByRole(t *testing.T) {
+	router := routeRouter()
+	serve := func(method, route, accessToken string) int {
+		req := httptest.NewRequest(method, routeParams.Replace(route), nil)
+		if accessToken != "" {
+			req.Header.Set("Authorization", "Bearer "+accessToken)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	for _, tt := range roleTable {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			if status := serve(tt.method, tt.path, ""); status != http.StatusUnauthorized {
+				t.Errorf("anonymous: status = %d, want %d", status, http.StatusUnauthorized)
+			}
+			for role, want := range map[string]int{"patient": tt.patient, "doctor": tt.doctor, "admin": tt.admin} {
+				status := serve(tt.method, tt.path, role)
+				switch {
+				case status == http.StatusNotFound:
+					t.Errorf("%s: route is not registered", role)
+				case want == passes && (status == http.StatusUnauthorized || status == http.StatusForbidden):
+					t.Errorf("%s: status = %d, want the request to reach its handler", role, status)
+				case want != passes && status != want:
+					t.Errorf("%s: status = %d, want %d", role, status, want)
+				}
+			}
+		})
+	}
+}
+
+// TestEveryRouteIsInTheRoleTable keeps the role table up to date with the routes of the API
+func TestEveryRouteIsInTheRoleTable(t *testing.T) {
+	public := map[string]bool{
+		"POST /register":             true,
+		"GET /login":                 true,
+		"POST /logout":               true,
+		"POST /token/refresh":        true,
+		"GET /.well-known/jwks.json": true,
+		"POST /email/verify":         true,
+		"POST /password/forgot":      true,
+		"POST /password/reset":       true,
+		"POST /login/mfa":            true,
+		"POST /invitations/accept":   true,
+	}
+	listed := map[string]bool{}
+	for _, tt := range roleTable {
+		listed[tt.method+" "+tt.path] = true
+	}
+
+	for _, route := range routeRouter().Routes() {
+		name := route.Method + " " + route.Path
+		if !public[name] && !listed[name] {
+			t.Errorf("%s is missing from the role table", name)
+		}
+	}
+}
@@ -1,17 +1,101 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 // Config holds the configuration for the application
 type Config struct {
-	DBConnStr      string
-	DBMaxIdleConns int // Maximum number of idle connections
-	DBMaxOpenConns int // Maximum number of open connections
+	DBConnStr        string
+	DBMaxIdleConns   int // Maximum number of idle connections
+	DBMaxOpenConns   int // Maximum number of open connections
+	PasswordHashCost int // bcrypt cost used when hashing user passwords
+
+	AccessTokenTTL     time.Duration // Lifetime of access tokens
+	RefreshTokenTTL    time.Duration // Lifetime of refresh tokens
+	RevocationCacheTTL time.Duration // How long a token found not revoked is trusted without asking the database
+	InvitationTTL      time.Duration // How long a staff invitation can be accepted
+
+	EmailVerificationTTL time.Duration // How long an email verification link can be used
+	PasswordResetTTL     time.Duration // How long a password reset link can be used
+	AppBaseURL           string        // Base URL of the frontend, used to build links in emails
+
+	JWT  JWTConfig  // Keys used to sign and verify tokens
+	Mail MailConfig // Outgoing mail
+	MFA  MFAConfig  // Two-factor authentication
+
+	LoginLockout LockoutConfig // Brute-force protection of the login
+
+	NoShowSweepInterval time.Duration // How often past appointments nobody checked in for are marked as no-show
+	NoShowGrace         time.Duration // How long after its end an appointment is still left scheduled
+
+	ScheduleHorizonDays         int           // How many days ahead schedules are materialised from the working hours
+	ScheduleMaterialiseInterval time.Duration // How often the schedule horizon is extended
+
+	IdempotencyKeyTTL          time.Duration // How long the response to an Idempotency-Key is replayed
+	IdempotencyCleanupInterval time.Duration // How often expired idempotency keys are removed
+
+	WaitlistOfferTTL          time.Duration // How long a freed slot is held for the waitlisted patient it is offered to
+	WaitlistPromotionInterval time.Duration // How often freed slots are offered to waitlisted patients
+
+	SlotHoldTTL          time.Duration // How long a time is held for a patient before it is given back
+	SlotHoldReapInterval time.Duration // How often holds that ran out are expired
+
+	ClinicLocation *time.Location // Timezone the clinic works in, calendar days and working hours are read in it
+
+	CookieDomain string // Domain of the token cookies, empty for host-only cookies
+	CookieSecure bool   // Only send the token cookies over HTTPS
+}
+
+// JWTConfig holds the token signing keys
+type JWTConfig struct {
+	Algorithm   string             // Algorithm new tokens are signed with: HS256, RS256 or EdDSA
+	ActiveKeyID string             // kid of the key new tokens are signed with
+	Keys        []SigningKeyConfig // Every key accepted for verification, including the active one
+}
+
+// MailConfig holds the settings of the outgoing mail sender
+type MailConfig struct {
+	Driver       string // smtp, or file to write mails to FilePath (or only log them when it is empty)
+	From         string // Sender address
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	FilePath     string // File the file driver appends mails to
+}
+
+// MFAConfig holds the two-factor authentication settings
+type MFAConfig struct {
+	Issuer        string        // Name authenticator apps show next to the account
+	RequiredRoles []string      // Roles that cannot log in without two-factor authentication
+	ChallengeTTL  time.Duration // How long the second login step can be completed
+	EncryptionKey []byte        // AES-256 key the TOTP secrets are encrypted with
+}
+
+// LockoutConfig holds the brute-force protection settings
+type LockoutConfig struct {
+	MaxFailures      int           // Failed logins per username before the account is locked
+	MaxFailuresPerIP int           // Failed logins per client IP before the IP is locked, higher since clients may share an IP
+	BackoffBase      time.Duration // Wait after the first failure, doubled with every further failure
+	BackoffMax       time.Duration // Upper bound of the backoff below the lockout threshold
+	LockoutDuration  time.Duration // How long a locked username or IP stays locked
+	FailureWindow    time.Duration // Failures are forgotten once no login failed for this long
+}
+
+// SigningKeyConfig holds the raw material of one key, either a shared secret or a PEM encoded key
+type SigningKeyConfig struct {
+	ID     string
+	Secret []byte // HMAC secret
+	PEM    []byte // PEM encoded RSA or Ed25519 key, private keys can sign while public keys only verify
 }
 
 // LoadConfig loads the configuration from environment variables
@@ -24,10 +108,132 @@ func LoadConfig() *Config {
 	// Get database connection string from environment variables
 	dbConnStr := getRequiredEnv("DB_CONN_STR")
 	return &Config{
-		DBConnStr:      dbConnStr,
-		DBMaxIdleConns: 10,  // Adjust the default value as needed
-		DBMaxOpenConns: 100, // Adjust the default value as needed
+		DBConnStr:        dbConnStr,
+		DBMaxIdleConns:   10,                                  // Adjust the default value as needed
+		DBMaxOpenConns:   100,                                 // Adjust the default value as needed
+		PasswordHashCost: getIntEnv("PASSWORD_HASH_COST", 12), // bcrypt cost, 10-14 is a sensible range
+
+		AccessTokenTTL:     getDurationEnv("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:    getDurationEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		RevocationCacheTTL: getDurationEnv("REVOCATION_CACHE_TTL", 5*time.Second),
+		InvitationTTL:      getDurationEnv("INVITATION_TTL", 72*time.Hour),
+
+		EmailVerificationTTL: getDurationEnv("EMAIL_VERIFICATION_TTL", 48*time.Hour),
+		PasswordResetTTL:     getDurationEnv("PASSWORD_RESET_TTL", time.Hour),
+		AppBaseURL:           strings.TrimRight(getEnv("APP_BASE_URL", "http://localhost:8080"), "/"),
+
+		JWT:  loadJWTConfig(),
+		Mail: loadMailConfig(),
+		MFA:  loadMFAConfig(),
+
+		LoginLockout: LockoutConfig{
+			MaxFailures:      getIntEnv("LOGIN_MAX_FAILURES", 5),
+			MaxFailuresPerIP: getIntEnv("LOGIN_MAX_FAILURES_PER_IP", 20),
+			BackoffBase:      getDurationEnv("LOGIN_BACKOFF_BASE", time.Second),
+			BackoffMax:       getDurationEnv("LOGIN_BACKOFF_MAX", time.Minute),
+			LockoutDuration:  getDurationEnv("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+			FailureWindow:    getDurationEnv("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+		},
+
+		NoShowSweepInterval: getDurationEnv("NO_SHOW_SWEEP_INTERVAL", 5*time.Minute),
+		NoShowGrace:         getDurationEnv("NO_SHOW_GRACE", 30*time.Minute),
+
+		ScheduleHorizonDays:         getIntEnv("SCHEDULE_HORIZON_DAYS", 60),
+		ScheduleMaterialiseInterval: getDurationEnv("SCHEDULE_MATERIALISE_INTERVAL", time.Hour),
+
+		IdempotencyKeyTTL:          getDurationEnv("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		IdempotencyCleanupInterval: getDurationEnv("IDEMPOTENCY_CLEANUP_INTERVAL", time.Hour),
+
+		WaitlistOfferTTL:          getDurationEnv("WAITLIST_OFFER_TTL", 15*time.Minute),
+		WaitlistPromotionInterval: getDurationEnv("WAITLIST_PROMOTION_INTERVAL", time.Minute),
+
+		SlotHoldTTL:          getDurationEnv("SLOT_HOLD_TTL", 5*time.Minute),
+		SlotHoldReapInterval: getDurationEnv("SLOT_HOLD_REAP_INTERVAL", time.Minute),
+
+		ClinicLocation: getLocationEnv("CLINIC_TIMEZONE", "UTC"),
+
+		CookieDomain: os.Getenv("COOKIE_DOMAIN"),
+		CookieSecure: getEnv("COOKIE_SECURE", "false") == "true",
+	}
+}
+
+// loadJWTConfig loads the token signing keys.
+// JWT_HMAC_KEYS holds comma separated kid:secret pairs, JWT_KEYS_DIR holds one <kid>.pem file per RSA or Ed25519 key.
+// Keeping retired keys configured lets tokens signed with them verify until they expire.
+func loadJWTConfig() JWTConfig {
+	cfg := JWTConfig{
+		Algorithm:   getEnv("JWT_ALGORITHM", "HS256"),
+		ActiveKeyID: getRequiredEnv("JWT_ACTIVE_KID"),
+	}
+
+	// Shared secrets
+	for _, pair := range strings.Split(os.Getenv("JWT_HMAC_KEYS"), ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kid, secret, ok := strings.Cut(pair, ":")
+		if !ok || kid == "" || secret == "" {
+			panic("Environment variable JWT_HMAC_KEYS must hold kid:secret pairs")
+		}
+		cfg.Keys = append(cfg.Keys, SigningKeyConfig{ID: kid, Secret: []byte(secret)})
+	}
+
+	// PEM encoded keys
+	if dir := os.Getenv("JWT_KEYS_DIR"); dir != "" {
+		files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+		if err != nil {
+			panic(fmt.Sprintf("Could not list JWT keys in %s", dir))
+		}
+		for _, file := range files {
+			pem, err := os.ReadFile(file)
+			if err != nil {
+				panic(fmt.Sprintf("Could not read JWT key %s", file))
+			}
+			kid := strings.TrimSuffix(filepath.Base(file), ".pem")
+			cfg.Keys = append(cfg.Keys, SigningKeyConfig{ID: kid, PEM: pem})
+		}
+	}
+
+	return cfg
+}
+
+// loadMailConfig loads the outgoing mail settings, mails are only logged unless configured otherwise
+func loadMailConfig() MailConfig {
+	cfg := MailConfig{
+		Driver:   getEnv("MAIL_DRIVER", "file"),
+		From:     getEnv("MAIL_FROM", "Clinic <no-reply@clinic.local>"),
+		FilePath: os.Getenv("MAIL_FILE"),
 	}
+	if cfg.Driver == "smtp" {
+		cfg.SMTPHost = getRequiredEnv("SMTP_HOST")
+		cfg.SMTPPort = getIntEnv("SMTP_PORT", 587)
+		cfg.SMTPUsername = os.Getenv("SMTP_USERNAME")
+		cfg.SMTPPassword = os.Getenv("SMTP_PASSWORD")
+	}
+	return cfg
+}
+
+// loadMFAConfig loads the two-factor authentication settings, doctors and admins need it unless configured otherwise
+func loadMFAConfig() MFAConfig {
+	cfg := MFAConfig{
+		Issuer:       getEnv("MFA_ISSUER", "Clinic"),
+		ChallengeTTL: getDurationEnv("MFA_CHALLENGE_TTL", 5*time.Minute),
+	}
+
+	for _, role := range strings.Split(getEnv("MFA_REQUIRED_ROLES", "doctor,admin"), ",") {
+		if role = strings.TrimSpace(role); role != "" && role != "none" {
+			cfg.RequiredRoles = append(cfg.RequiredRoles, role)
+		}
+	}
+
+	key, err := base64.StdEncoding.DecodeString(getRequiredEnv("MFA_ENCRYPTION_KEY"))
+	if err != nil || len(key) != 32 {
+		panic("Environment variable MFA_ENCRYPTION_KEY must be 32 bytes encoded as base64")
+	}
+	cfg.EncryptionKey = key
+
+	return cfg
 }
 
 // getRequiredEnv retrieves an environment variable and panics if it is not set
@@ -38,3 +244,47 @@ func getRequiredEnv(key string) string {
 	}
 	return value
 }
+
+// getEnv retrieves an environment variable, falling back to a default when it is not set
+func getEnv(key, fallback string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// getIntEnv retrieves an integer environment variable, falling back to a default when it is not set
+func getIntEnv(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		panic(fmt.Sprintf("Environment variable %s must be an integer", key))
+	}
+	return parsed
+}
+
+// getDurationEnv retrieves a duration environment variable such as "15m", falling back to a default when it is not set
+func getDurationEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		panic(fmt.Sprintf("Environment variable %s must be a duration", key))
+	}
+	return parsed
+}
+
+// getLocationEnv retrieves an IANA timezone name such as "Europe/Berlin", falling back to a default when it is not set
+func getLocationEnv(key, fallback string) *time.Location {
+	loc, err := time.LoadLocation(getEnv(key, fallback))
+	if err != nil {
+		panic(fmt.Sprintf("Environment variable %s must be an IANA timezone", key))
+	}
+	return loc
+}
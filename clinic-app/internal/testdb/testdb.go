@@ -0,0 +1,136 @@
+// Package testdb sets up the database the integration tests run against.
+//
+// The tests only run when TEST_DB_CONN_STR holds the connection string of a PostgreSQL database they may write to,
+// otherwise they are skipped. The database is migrated to the latest version and shared by every test,
+// so each test creates its own users and only looks at their rows.
+package testdb
+
+import (
+	"clinic-app/pkg/adapters/posty"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/infra/migrations"
+	"clinic-app/pkg/services/clinictime"
+	"clinic-app/pkg/services/factory"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// ConnStrEnv is the environment variable holding the connection string of the test database
+const ConnStrEnv = "TEST_DB_CONN_STR"
+
+var (
+	once  sync.Once
+	db    *sql.DB
+	dbErr error
+
+	userSeq atomic.Int64 // Makes the usernames of one test run unique
+)
+
+// Open connects to the test database, applying the migrations on first use. Sessions run in the clinic timezone,
+// so tests that need another one call clinictime.SetUpLocation before their first Open.
+func Open(t testing.TB) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv(ConnStrEnv)
+	if dsn == "" {
+		t.Skipf("%s not set, skipping database test", ConnStrEnv)
+	}
+
+	once.Do(func() {
+		db, dbErr = sql.Open("postgres", posty.WithTimeZone(dsn, clinictime.Location().String()))
+		if dbErr != nil {
+			return
+		}
+		dbErr = migrations.ApplyMigrations(migrationsDir(), false, db, zap.NewNop())
+	})
+	if dbErr != nil {
+		t.Fatalf("Could not set up test database: %v", dbErr)
+	}
+	return db
+}
+
+// Service returns a factory service on the test database scoped to the caller
+func Service(t testing.TB, db *sql.DB, principal models.Principal) factory.Service {
+	t.Helper()
+	ftx, err := factory.NewFactory(db, context.Background())
+	if err != nil {
+		t.Fatalf("Could not create factory: %v", err)
+	}
+	return ftx.WithPrincipal(principal)
+}
+
+// User creates a user with a verified email address. The user and everything it owns are removed after the test.
+func User(t testing.TB, db *sql.DB, role string) int {
+	t.Helper()
+	username := fmt.Sprintf("t%d_%d", time.Now().UnixNano()%1e12, userSeq.Add(1))
+
+	var userId int
+	err := db.QueryRow(`
+		INSERT INTO Users (username, name, email, password, role, email_verified_at)
+		VALUES ($1, $1, $1 || '@test.local', 'x', $2, NOW())
+		RETURNING user_id;
+	`, username, role).Scan(&userId)
+	if err != nil {
+		t.Fatalf("Could not create %s: %v", role, err)
+	}
+
+	t.Cleanup(func() {
+		if _, err := db.Exec(`DELETE FROM Users WHERE user_id = $1;`, userId); err != nil {
+			t.Logf("Could not remove %s %d: %v", role, userId, err)
+		}
+	})
+	return userId
+}
+
+// WorkingDay gives a doctor working hours on one clinic day, from and to being wall-clock times like "08:00",
+// and materialises the schedule of that day
+func WorkingDay(t testing.TB, db *sql.DB, doctorId int, day time.Time, from, to string) {
+	t.Helper()
+	day = clinictime.Day(day)
+	date := day.Format("2006-01-02")
+
+	_, err := db.Exec(`
+		INSERT INTO WorkingHours (doctor_id, weekday, start_time, end_time, effective_from, effective_to)
+		VALUES ($1, $2, $3::TIME, $4::TIME, $5::DATE, $5::DATE);
+	`, doctorId, int(day.Weekday()), from, to, date)
+	if err != nil {
+		t.Fatalf("Could not add working hours: %v", err)
+	}
+	if _, err := db.Exec(`SELECT materialise_schedules($1, $2::DATE, $2::DATE);`, doctorId, date); err != nil {
+		t.Fatalf("Could not materialise schedule: %v", err)
+	}
+}
+
+// Policy sets the daily caps of a doctor, call it before WorkingDay so the schedule picks them up
+func Policy(t testing.TB, db *sql.DB, doctorId, maxDailyAppointments, maxDailyMinutes int) {
+	t.Helper()
+	_, err := db.Exec(`
+		INSERT INTO BookingPolicies (doctor_id, max_daily_appointments, max_daily_minutes)
+		VALUES ($1, $2, $3);
+	`, doctorId, maxDailyAppointments, maxDailyMinutes)
+	if err != nil {
+		t.Fatalf("Could not set booking policy: %v", err)
+	}
+}
+
+// At returns the wall-clock time of a clinic day, like At(day, 9, 30)
+func At(day time.Time, hour, minute int) time.Time {
+	day = clinictime.In(day)
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+}
+
+// migrationsDir locates the migrations from this file, tests run in the directory of their package
+func migrationsDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "pkg", "infra", "migrations", "sql")
+}
@@ -1,6 +1,8 @@
 package adapters
 
 import (
+	"clinic-app/internal/config"
+	"clinic-app/pkg/adapters/mailer"
 	"database/sql"
 	"log"
 
@@ -15,15 +17,18 @@ import (
 
 // Options holds the configuration for setting up the adapters.
 type Options struct {
-	DBConnStr      string // Connection string for PostgreSQL database
-	DBMaxIdleConns int    // Maximum number of idle connections in the pool
-	DBMaxOpenConns int    // Maximum number of open connections in the pool
+	DBConnStr      string            // Connection string for PostgreSQL database
+	DBMaxIdleConns int               // Maximum number of idle connections in the pool
+	DBMaxOpenConns int               // Maximum number of open connections in the pool
+	DBTimeZone     string            // Session timezone of every database connection
+	Mail           config.MailConfig // Outgoing mail settings
 }
 
 // Results holds the initialized adapters.
 type Results struct {
-	DB     *sql.DB     // Database connection
-	Logger *zap.Logger // Logger instance
+	DB     *sql.DB       // Database connection
+	Logger *zap.Logger   // Logger instance
+	Mailer mailer.Mailer // Outgoing mail sender
 }
 
 // SetupAdapters initializes and returns the database connection and logger.
@@ -39,7 +44,7 @@ func SetupAdapters(opts *Options) (*Results, error) {
 
 	// Initialize the database connection using the posty package
 	res.DB, err = posty.MustInitDatabase(
-		opts.DBConnStr,      // Database connection string
+		posty.WithTimeZone(opts.DBConnStr, opts.DBTimeZone), // Database connection string, sessions run in the clinic timezone
 		opts.DBMaxIdleConns, // Maximum number of idle connections
 		opts.DBMaxOpenConns, // Maximum number of open connections
 		logger)              // Logger instance
@@ -48,6 +53,13 @@ func SetupAdapters(opts *Options) (*Results, error) {
 		return nil, err
 	}
 
+	// Initialize the mail sender
+	res.Mailer, err = mailer.New(opts.Mail, logger)
+	if err != nil {
+		logger.Error("Error initializing mailer", zap.Error(err)) // Log error if the mail driver is unknown
+		return nil, err
+	}
+
 	logger.Info("Adapters set up successfully") // Log success message
 	return res, nil
 }
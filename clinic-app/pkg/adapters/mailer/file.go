@@ -0,0 +1,68 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// FileMailer appends emails to a file instead of sending them, for local development and tests.
+// Without a file the emails are only logged.
+type FileMailer struct {
+	from   string
+	path   string
+	logger *zap.Logger
+	mu     sync.Mutex
+}
+
+// NewFileMailer creates a mailer that writes to path, or only logs when path is empty
+func NewFileMailer(from, path string, logger *zap.Logger) *FileMailer {
+	return &FileMailer{
+		from:   from,
+		path:   path,
+		logger: logger,
+	}
+}
+
+// Send writes a message to the file and logs it
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	data, err := render(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	// Without a file the log is the only place the mail can be read, links included
+	if m.path == "" {
+		m.logger.Info("Mail sent",
+			zap.String("To", msg.To),
+			zap.String("Subject", msg.Subject),
+			zap.String("Body", msg.Body),
+		)
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	file, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Separate messages the way an mbox file does
+	if _, err := file.WriteString("From " + m.from + "\r\n"); err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		return err
+	}
+	if _, err := file.WriteString("\r\n"); err != nil {
+		return err
+	}
+
+	m.logger.Info("Mail written", zap.String("To", msg.To), zap.String("Subject", msg.Subject), zap.String("File", m.path))
+	return nil
+}
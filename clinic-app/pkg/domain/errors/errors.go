@@ -3,6 +3,7 @@ package errors
 import (
 	"fmt"
 	"net/http"
+	"time"
 )
 
 // AppError represents an application-level error with an associated HTTP status code
@@ -31,8 +32,97 @@ var (
 	ErrUserNotFound      = NewClinicAppError(http.StatusNotFound, "User not found")
 	ErrInvalidPassword   = NewClinicAppError(http.StatusUnauthorized, "Invalid password")
 	ErrUnauthorized      = NewClinicAppError(http.StatusUnauthorized, "Unauthorized access")
-	ErrDuration          = NewClinicAppError(http.StatusNotAcceptable, "Invalid Appointment Duration")
-	ErrAppointmentExists = NewClinicAppError(http.StatusBadRequest, "Appointment already exists for this time")
+	ErrDuration          = NewClinicAppError(http.StatusNotAcceptable, "Appointment duration is outside the limits of the doctor's booking policy")
+	ErrAppointmentExists = NewClinicAppError(http.StatusConflict, "Appointment overlaps another appointment of the doctor or patient")
 	ErrNoSchedule        = NewClinicAppError(http.StatusNotFound, "No schedule found for the doctor")
 	ErrDoctorOverbooked  = NewClinicAppError(http.StatusNotAcceptable, "Doctor is overbooked")
+	ErrInvalidToken      = NewClinicAppError(http.StatusUnauthorized, "Invalid or expired token")
+	ErrTokenReused       = NewClinicAppError(http.StatusUnauthorized, "Refresh token has already been used, please log in again")
+	ErrRoleNotAllowed    = NewClinicAppError(http.StatusForbidden, "Only patients can register themselves, staff accounts are created by invitation")
+	ErrInvalidRole       = NewClinicAppError(http.StatusBadRequest, "Role must be doctor or admin")
+	ErrInvitationInvalid = NewClinicAppError(http.StatusGone, "Invitation is invalid, expired, revoked or already used")
+	ErrWeakPassword      = NewClinicAppError(http.StatusBadRequest, "Password must be 8-72 characters long, contain upper-case and lower-case letters and a digit, and must not contain the username")
+	ErrLinkInvalid       = NewClinicAppError(http.StatusGone, "Link is invalid, expired or already used")
+	ErrEmailNotVerified  = NewClinicAppError(http.StatusForbidden, "Email address must be verified before booking appointments")
+	ErrMailDelivery      = NewClinicAppError(http.StatusBadGateway, "Email could not be sent")
+	ErrInvalidMFACode    = NewClinicAppError(http.StatusUnauthorized, "Invalid two-factor authentication code")
+	ErrMFAAlreadyEnabled = NewClinicAppError(http.StatusConflict, "Two-factor authentication is already enabled")
+	ErrMFANotEnabled     = NewClinicAppError(http.StatusBadRequest, "Two-factor authentication is not set up")
+	ErrMFAMandatory      = NewClinicAppError(http.StatusForbidden, "Two-factor authentication is mandatory for your role")
+	ErrTooManyAttempts   = NewClinicAppError(http.StatusTooManyRequests, "Too many failed attempts, please try again later")
+	ErrForbidden         = NewClinicAppError(http.StatusForbidden, "You don't have permission to access this resource")
+	ErrInvalidTransition = NewClinicAppError(http.StatusConflict, "Appointment cannot change to this status")
+	ErrOutsideHours      = NewClinicAppError(http.StatusNotAcceptable, "Appointment is outside the doctor's working hours")
+	ErrBeyondHorizon     = NewClinicAppError(http.StatusNotAcceptable, "Appointment is further ahead than the doctor takes bookings")
+	ErrInvalidPolicy     = NewClinicAppError(http.StatusBadRequest, "The clinic default must set every rule, minimum duration cannot exceed maximum duration")
+	ErrDoctorOnTimeOff   = NewClinicAppError(http.StatusNotAcceptable, "Doctor is not available at this time")
+	ErrInvalidTimeOff    = NewClinicAppError(http.StatusBadRequest, "Time off needs start_time before end_time in the future, weekly repeats need blocks shorter than a week and repeat_until within a year")
+	ErrTimeOffStatus     = NewClinicAppError(http.StatusConflict, "Time off cannot change to this status")
+	ErrInvalidHours      = NewClinicAppError(http.StatusBadRequest, "Working hours need a weekday from 0 (Sunday) to 6, start and end as HH:MM with start before end, no overlapping blocks, and dates as YYYY-MM-DD with effective_to not before effective_from")
+	ErrInvalidSlotQuery  = NewClinicAppError(http.StatusBadRequest, "Free slots need from and to as YYYY-MM-DD at most 31 days apart, a duration of at most 480 minutes and a granularity of 5 to 120 minutes")
+	ErrIdempotencyReuse  = NewClinicAppError(http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+	ErrRequestInProgress = NewClinicAppError(http.StatusConflict, "A request with this Idempotency-Key is still being processed")
+	ErrSlotHeld          = NewClinicAppError(http.StatusConflict, "This time is held for another patient")
+	ErrInvalidWaitlist   = NewClinicAppError(http.StatusBadRequest, "Waitlist entries need date_from and date_to as YYYY-MM-DD at most 90 days apart and not in the past, a duration of 5 to 480 minutes and windows as HH:MM with start before end")
+	ErrOfferClosed       = NewClinicAppError(http.StatusConflict, "Waitlist offer has already been answered or has expired")
+	ErrNotOnWaitlist     = NewClinicAppError(http.StatusConflict, "Waitlist entry is no longer active")
+	ErrInvalidSeries     = NewClinicAppError(http.StatusBadRequest, "Series need an rrule with FREQ=WEEKLY, INTERVAL 1 or 2 and either COUNT of at most 52 or UNTIL, and a mode of all_or_nothing or best_effort")
+	ErrInvalidScope      = NewClinicAppError(http.StatusBadRequest, "Scope must be this, following or all, following and all only apply to appointments of a series")
+	ErrSeriesNotBooked   = NewClinicAppError(http.StatusConflict, "Series was not booked, see the occurrences for the reasons")
+	ErrSeriesNotMoved    = NewClinicAppError(http.StatusConflict, "Series was not rescheduled, see the occurrences for the reasons")
+	ErrHoldNotActive     = NewClinicAppError(http.StatusGone, "Hold has expired or was already confirmed or released")
+	ErrNotReschedulable  = NewClinicAppError(http.StatusConflict, "Only scheduled appointments can be rescheduled")
+	ErrInvalidAptmtType  = NewClinicAppError(http.StatusBadRequest, "Appointment types need a name, a color as #RRGGBB and durations of 5 to 480 minutes with the default between the minimum and maximum")
+	ErrAptmtTypeExists   = NewClinicAppError(http.StatusConflict, "An appointment type with this name already exists")
+	ErrTypeNotOffered    = NewClinicAppError(http.StatusNotAcceptable, "Doctor does not offer this appointment type")
+	ErrTypeDuration      = NewClinicAppError(http.StatusNotAcceptable, "Appointment duration is outside the limits of its appointment type")
+	ErrNoShowTooEarly    = NewClinicAppError(http.StatusConflict, "A patient can only be marked as no-show once the appointment has started")
+	ErrOutsideVisit      = NewClinicAppError(http.StatusConflict, "Patients can be checked in and consultations started from an hour before to two hours after the start of the appointment")
 )
+
+// LockedError is returned while attempts are blocked after too many failures, it unwraps to ErrTooManyAttempts
+type LockedError struct {
+	RetryAfter time.Duration // How long the caller has to wait
+}
+
+// Error implements the error interface for LockedError
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrTooManyAttempts.Message, e.RetryAfter.Round(time.Second))
+}
+
+// Unwrap lets errors.Is match ErrTooManyAttempts
+func (e *LockedError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
+// InvalidTransitionError is returned when an appointment cannot move from its status to the requested one,
+// it unwraps to ErrInvalidTransition
+type InvalidTransitionError struct {
+	From string // Current status of the appointment
+	To   string // Requested status
+}
+
+// Error implements the error interface for InvalidTransitionError
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("Appointment cannot change from %s to %s", e.From, e.To)
+}
+
+// Unwrap lets errors.Is match ErrInvalidTransition
+func (e *InvalidTransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+// ConflictError is returned when a booking overlaps another appointment, it unwraps to ErrAppointmentExists
+type ConflictError struct {
+	AppointmentID int // Appointment the booking collided with, zero if it could not be determined
+}
+
+// Error implements the error interface for ConflictError
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s: appointment %d", ErrAppointmentExists.Message, e.AppointmentID)
+}
+
+// Unwrap lets errors.Is match ErrAppointmentExists
+func (e *ConflictError) Unwrap() error {
+	return ErrAppointmentExists
+}
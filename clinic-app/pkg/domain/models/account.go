@@ -0,0 +1,23 @@
+package models
+
+// Purposes of the single-use tokens mailed to users
+const (
+	TokenPurposeVerifyEmail   = "verify_email"
+	TokenPurposeResetPassword = "reset_password"
+)
+
+// VerifyEmail is the request to confirm an email address
+type VerifyEmail struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ForgotPassword is the request to mail a password reset link
+type ForgotPassword struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPassword is the request to choose a new password with a reset token
+type ResetPassword struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
@@ -2,22 +2,115 @@ package models
 
 import "time"
 
+// Appointment statuses, see the appointments usecase for the allowed transitions
+const (
+	StatusScheduled  = "scheduled"
+	StatusCheckedIn  = "checked_in"
+	StatusInProgress = "in_progress"
+	StatusCompleted  = "completed"
+	StatusCanceled   = "canceled"
+	StatusNoShow     = "no_show"
+)
+
+// Hold statuses, only held holds keep the time from other patients
+const (
+	HoldHeld      = "held"
+	HoldConfirmed = "confirmed"
+	HoldReleased  = "released"
+	HoldExpired   = "expired"
+)
+
 type BookAppointment struct {
 	AppointmentID int       `json:"appointment_id"`
 	DoctorID      int       `json:"doctor_id"`
 	PatientID     int       `json:"patient_id"`
 	Date          time.Time `json:"appointment_date"`
 	StartTime     time.Time `json:"start_time"`
-	EndTime       time.Time `json:"end_time"`
+	EndTime       time.Time `json:"end_time"` // Filled in from the default duration of the type when left out
 	Status        string    `json:"status"`
+	TypeID        *int      `json:"type_id"`
+	Reason        string    `json:"reason" binding:"max=500"` // Why the patient wants to see the doctor
 }
 
 type Appointment struct {
-	AppointmentID int       `json:"appointment_id"`
+	AppointmentID   int        `json:"appointment_id"`
+	PatientID       int        `json:"patient_id"`
+	DoctorID        int        `json:"doctor_id"`
+	PatientName     string     `json:"patient_name"`
+	DoctorName      string     `json:"doctor_name"`
+	StartTime       time.Time  `json:"start_time"`
+	EndTime         time.Time  `json:"end_time"`
+	Status          string     `json:"status"`
+	CanceledBy      *int       `json:"canceled_by,omitempty"`
+	CanceledAt      *time.Time `json:"canceled_at,omitempty"`
+	CancelReason    *string    `json:"cancel_reason,omitempty"`
+	RescheduledFrom *int       `json:"rescheduled_from,omitempty"`
+	SeriesID        *int       `json:"series_id,omitempty"`
+	TypeID          *int       `json:"type_id,omitempty"`
+	TypeName        *string    `json:"type_name,omitempty"`
+	Reason          *string    `json:"reason,omitempty"`
+	CheckedInAt     *time.Time `json:"checked_in_at,omitempty"`
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	NoShowAt        *time.Time `json:"no_show_at,omitempty"`
+}
+
+// AppointmentHold is a time held for a patient while they complete a booking, see the hold statuses
+type AppointmentHold struct {
+	ID            int       `json:"hold_id"`
 	PatientID     int       `json:"patient_id"`
-	PatientName   string    `json:"patient_name"`
-	DoctorName    string    `json:"doctor_name"`
+	DoctorID      int       `json:"doctor_id"`
+	Date          time.Time `json:"appointment_date"`
 	StartTime     time.Time `json:"start_time"`
 	EndTime       time.Time `json:"end_time"`
 	Status        string    `json:"status"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	TypeID        *int      `json:"type_id,omitempty"`
+	Reason        *string   `json:"reason,omitempty"`
+	AppointmentID *int      `json:"appointment_id,omitempty"` // Set once the hold was confirmed
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// CancelAppointment is the request to cancel an appointment, the reason is optional
+type CancelAppointment struct {
+	AppointmentID int    `json:"-"`
+	Reason        string `json:"reason" binding:"max=255"`
+}
+
+// RescheduleAppointment is the request to move an appointment, DoctorID is optional and keeps the doctor when left out
+type RescheduleAppointment struct {
+	AppointmentID int       `json:"-"`
+	DoctorID      int       `json:"doctor_id"`
+	Date          time.Time `json:"appointment_date" binding:"required"`
+	StartTime     time.Time `json:"start_time" binding:"required"`
+	EndTime       time.Time `json:"end_time" binding:"required"`
+}
+
+// AppointmentParties holds the users an appointment belongs to, the authorizer decides ownership with it
+type AppointmentParties struct {
+	AppointmentID int
+	PatientID     int
+	DoctorID      int
+	Upcoming      bool // The appointment has not started yet
+}
+
+// InZone renders the times of the appointment in a zone
+func (a Appointment) InZone(zone Zone) Appointment {
+	a.StartTime = zone(a.StartTime)
+	a.EndTime = zone(a.EndTime)
+	a.CanceledAt = zone.at(a.CanceledAt)
+	a.CheckedInAt = zone.at(a.CheckedInAt)
+	a.StartedAt = zone.at(a.StartedAt)
+	a.CompletedAt = zone.at(a.CompletedAt)
+	a.NoShowAt = zone.at(a.NoShowAt)
+	return a
+}
+
+// InZone renders the times of the hold in a zone, its day stays the clinic day
+func (h AppointmentHold) InZone(zone Zone) AppointmentHold {
+	h.StartTime = zone(h.StartTime)
+	h.EndTime = zone(h.EndTime)
+	h.ExpiresAt = zone(h.ExpiresAt)
+	h.CreatedAt = zone(h.CreatedAt)
+	return h
 }
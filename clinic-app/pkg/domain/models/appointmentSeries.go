@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Ways a series can be booked
+const (
+	SeriesAllOrNothing = "all_or_nothing" // Book every occurrence or none
+	SeriesBestEffort   = "best_effort"    // Book the occurrences that fit, skip the others
+)
+
+// Occurrences of a series a cancellation or reschedule applies to
+const (
+	ScopeThis      = "this"
+	ScopeFollowing = "following"
+	ScopeAll       = "all"
+)
+
+// AppointmentSeries is a recurring appointment of a patient, StartTime and EndTime are those of the first occurrence
+type AppointmentSeries struct {
+	ID        int       `json:"series_id"`
+	PatientID int       `json:"patient_id"`
+	DoctorID  int       `json:"doctor_id"`
+	RRule     string    `json:"rrule"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BookSeries is the request to book a recurring appointment. RRule supports FREQ=WEEKLY with INTERVAL 1 or 2
+// and either COUNT or UNTIL, Mode defaults to all_or_nothing.
+type BookSeries struct {
+	PatientID int       `json:"-"`
+	DoctorID  int       `json:"doctor_id" binding:"required"`
+	StartTime time.Time `json:"start_time" binding:"required"`
+	EndTime   time.Time `json:"end_time" binding:"required"`
+	RRule     string    `json:"rrule" binding:"required"`
+	Mode      string    `json:"mode"`
+}
+
+// SeriesOccurrence is the outcome of booking one occurrence of a series
+type SeriesOccurrence struct {
+	StartTime                time.Time `json:"start_time"`
+	EndTime                  time.Time `json:"end_time"`
+	AppointmentID            int       `json:"appointment_id,omitempty"`             // Set when the occurrence was booked
+	Error                    string    `json:"error,omitempty"`                      // Why the occurrence could not be booked
+	ConflictingAppointmentID int       `json:"conflicting_appointment_id,omitempty"` // The appointment the occurrence overlaps
+}
+
+// InZone renders the times of the series in a zone
+func (s AppointmentSeries) InZone(zone Zone) AppointmentSeries {
+	s.StartTime = zone(s.StartTime)
+	s.EndTime = zone(s.EndTime)
+	s.CreatedAt = zone(s.CreatedAt)
+	return s
+}
+
+// InZone renders the times of the occurrence in a zone
+func (o SeriesOccurrence) InZone(zone Zone) SeriesOccurrence {
+	o.StartTime = zone(o.StartTime)
+	o.EndTime = zone(o.EndTime)
+	return o
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// AppointmentType is a kind of visit, such as a new consultation or a follow-up. Bookings of the type get its
+// default duration when they leave out their end and have to stay within its minimum and maximum duration.
+type AppointmentType struct {
+	ID                     int        `json:"type_id"`
+	Name                   string     `json:"name" binding:"required,max=100"`
+	DefaultDurationMinutes int        `json:"default_duration_minutes" binding:"required"`
+	MinDurationMinutes     int        `json:"min_duration_minutes" binding:"required"`
+	MaxDurationMinutes     int        `json:"max_duration_minutes" binding:"required"`
+	Color                  string     `json:"color" binding:"required"` // #RRGGBB used to show the type in calendars
+	PrepInstructions       string     `json:"prep_instructions" binding:"max=2000"`
+	Active                 *bool      `json:"active"` // Inactive types cannot be booked anymore, left out when updating to keep it as is
+	CreatedAt              *time.Time `json:"created_at,omitempty"`
+	UpdatedAt              *time.Time `json:"updated_at,omitempty"`
+}
+
+// DoctorAppointmentTypes is the request to choose the types a doctor offers, an empty list offers every active type
+type DoctorAppointmentTypes struct {
+	TypeIDs []int `json:"type_ids" binding:"required"`
+}
+
+// InZone renders the times of the type in a zone
+func (t AppointmentType) InZone(zone Zone) AppointmentType {
+	t.CreatedAt = zone.at(t.CreatedAt)
+	t.UpdatedAt = zone.at(t.UpdatedAt)
+	return t
+}
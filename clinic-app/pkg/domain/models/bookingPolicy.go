@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// BookingPolicy holds the booking rules of a doctor, or the clinic-wide default when DoctorID is nil.
+// The default sets every rule, a doctor's policy falls back to the default for the rules it leaves nil.
+type BookingPolicy struct {
+	DoctorID             *int       `json:"doctor_id,omitempty"`
+	MaxDailyAppointments *int       `json:"max_daily_appointments" binding:"omitempty,min=1"`
+	MaxDailyMinutes      *int       `json:"max_daily_minutes" binding:"omitempty,min=1"`
+	MinDurationMinutes   *int       `json:"min_duration_minutes" binding:"omitempty,min=1"`
+	MaxDurationMinutes   *int       `json:"max_duration_minutes" binding:"omitempty,min=1"`
+	BufferMinutes        *int       `json:"buffer_minutes" binding:"omitempty,min=0"`       // Free time kept around every appointment of the doctor
+	BookingHorizonDays   *int       `json:"booking_horizon_days" binding:"omitempty,min=1"` // How many days ahead appointments can be booked
+	UpdatedBy            *int       `json:"updated_by,omitempty"`
+	UpdatedAt            *time.Time `json:"updated_at,omitempty"`
+}
+
+// InZone renders the times of the policy in a zone
+func (p BookingPolicy) InZone(zone Zone) BookingPolicy {
+	p.UpdatedAt = zone.at(p.UpdatedAt)
+	return p
+}
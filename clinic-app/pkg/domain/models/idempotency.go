@@ -0,0 +1,16 @@
+package models
+
+// IdempotentRequest identifies a request sent with an Idempotency-Key header
+type IdempotentRequest struct {
+	UserID      int    // 0 for anonymous requests
+	Key         string // Value of the Idempotency-Key header, scoped to the request for anonymous requests
+	RequestHash string // SHA-256 of the method, path and body, tells retries apart from a reused key
+}
+
+// IdempotentResponse is the stored response of an idempotent request, StatusCode is 0 while it is being processed
+type IdempotentResponse struct {
+	RequestHash string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
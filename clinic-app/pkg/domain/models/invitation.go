@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Invitation is an invitation for a staff member to create a doctor or admin account
+type Invitation struct {
+	InvitationID   int        `json:"invitation_id"`
+	Email          string     `json:"email"`
+	Role           string     `json:"role"`
+	InvitedBy      *int       `json:"invited_by"`
+	Status         string     `json:"status"` // pending, accepted, revoked or expired
+	ExpiresAt      time.Time  `json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at"`
+	AcceptedUserID *int       `json:"accepted_user_id"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// NewInvitation is the request to invite a staff member
+type NewInvitation struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required"`
+}
+
+// AcceptInvitation is the request to complete a staff account from an invitation
+type AcceptInvitation struct {
+	Token    string `json:"token" binding:"required"`
+	Username string `json:"username" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// InvitationEvent is one entry of an invitation's audit trail
+type InvitationEvent struct {
+	EventID      int       `json:"event_id"`
+	InvitationID int       `json:"invitation_id"`
+	Event        string    `json:"event"`
+	ActorID      *int      `json:"actor_id"`
+	Details      *string   `json:"details"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// InZone renders the times of the invitation in a zone
+func (i Invitation) InZone(zone Zone) Invitation {
+	i.ExpiresAt = zone(i.ExpiresAt)
+	i.AcceptedAt = zone.at(i.AcceptedAt)
+	i.RevokedAt = zone.at(i.RevokedAt)
+	i.CreatedAt = zone(i.CreatedAt)
+	return i
+}
+
+// InZone renders the time of the event in a zone
+func (e InvitationEvent) InZone(zone Zone) InvitationEvent {
+	e.CreatedAt = zone(e.CreatedAt)
+	return e
+}
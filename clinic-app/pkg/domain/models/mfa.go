@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Purposes of the challenge tokens handed out between the password and the second login step
+const (
+	ChallengeMFA       = "mfa"        // The user has to enter a code from their authenticator app
+	ChallengeEnrollMFA = "mfa_enroll" // The user's role requires two-factor authentication they have not set up yet
+)
+
+// MFAChallenge is returned by a login that needs a second step
+type MFAChallenge struct {
+	Token     string    `json:"challenge_token"`
+	Purpose   string    `json:"purpose"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// MFALogin is the second step of a login
+type MFALogin struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"` // TOTP code or recovery code
+}
+
+// MFACode is a request confirmed with a TOTP code or a recovery code
+type MFACode struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// MFAEnrollment is what an authenticator app needs to be set up
+type MFAEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"` // otpauth:// URI to render as a QR code
+}
+
+// MFASettings is a user's stored TOTP configuration
+type MFASettings struct {
+	UserID       int
+	Secret       string // Encrypted TOTP secret
+	Enabled      bool   // False until the first code was confirmed
+	LastUsedStep int64  // Time step of the last accepted code, codes cannot be replayed
+}
+
+// InZone renders the expiry of the challenge in a zone
+func (c MFAChallenge) InZone(zone Zone) MFAChallenge {
+	c.ExpiresAt = zone(c.ExpiresAt)
+	return c
+}
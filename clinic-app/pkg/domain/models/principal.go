@@ -0,0 +1,12 @@
+package models
+
+// Principal identifies the authenticated caller of a request
+type Principal struct {
+	UserID int    `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+// IsAuthenticated reports whether the principal belongs to a logged in user
+func (p Principal) IsAuthenticated() bool {
+	return p.UserID != 0
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// WorkingHours is one weekly working block of a doctor
+type WorkingHours struct {
+	ID            int     `json:"id"`
+	DoctorID      int     `json:"doctor_id"`
+	Weekday       int     `json:"weekday"`        // 0 is Sunday
+	StartTime     string  `json:"start_time"`     // HH:MM
+	EndTime       string  `json:"end_time"`       // HH:MM
+	EffectiveFrom string  `json:"effective_from"` // YYYY-MM-DD
+	EffectiveTo   *string `json:"effective_to,omitempty"`
+}
+
+// WorkingBlock is a block of a week in a SetWorkingHours request
+type WorkingBlock struct {
+	Weekday   int    `json:"weekday"`
+	StartTime string `json:"start_time" binding:"required"`
+	EndTime   string `json:"end_time" binding:"required"`
+}
+
+// SetWorkingHours replaces a doctor's working hours from EffectiveFrom on.
+// The doctor does not work after EffectiveTo unless new hours are set, no blocks at all means no working hours.
+type SetWorkingHours struct {
+	DoctorID      int            `json:"-"`
+	EffectiveFrom string         `json:"effective_from" binding:"required"`
+	EffectiveTo   *string        `json:"effective_to"`
+	Blocks        []WorkingBlock `json:"blocks" binding:"dive"`
+}
+
+// FreeSlot is a bookable time of a doctor, its fields can be posted to /appointment as they are
+type FreeSlot struct {
+	DoctorID  int       `json:"doctor_id"`
+	Date      time.Time `json:"appointment_date"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// FreeSlotQuery asks for the free slots of a doctor between two days
+type FreeSlotQuery struct {
+	DoctorID    int    `form:"-"`
+	From        string `form:"from" binding:"required"` // YYYY-MM-DD
+	To          string `form:"to" binding:"required"`   // YYYY-MM-DD, inclusive
+	Duration    int    `form:"duration"`                // Minutes, 30 unless given
+	Granularity int    `form:"granularity"`             // Minutes between the starts of two slots, 15 unless given
+}
+
+// InZone renders the times of the slot in a zone, its day stays the clinic day
+func (s FreeSlot) InZone(zone Zone) FreeSlot {
+	s.StartTime = zone(s.StartTime)
+	s.EndTime = zone(s.EndTime)
+	return s
+}
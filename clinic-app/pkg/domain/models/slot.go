@@ -21,3 +21,17 @@ type SlotPat struct {
 	IsBooked      bool      `json:"is_booked"`
 	Duration      string    `json:"duration"`
 }
+
+// InZone renders the times of the slot in a zone
+func (s SlotDoc) InZone(zone Zone) SlotDoc {
+	s.StartTime = zone(s.StartTime)
+	s.EndTime = zone(s.EndTime)
+	return s
+}
+
+// InZone renders the times of the slot in a zone
+func (s SlotPat) InZone(zone Zone) SlotPat {
+	s.StartTime = zone(s.StartTime)
+	s.EndTime = zone(s.EndTime)
+	return s
+}
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Statuses of a time off request
+const (
+	TimeOffPending   = "pending"
+	TimeOffApproved  = "approved"
+	TimeOffRejected  = "rejected"
+	TimeOffWithdrawn = "withdrawn"
+)
+
+// TimeOff is a period a doctor is not available, optionally repeated every week
+type TimeOff struct {
+	ID          int        `json:"time_off_id"`
+	DoctorID    int        `json:"doctor_id"`
+	StartTime   time.Time  `json:"start_time"`
+	EndTime     time.Time  `json:"end_time"`
+	RepeatUntil *string    `json:"repeat_until,omitempty"` // YYYY-MM-DD
+	Reason      *string    `json:"reason,omitempty"`
+	Status      string     `json:"status"`
+	RequestedBy *int       `json:"requested_by,omitempty"`
+	DecidedBy   *int       `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// RequestTimeOff is the request for time off. AllDay stretches the period to whole days,
+// RepeatUntil repeats it every week until that day.
+type RequestTimeOff struct {
+	DoctorID    int       `json:"-"`
+	StartTime   time.Time `json:"start_time" binding:"required"`
+	EndTime     time.Time `json:"end_time" binding:"required"`
+	AllDay      bool      `json:"all_day"`
+	RepeatUntil *string   `json:"repeat_until"` // YYYY-MM-DD
+	Reason      string    `json:"reason" binding:"max=255"`
+}
+
+// ReassignAppointments is the request to move the appointments affected by a time off to another doctor
+type ReassignAppointments struct {
+	DoctorID int `json:"doctor_id" binding:"required"`
+}
+
+// AffectedAppointment is the outcome of canceling or reassigning one appointment affected by a time off
+type AffectedAppointment struct {
+	AppointmentID    int    `json:"appointment_id"`
+	NewAppointmentID int    `json:"new_appointment_id,omitempty"` // Set when the appointment was reassigned
+	Error            string `json:"error,omitempty"`              // Why the appointment was left as it is
+}
+
+// InZone renders the times of the time off in a zone
+func (t TimeOff) InZone(zone Zone) TimeOff {
+	t.StartTime = zone(t.StartTime)
+	t.EndTime = zone(t.EndTime)
+	t.DecidedAt = zone.at(t.DecidedAt)
+	t.CreatedAt = zone(t.CreatedAt)
+	return t
+}
@@ -1,9 +1,68 @@
 package models
 
-import "github.com/golang-jwt/jwt"
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
 
 type Claims struct {
-	UserID int    `json:"user_id"`
-	Role   string `json:"role"`
+	UserID  int    `json:"user_id"`
+	Role    string `json:"role"`
+	Purpose string `json:"purpose,omitempty"` // Set on login challenge tokens, which are not access tokens
 	jwt.StandardClaims
 }
+
+// TokenPair holds the tokens issued to a client after a login or a refresh
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	AccessExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// RefreshToken is a refresh token as it is stored, only the hash of the token is persisted
+type RefreshToken struct {
+	Hash            string
+	FamilyID        string        // All tokens rotated from the same login share a family
+	AccessJTI       string        // ID of the access token issued alongside this refresh token
+	AccessExpiresAt time.Time     // Expiry of that access token
+	TTL             time.Duration // Lifetime of the refresh token
+}
+
+// RefreshSession is the login a refresh token belongs to
+type RefreshSession struct {
+	UserID   int
+	Role     string
+	FamilyID string
+}
+
+// RevokedToken is an access token that must no longer be accepted
+type RevokedToken struct {
+	JTI       string
+	ExpiresAt time.Time
+}
+
+// JSONWebKey is a public key in RFC 7517 format
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // OKP curve
+	X   string `json:"x,omitempty"`   // OKP public key
+}
+
+// JSONWebKeySet is the document served at /.well-known/jwks.json
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// InZone renders the expiries of the tokens in a zone
+func (p TokenPair) InZone(zone Zone) TokenPair {
+	p.AccessExpiresAt = zone(p.AccessExpiresAt)
+	p.RefreshExpiresAt = zone(p.RefreshExpiresAt)
+	return p
+}
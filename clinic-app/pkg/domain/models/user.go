@@ -1,12 +1,13 @@
 package models
 
 type User struct {
-	ID       int    `json:"id"`
-	Username string `json:"username"`
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
-	Role     string `json:"role"`
+	ID            int    `json:"id"`
+	Username      string `json:"username"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	Password      string `json:"password"`
+	Role          string `json:"role"`
+	EmailVerified bool   `json:"-"`
 }
 
 type Doctor struct {
@@ -19,6 +20,7 @@ type Doctor struct {
 type Credentials struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
+	ClientIP string `json:"-"` // Set by the handler, failed logins are also counted per client IP
 }
 
 // DoctorAvailability represents a doctor's availability
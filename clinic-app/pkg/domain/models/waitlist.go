@@ -0,0 +1,99 @@
+package models
+
+import "time"
+
+// Statuses of a waitlist entry
+const (
+	WaitlistWaiting = "waiting"
+	WaitlistOffered = "offered"
+	WaitlistBooked  = "booked"
+	WaitlistLeft    = "left"
+	WaitlistExpired = "expired"
+)
+
+// Statuses of a waitlist offer
+const (
+	OfferOpen      = "open"
+	OfferAccepted  = "accepted"
+	OfferDeclined  = "declined"
+	OfferExpired   = "expired"
+	OfferWithdrawn = "withdrawn"
+)
+
+// WaitlistWindow is a preferred time of day of a waitlist entry
+type WaitlistWindow struct {
+	StartTime string `json:"start_time" binding:"required"` // HH:MM
+	EndTime   string `json:"end_time" binding:"required"`   // HH:MM
+}
+
+// WaitlistEntry is a patient waiting for a doctor to have room between two days
+type WaitlistEntry struct {
+	ID              int              `json:"waitlist_entry_id"`
+	PatientID       int              `json:"patient_id"`
+	DoctorID        int              `json:"doctor_id"`
+	DateFrom        string           `json:"date_from"` // YYYY-MM-DD
+	DateTo          string           `json:"date_to"`   // YYYY-MM-DD
+	DurationMinutes int              `json:"duration_minutes"`
+	Windows         []WaitlistWindow `json:"windows"`
+	Status          string           `json:"status"`
+	CreatedAt       time.Time        `json:"created_at"`
+	Offers          []WaitlistOffer  `json:"offers"`
+}
+
+// WaitlistOffer is a freed slot offered to a waitlist entry, held for the patient until ExpiresAt
+type WaitlistOffer struct {
+	ID            int        `json:"waitlist_offer_id"`
+	EntryID       int        `json:"waitlist_entry_id"`
+	DoctorID      int        `json:"doctor_id"`
+	StartTime     time.Time  `json:"start_time"`
+	EndTime       time.Time  `json:"end_time"`
+	Status        string     `json:"status"`
+	OfferedAt     time.Time  `json:"offered_at"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	RespondedAt   *time.Time `json:"responded_at,omitempty"`
+	AppointmentID *int       `json:"appointment_id,omitempty"` // Set when the offer was accepted
+}
+
+// JoinWaitlist is the request to wait for a doctor between two days, optionally only at some times of day.
+// DurationMinutes defaults to 30.
+type JoinWaitlist struct {
+	PatientID       int              `json:"-"`
+	DoctorID        int              `json:"doctor_id" binding:"required"`
+	DateFrom        string           `json:"date_from" binding:"required"` // YYYY-MM-DD
+	DateTo          string           `json:"date_to" binding:"required"`   // YYYY-MM-DD
+	DurationMinutes int              `json:"duration_minutes"`
+	Windows         []WaitlistWindow `json:"windows" binding:"dive"`
+}
+
+// OfferNotice is what the patient is told about a new offer
+type OfferNotice struct {
+	WaitlistOffer
+	PatientName  string
+	PatientEmail string
+	DoctorName   string
+}
+
+// ReleasedCapacity is room a doctor has again after a cancellation or a change of working hours, time off or policy.
+// A zero DoctorID stands for every doctor and a zero Date for every day.
+type ReleasedCapacity struct {
+	DoctorID    int
+	Date        time.Time
+	TraceParent string // Of the request that freed the capacity, so the promotion logs can be followed back to it
+}
+
+// InZone renders the times of the entry and its offers in a zone, its days stay clinic days
+func (e WaitlistEntry) InZone(zone Zone) WaitlistEntry {
+	e.CreatedAt = zone(e.CreatedAt)
+	e.Offers = InZone(e.Offers, zone)
+	return e
+}
+
+// InZone renders the times of the offer in a zone
+func (o WaitlistOffer) InZone(zone Zone) WaitlistOffer {
+	o.StartTime = zone(o.StartTime)
+	o.EndTime = zone(o.EndTime)
+	o.OfferedAt = zone(o.OfferedAt)
+	o.ExpiresAt = zone(o.ExpiresAt)
+	o.RespondedAt = zone.at(o.RespondedAt)
+	return o
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Zone renders a time in the timezone a response is given in, such as clinictime.In for the clinic's own
+type Zone func(time.Time) time.Time
+
+// at renders an optional time
+func (zone Zone) at(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	inZone := zone(*t)
+	return &inZone
+}
+
+// InZone renders the times of every item in a zone. Only the offset changes, the instants stay the same.
+func InZone[T interface{ InZone(Zone) T }](items []T, zone Zone) []T {
+	if items == nil {
+		return nil
+	}
+	inZone := make([]T, len(items))
+	for i, item := range items {
+		inZone[i] = item.InZone(zone)
+	}
+	return inZone
+}
@@ -0,0 +1,2 @@
+ALTER TABLE Users
+ALTER COLUMN password TYPE VARCHAR(25);
@@ -0,0 +1,4 @@
+-- Widen the password column so bcrypt hashes fit.
+-- Existing plaintext passwords are upgraded to a hash on the user's next successful login.
+ALTER TABLE Users
+ALTER COLUMN password TYPE VARCHAR(255);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS RevokedTokens CASCADE;
+
+DROP TABLE IF EXISTS RefreshTokens CASCADE;
@@ -0,0 +1,23 @@
+-- Refresh tokens, only the SHA-256 hash of a token is stored.
+-- Every login starts a new family, rotating a token adds the next token to the same family.
+CREATE TABLE IF NOT EXISTS RefreshTokens (
+    token_id SERIAL PRIMARY KEY,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    family_id UUID NOT NULL,
+    user_id INT REFERENCES Users(user_id) ON DELETE CASCADE,
+    access_jti UUID NOT NULL,
+    access_expires_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    rotated_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family ON RefreshTokens (family_id);
+
+-- Access tokens that were revoked before their expiry
+CREATE TABLE IF NOT EXISTS RevokedTokens (
+    jti UUID PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
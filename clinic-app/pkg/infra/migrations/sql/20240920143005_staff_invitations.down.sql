@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS InvitationEvents CASCADE;
+
+DROP TABLE IF EXISTS Invitations CASCADE;
@@ -0,0 +1,26 @@
+-- Staff accounts (doctors and admins) are created by invitation only.
+-- Only the SHA-256 hash of an invitation token is stored.
+CREATE TABLE IF NOT EXISTS Invitations (
+    invitation_id SERIAL PRIMARY KEY,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    email VARCHAR(50) NOT NULL,
+    role VARCHAR(25) CHECK (role IN ('doctor', 'admin')) NOT NULL,
+    invited_by INT REFERENCES Users(user_id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    accepted_user_id INT REFERENCES Users(user_id) ON DELETE SET NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Audit trail of everything that happens to an invitation
+CREATE TABLE IF NOT EXISTS InvitationEvents (
+    event_id SERIAL PRIMARY KEY,
+    invitation_id INT REFERENCES Invitations(invitation_id) ON DELETE CASCADE,
+    event VARCHAR(25) CHECK (event IN ('created', 'accepted', 'revoked', 'rejected')) NOT NULL,
+    actor_id INT REFERENCES Users(user_id) ON DELETE SET NULL,
+    details TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_invitation_events_invitation ON InvitationEvents (invitation_id);
@@ -0,0 +1,20 @@
+-- Set once the owner of an account has proven they can read its email
+ALTER TABLE Users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP;
+
+-- Accounts created before verification existed keep booking, they count as verified since they were created
+UPDATE Users
+SET email_verified_at = COALESCE(created_at, NOW())
+WHERE email_verified_at IS NULL;
+
+-- Single-use tokens mailed to users, only the SHA-256 hash of a token is stored
+CREATE TABLE IF NOT EXISTS UserTokens (
+    token_id SERIAL PRIMARY KEY,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    user_id INT REFERENCES Users(user_id) ON DELETE CASCADE,
+    purpose VARCHAR(25) CHECK (purpose IN ('verify_email', 'reset_password')) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_tokens_user ON UserTokens (user_id, purpose);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS RecoveryCodes CASCADE;
+
+DROP TABLE IF EXISTS UserMFA CASCADE;
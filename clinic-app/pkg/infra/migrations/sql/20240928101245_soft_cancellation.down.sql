@@ -0,0 +1,35 @@
+DROP TRIGGER IF EXISTS trigger_release_schedule_on_cancellation ON Appointment;
+DROP FUNCTION IF EXISTS release_schedule_on_cancellation();
+
+-- Remove cancelled appointments while the deletion trigger still skips them
+DELETE FROM Appointment WHERE status = 'canceled';
+
+-- Restore the original deletion trigger function
+CREATE OR REPLACE FUNCTION update_schedule_on_cancellation()
+RETURNS TRIGGER AS $$
+DECLARE
+    appointment_duration INTERVAL;
+BEGIN
+    SELECT end_time - start_time INTO appointment_duration
+    FROM Appointment
+    WHERE appointment_id = OLD.appointment_id;
+
+    UPDATE Schedules
+    SET total_appointment_time = total_appointment_time - appointment_duration,
+        total_appointments = total_appointments - 1,
+        availability = CASE
+                        WHEN total_appointments = 12 OR total_appointment_time + appointment_duration = '08:00:00'
+                        THEN 'unavailable'
+                        ELSE 'available'
+                       END
+    WHERE doctor_id = OLD.doctor_id
+    AND date = OLD.appointment_date;
+
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE Appointment
+DROP COLUMN IF EXISTS cancel_reason,
+DROP COLUMN IF EXISTS canceled_at,
+DROP COLUMN IF EXISTS canceled_by;
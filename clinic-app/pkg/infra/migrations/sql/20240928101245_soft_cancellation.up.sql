@@ -0,0 +1,62 @@
+-- Cancelled appointments are kept with their status set to 'canceled' instead of being deleted.
+ALTER TABLE Appointment
+ADD COLUMN canceled_by INT REFERENCES Users(user_id) ON DELETE SET NULL,
+ADD COLUMN canceled_at TIMESTAMP,
+ADD COLUMN cancel_reason VARCHAR(255);
+
+-- Function to release schedule metrics when an appointment is cancelled
+CREATE OR REPLACE FUNCTION release_schedule_on_cancellation()
+RETURNS TRIGGER AS $$
+DECLARE
+    appointment_duration INTERVAL;
+BEGIN
+    appointment_duration := OLD.end_time - OLD.start_time;
+
+    UPDATE Schedules
+    SET total_appointment_time = total_appointment_time - appointment_duration,
+        total_appointments = total_appointments - 1,
+        availability = CASE
+                        WHEN total_appointments - 1 >= 12 OR total_appointment_time - appointment_duration >= '08:00:00'
+                        THEN 'unavailable'
+                        ELSE 'available'
+                       END
+    WHERE doctor_id = OLD.doctor_id
+    AND date = OLD.appointment_date;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Trigger to release schedule metrics once an appointment is cancelled
+CREATE TRIGGER trigger_release_schedule_on_cancellation
+AFTER UPDATE OF status ON Appointment
+FOR EACH ROW
+WHEN (OLD.status <> 'canceled' AND NEW.status = 'canceled')
+EXECUTE FUNCTION release_schedule_on_cancellation();
+
+-- Cancelled appointments no longer hold the schedule, so deleting one must not release it again
+CREATE OR REPLACE FUNCTION update_schedule_on_cancellation()
+RETURNS TRIGGER AS $$
+DECLARE
+    appointment_duration INTERVAL;
+BEGIN
+    IF OLD.status = 'canceled' THEN
+        RETURN OLD;
+    END IF;
+
+    appointment_duration := OLD.end_time - OLD.start_time;
+
+    UPDATE Schedules
+    SET total_appointment_time = total_appointment_time - appointment_duration,
+        total_appointments = total_appointments - 1,
+        availability = CASE
+                        WHEN total_appointments - 1 >= 12 OR total_appointment_time - appointment_duration >= '08:00:00'
+                        THEN 'unavailable'
+                        ELSE 'available'
+                       END
+    WHERE doctor_id = OLD.doctor_id
+    AND date = OLD.appointment_date;
+
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
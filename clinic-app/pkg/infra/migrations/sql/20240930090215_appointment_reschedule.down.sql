@@ -0,0 +1,2 @@
+ALTER TABLE Appointment
+DROP COLUMN IF EXISTS rescheduled_from;
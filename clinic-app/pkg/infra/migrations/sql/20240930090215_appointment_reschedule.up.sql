@@ -0,0 +1,3 @@
+-- A rescheduled appointment is cancelled and booked again, the new booking points back to the original one.
+ALTER TABLE Appointment
+ADD COLUMN rescheduled_from INT REFERENCES Appointment(appointment_id) ON DELETE SET NULL;
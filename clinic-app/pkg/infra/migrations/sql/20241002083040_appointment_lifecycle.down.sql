@@ -0,0 +1,18 @@
+DROP INDEX IF EXISTS idx_appointment_scheduled_end_time;
+
+-- Fold the new statuses back into the original ones
+UPDATE Appointment SET status = 'scheduled' WHERE status IN ('checked_in', 'in_progress');
+UPDATE Appointment SET status = 'canceled' WHERE status = 'no_show';
+
+ALTER TABLE Appointment
+DROP CONSTRAINT IF EXISTS appointment_status_check;
+
+ALTER TABLE Appointment
+ADD CONSTRAINT appointment_status_check
+CHECK (status IN ('scheduled', 'completed', 'canceled'));
+
+ALTER TABLE Appointment
+DROP COLUMN IF EXISTS no_show_at,
+DROP COLUMN IF EXISTS completed_at,
+DROP COLUMN IF EXISTS started_at,
+DROP COLUMN IF EXISTS checked_in_at;
@@ -0,0 +1,17 @@
+-- Appointments move through scheduled -> checked_in -> in_progress -> completed, or end as canceled or no_show.
+ALTER TABLE Appointment
+DROP CONSTRAINT IF EXISTS appointment_status_check;
+
+ALTER TABLE Appointment
+ADD CONSTRAINT appointment_status_check
+CHECK (status IN ('scheduled', 'checked_in', 'in_progress', 'completed', 'canceled', 'no_show'));
+
+-- When each step was taken
+ALTER TABLE Appointment
+ADD COLUMN checked_in_at TIMESTAMP,
+ADD COLUMN started_at TIMESTAMP,
+ADD COLUMN completed_at TIMESTAMP,
+ADD COLUMN no_show_at TIMESTAMP;
+
+-- The no-show sweep looks for past appointments that are still scheduled
+CREATE INDEX IF NOT EXISTS idx_appointment_scheduled_end_time ON Appointment (end_time) WHERE status = 'scheduled';
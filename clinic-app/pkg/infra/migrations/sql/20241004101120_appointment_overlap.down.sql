@@ -0,0 +1,6 @@
+-- Appointments cancelled for overlapping stay cancelled, their times may have been booked again since
+ALTER TABLE Appointment
+DROP CONSTRAINT IF EXISTS appointment_patient_no_overlap;
+
+ALTER TABLE Appointment
+DROP CONSTRAINT IF EXISTS appointment_doctor_no_overlap;
@@ -0,0 +1,46 @@
+-- btree_gist lets the exclusion constraints combine the equality on the user with the overlap of the time ranges
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Appointments booked before the constraints may already overlap, of overlapping appointments the earliest is kept
+-- and the others are cancelled. They are walked through in order, so an appointment only gives way to one that stays.
+DO $$
+DECLARE
+    aptmt RECORD;
+BEGIN
+    FOR aptmt IN
+        SELECT appointment_id, doctor_id, patient_id, start_time, end_time
+        FROM Appointment
+        WHERE status NOT IN ('canceled', 'no_show')
+        ORDER BY start_time, appointment_id
+    LOOP
+        IF EXISTS (
+            SELECT 1
+            FROM Appointment kept
+            WHERE kept.status NOT IN ('canceled', 'no_show')
+            AND (kept.doctor_id = aptmt.doctor_id OR kept.patient_id = aptmt.patient_id)
+            AND (kept.start_time, kept.appointment_id) < (aptmt.start_time, aptmt.appointment_id)
+            AND tsrange(kept.start_time, kept.end_time) && tsrange(aptmt.start_time, aptmt.end_time)
+        ) THEN
+            UPDATE Appointment
+            SET status = 'canceled',
+                canceled_at = NOW(),
+                cancel_reason = 'Overlapped an earlier appointment'
+            WHERE appointment_id = aptmt.appointment_id;
+
+            DELETE FROM Slot WHERE appointment_id = aptmt.appointment_id;
+        END IF;
+    END LOOP;
+END $$;
+
+-- A doctor cannot have two appointments at overlapping times, cancelled and missed appointments do not count.
+-- Ranges are half-open, so an appointment may start exactly when the previous one ends.
+ALTER TABLE Appointment
+ADD CONSTRAINT appointment_doctor_no_overlap
+EXCLUDE USING gist (doctor_id WITH =, tsrange(start_time, end_time) WITH &&)
+WHERE (status NOT IN ('canceled', 'no_show'));
+
+-- Neither can a patient, even with different doctors
+ALTER TABLE Appointment
+ADD CONSTRAINT appointment_patient_no_overlap
+EXCLUDE USING gist (patient_id WITH =, tsrange(start_time, end_time) WITH &&)
+WHERE (status NOT IN ('canceled', 'no_show'));
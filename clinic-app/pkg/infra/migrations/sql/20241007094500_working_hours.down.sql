@@ -0,0 +1,113 @@
+-- Restore the schedule functions as they were before working hours
+CREATE OR REPLACE FUNCTION update_schedule_record()
+RETURNS TRIGGER AS $$
+DECLARE
+    appointment_duration INTERVAL;
+BEGIN
+    appointment_duration := NEW.end_time - NEW.start_time;
+
+    IF EXISTS (
+        SELECT 1
+        FROM Schedules
+        WHERE doctor_id = NEW.doctor_id
+        AND (date = NEW.appointment_date OR date IS NULL)
+    ) THEN
+        UPDATE Schedules
+        SET total_appointment_time = total_appointment_time + appointment_duration,
+            total_appointments = total_appointments + 1,
+            date = COALESCE(date, NEW.appointment_date),
+            availability = CASE
+                WHEN total_appointments + 1 >= 12 OR total_appointment_time + appointment_duration >= '08:00:00'
+                THEN 'unavailable'
+                ELSE 'available'
+            END
+        WHERE doctor_id = NEW.doctor_id
+        AND (date = NEW.appointment_date OR date IS NULL);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION release_schedule_on_cancellation()
+RETURNS TRIGGER AS $$
+DECLARE
+    appointment_duration INTERVAL;
+BEGIN
+    appointment_duration := OLD.end_time - OLD.start_time;
+
+    UPDATE Schedules
+    SET total_appointment_time = total_appointment_time - appointment_duration,
+        total_appointments = total_appointments - 1,
+        availability = CASE
+                        WHEN total_appointments - 1 >= 12 OR total_appointment_time - appointment_duration >= '08:00:00'
+                        THEN 'unavailable'
+                        ELSE 'available'
+                       END
+    WHERE doctor_id = OLD.doctor_id
+    AND date = OLD.appointment_date;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION update_schedule_on_cancellation()
+RETURNS TRIGGER AS $$
+DECLARE
+    appointment_duration INTERVAL;
+BEGIN
+    IF OLD.status = 'canceled' THEN
+        RETURN OLD;
+    END IF;
+
+    appointment_duration := OLD.end_time - OLD.start_time;
+
+    UPDATE Schedules
+    SET total_appointment_time = total_appointment_time - appointment_duration,
+        total_appointments = total_appointments - 1,
+        availability = CASE
+                        WHEN total_appointments - 1 >= 12 OR total_appointment_time - appointment_duration >= '08:00:00'
+                        THEN 'unavailable'
+                        ELSE 'available'
+                       END
+    WHERE doctor_id = OLD.doctor_id
+    AND date = OLD.appointment_date;
+
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP FUNCTION IF EXISTS materialise_schedules(INT, DATE, DATE);
+
+DROP TABLE IF EXISTS ScheduleBlocks CASCADE;
+
+DROP INDEX IF EXISTS idx_schedules_doctor_date;
+
+ALTER TABLE Schedules
+DROP COLUMN IF EXISTS max_appointment_time,
+DROP COLUMN IF EXISTS max_appointments,
+ALTER COLUMN date DROP NOT NULL,
+ALTER COLUMN date TYPE TIMESTAMP;
+
+-- Give every doctor an undated schedule again
+CREATE OR REPLACE FUNCTION insert_schedule_for_doctor()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.role = 'doctor' THEN
+        INSERT INTO Schedules (doctor_id, date, total_appointment_time, total_appointments, availability, created_at)
+        VALUES (NEW.user_id, NULL, '00:00:00', 0, 'available', CURRENT_TIMESTAMP);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_insert_schedule_for_doctor
+AFTER INSERT ON Users
+FOR EACH ROW
+EXECUTE FUNCTION insert_schedule_for_doctor();
+
+INSERT INTO Schedules (doctor_id, date, total_appointment_time, total_appointments, availability)
+SELECT user_id, NULL, '00:00:00', 0, 'available'
+FROM Users
+WHERE role = 'doctor';
+
+DROP TABLE IF EXISTS WorkingHours CASCADE;
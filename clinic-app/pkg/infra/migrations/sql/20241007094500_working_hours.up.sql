@@ -0,0 +1,162 @@
+-- Weekly working hours of the doctors. Every row is one block on one weekday (0 = Sunday), so a lunch break
+-- is expressed as two blocks. A row applies between effective_from and effective_to, open ended without the latter.
+CREATE TABLE IF NOT EXISTS WorkingHours (
+    working_hours_id SERIAL PRIMARY KEY,
+    doctor_id INT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    effective_from DATE NOT NULL,
+    effective_to DATE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (start_time < end_time),
+    CHECK (effective_to IS NULL OR effective_to >= effective_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_working_hours_doctor ON WorkingHours (doctor_id, weekday);
+
+-- Schedules become one row per doctor and day, materialised from the working hours.
+-- The single undated row every doctor got on creation is no longer used.
+DROP TRIGGER IF EXISTS trigger_insert_schedule_for_doctor ON Users;
+DROP FUNCTION IF EXISTS insert_schedule_for_doctor();
+
+DELETE FROM Schedules WHERE date IS NULL;
+
+ALTER TABLE Schedules
+ALTER COLUMN date TYPE DATE,
+ALTER COLUMN date SET NOT NULL,
+ADD COLUMN max_appointments INT NOT NULL DEFAULT 12,
+ADD COLUMN max_appointment_time INTERVAL NOT NULL DEFAULT '08:00:00';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_schedules_doctor_date ON Schedules (doctor_id, date);
+
+-- Working blocks of a schedule day, appointments must fit into one of them
+CREATE TABLE IF NOT EXISTS ScheduleBlocks (
+    schedule_id INT NOT NULL REFERENCES Schedules(schedule_id) ON DELETE CASCADE,
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_schedule_blocks_schedule ON ScheduleBlocks (schedule_id);
+
+-- Function to materialise the schedules of a doctor between two days from the working hours.
+-- The time a doctor works on a day is its capacity. Days without working hours only keep a schedule
+-- when one already exists, its capacity drops to zero so nothing new can be booked.
+CREATE OR REPLACE FUNCTION materialise_schedules(p_doctor_id INT, p_from DATE, p_to DATE)
+RETURNS VOID AS $$
+DECLARE
+    schedule_day DATE;
+    day_schedule_id INT;
+    working_time INTERVAL;
+BEGIN
+    FOR schedule_day IN SELECT generate_series(p_from, p_to, INTERVAL '1 day')::DATE LOOP
+        SELECT COALESCE(SUM(end_time - start_time), '0') INTO working_time
+        FROM WorkingHours
+        WHERE doctor_id = p_doctor_id
+        AND weekday = EXTRACT(DOW FROM schedule_day)
+        AND effective_from <= schedule_day
+        AND (effective_to IS NULL OR effective_to >= schedule_day);
+
+        IF working_time = '0' AND NOT EXISTS (
+            SELECT 1 FROM Schedules WHERE doctor_id = p_doctor_id AND date = schedule_day
+        ) THEN
+            CONTINUE;
+        END IF;
+
+        INSERT INTO Schedules (doctor_id, date, max_appointment_time)
+        VALUES (p_doctor_id, schedule_day, working_time)
+        ON CONFLICT (doctor_id, date) DO UPDATE
+        SET max_appointment_time = EXCLUDED.max_appointment_time
+        RETURNING schedule_id INTO day_schedule_id;
+
+        DELETE FROM ScheduleBlocks WHERE schedule_id = day_schedule_id;
+
+        INSERT INTO ScheduleBlocks (schedule_id, start_time, end_time)
+        SELECT day_schedule_id, schedule_day + start_time, schedule_day + end_time
+        FROM WorkingHours
+        WHERE doctor_id = p_doctor_id
+        AND weekday = EXTRACT(DOW FROM schedule_day)
+        AND effective_from <= schedule_day
+        AND (effective_to IS NULL OR effective_to >= schedule_day);
+
+        UPDATE Schedules
+        SET availability = CASE
+                WHEN total_appointments >= max_appointments OR total_appointment_time >= max_appointment_time
+                THEN 'unavailable'
+                ELSE 'available'
+            END
+        WHERE schedule_id = day_schedule_id;
+    END LOOP;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Bookings count against the schedule of their day and its capacity
+CREATE OR REPLACE FUNCTION update_schedule_record()
+RETURNS TRIGGER AS $$
+DECLARE
+    appointment_duration INTERVAL;
+BEGIN
+    appointment_duration := NEW.end_time - NEW.start_time;
+
+    UPDATE Schedules
+    SET total_appointment_time = total_appointment_time + appointment_duration,
+        total_appointments = total_appointments + 1,
+        availability = CASE
+            WHEN total_appointments + 1 >= max_appointments OR total_appointment_time + appointment_duration >= max_appointment_time
+            THEN 'unavailable'
+            ELSE 'available'
+        END
+    WHERE doctor_id = NEW.doctor_id
+    AND date = NEW.appointment_date::DATE;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION release_schedule_on_cancellation()
+RETURNS TRIGGER AS $$
+DECLARE
+    appointment_duration INTERVAL;
+BEGIN
+    appointment_duration := OLD.end_time - OLD.start_time;
+
+    UPDATE Schedules
+    SET total_appointment_time = total_appointment_time - appointment_duration,
+        total_appointments = total_appointments - 1,
+        availability = CASE
+                        WHEN total_appointments - 1 >= max_appointments OR total_appointment_time - appointment_duration >= max_appointment_time
+                        THEN 'unavailable'
+                        ELSE 'available'
+                       END
+    WHERE doctor_id = OLD.doctor_id
+    AND date = OLD.appointment_date::DATE;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION update_schedule_on_cancellation()
+RETURNS TRIGGER AS $$
+DECLARE
+    appointment_duration INTERVAL;
+BEGIN
+    IF OLD.status IN ('canceled', 'no_show') THEN
+        RETURN OLD;
+    END IF;
+
+    appointment_duration := OLD.end_time - OLD.start_time;
+
+    UPDATE Schedules
+    SET total_appointment_time = total_appointment_time - appointment_duration,
+        total_appointments = total_appointments - 1,
+        availability = CASE
+                        WHEN total_appointments - 1 >= max_appointments OR total_appointment_time - appointment_duration >= max_appointment_time
+                        THEN 'unavailable'
+                        ELSE 'available'
+                       END
+    WHERE doctor_id = OLD.doctor_id
+    AND date = OLD.appointment_date::DATE;
+
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
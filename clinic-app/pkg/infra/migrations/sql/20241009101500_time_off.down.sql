@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS TimeOffBlocks;
+DROP TABLE IF EXISTS TimeOff;
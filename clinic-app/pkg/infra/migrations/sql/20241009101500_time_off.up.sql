@@ -0,0 +1,29 @@
+-- Time off of the doctors, requested by the doctor and approved by an admin.
+-- A request covers start_time to end_time, full days run from midnight to midnight.
+-- With repeat_until the same block recurs every week until that day.
+CREATE TABLE IF NOT EXISTS TimeOff (
+    time_off_id SERIAL PRIMARY KEY,
+    doctor_id INT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP NOT NULL,
+    repeat_until DATE,
+    reason VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'withdrawn')),
+    requested_by INT REFERENCES Users(user_id) ON DELETE SET NULL,
+    decided_by INT REFERENCES Users(user_id) ON DELETE SET NULL,
+    decided_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (start_time < end_time),
+    CHECK (repeat_until IS NULL OR repeat_until >= start_time::DATE)
+);
+
+CREATE INDEX IF NOT EXISTS idx_time_off_doctor ON TimeOff (doctor_id, status);
+
+-- Every occurrence of a time off, so recurring blocks can be checked like single ones
+CREATE TABLE IF NOT EXISTS TimeOffBlocks (
+    time_off_id INT NOT NULL REFERENCES TimeOff(time_off_id) ON DELETE CASCADE,
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_time_off_blocks_range ON TimeOffBlocks USING gist (tsrange(start_time, end_time));
@@ -0,0 +1,95 @@
+-- Restore the hard-coded duration limits of the slot trigger
+CREATE OR REPLACE FUNCTION update_slot_on_appointment()
+RETURNS TRIGGER AS $$
+DECLARE
+    appointment_duration INTERVAL;
+BEGIN
+    appointment_duration := NEW.end_time - NEW.start_time;
+
+    IF appointment_duration < '00:15:00' THEN
+        RAISE EXCEPTION 'Appointment duration is too short. Minimum duration is 15 minutes.'
+        USING ERRCODE = 'P0002';
+    ELSIF appointment_duration > '02:00:00' THEN
+        RAISE EXCEPTION 'Appointment duration exceeds the maximum limit. Maximum duration is 2 hours.'
+        USING ERRCODE = 'P0003';
+    END IF;
+
+    -- Check if a slot already exists
+    IF NOT EXISTS (
+        SELECT 1 
+        FROM Slot 
+        WHERE doctor_id = NEW.doctor_id 
+          AND start_time = NEW.start_time 
+          AND end_time = NEW.end_time
+    ) THEN
+        INSERT INTO Slot (appointment_id, doctor_id, start_time, end_time, duration, is_booked, created_at)
+        VALUES (
+            NEW.appointment_id,
+            NEW.doctor_id,
+            NEW.start_time,
+            NEW.end_time,
+            appointment_duration, 
+            TRUE, 
+            CURRENT_TIMESTAMP 
+        );
+    END IF;
+    
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Restore the schedule capacity from the working hours alone
+CREATE OR REPLACE FUNCTION materialise_schedules(p_doctor_id INT, p_from DATE, p_to DATE)
+RETURNS VOID AS $$
+DECLARE
+    schedule_day DATE;
+    day_schedule_id INT;
+    working_time INTERVAL;
+BEGIN
+    FOR schedule_day IN SELECT generate_series(p_from, p_to, INTERVAL '1 day')::DATE LOOP
+        SELECT COALESCE(SUM(end_time - start_time), '0') INTO working_time
+        FROM WorkingHours
+        WHERE doctor_id = p_doctor_id
+        AND weekday = EXTRACT(DOW FROM schedule_day)
+        AND effective_from <= schedule_day
+        AND (effective_to IS NULL OR effective_to >= schedule_day);
+
+        IF working_time = '0' AND NOT EXISTS (
+            SELECT 1 FROM Schedules WHERE doctor_id = p_doctor_id AND date = schedule_day
+        ) THEN
+            CONTINUE;
+        END IF;
+
+        INSERT INTO Schedules (doctor_id, date, max_appointment_time)
+        VALUES (p_doctor_id, schedule_day, working_time)
+        ON CONFLICT (doctor_id, date) DO UPDATE
+        SET max_appointment_time = EXCLUDED.max_appointment_time
+        RETURNING schedule_id INTO day_schedule_id;
+
+        DELETE FROM ScheduleBlocks WHERE schedule_id = day_schedule_id;
+
+        INSERT INTO ScheduleBlocks (schedule_id, start_time, end_time)
+        SELECT day_schedule_id, schedule_day + start_time, schedule_day + end_time
+        FROM WorkingHours
+        WHERE doctor_id = p_doctor_id
+        AND weekday = EXTRACT(DOW FROM schedule_day)
+        AND effective_from <= schedule_day
+        AND (effective_to IS NULL OR effective_to >= schedule_day);
+
+        UPDATE Schedules
+        SET availability = CASE
+                WHEN total_appointments >= max_appointments OR total_appointment_time >= max_appointment_time
+                THEN 'unavailable'
+                ELSE 'available'
+            END
+        WHERE schedule_id = day_schedule_id;
+    END LOOP;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE Schedules
+ALTER COLUMN max_appointments SET DEFAULT 12,
+ALTER COLUMN max_appointment_time SET DEFAULT '08:00:00';
+
+DROP FUNCTION IF EXISTS effective_booking_policy(INT);
+DROP TABLE IF EXISTS BookingPolicies;
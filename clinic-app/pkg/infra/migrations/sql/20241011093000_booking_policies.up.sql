@@ -0,0 +1,155 @@
+-- Booking rules. The row without a doctor is the clinic-wide default and sets every rule,
+-- a doctor's row overrides the rules it sets and falls back to the default for those left NULL.
+CREATE TABLE IF NOT EXISTS BookingPolicies (
+    booking_policy_id SERIAL PRIMARY KEY,
+    doctor_id INT UNIQUE REFERENCES Users(user_id) ON DELETE CASCADE,
+    max_daily_appointments INT CHECK (max_daily_appointments > 0),
+    max_daily_minutes INT CHECK (max_daily_minutes > 0),
+    min_duration_minutes INT CHECK (min_duration_minutes > 0),
+    max_duration_minutes INT CHECK (max_duration_minutes > 0),
+    buffer_minutes INT CHECK (buffer_minutes >= 0),
+    booking_horizon_days INT CHECK (booking_horizon_days > 0),
+    updated_by INT REFERENCES Users(user_id) ON DELETE SET NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (
+        doctor_id IS NOT NULL OR (
+            max_daily_appointments IS NOT NULL
+            AND max_daily_minutes IS NOT NULL
+            AND min_duration_minutes IS NOT NULL
+            AND max_duration_minutes IS NOT NULL
+            AND buffer_minutes IS NOT NULL
+            AND booking_horizon_days IS NOT NULL
+        )
+    )
+);
+
+-- Only one clinic-wide default
+CREATE UNIQUE INDEX IF NOT EXISTS idx_booking_policies_default ON BookingPolicies ((doctor_id IS NULL)) WHERE doctor_id IS NULL;
+
+-- The limits that used to be hard-coded
+INSERT INTO BookingPolicies (
+    max_daily_appointments,
+    max_daily_minutes,
+    min_duration_minutes,
+    max_duration_minutes,
+    buffer_minutes,
+    booking_horizon_days
+)
+VALUES (12, 480, 15, 120, 0, 60);
+
+-- Function returning the rules that apply to a doctor
+CREATE OR REPLACE FUNCTION effective_booking_policy(p_doctor_id INT)
+RETURNS TABLE (
+    max_daily_appointments INT,
+    max_daily_time INTERVAL,
+    min_duration INTERVAL,
+    max_duration INTERVAL,
+    buffer INTERVAL,
+    booking_horizon_days INT
+) AS $$
+    SELECT
+        COALESCE(o.max_daily_appointments, d.max_daily_appointments),
+        make_interval(mins => COALESCE(o.max_daily_minutes, d.max_daily_minutes)),
+        make_interval(mins => COALESCE(o.min_duration_minutes, d.min_duration_minutes)),
+        make_interval(mins => COALESCE(o.max_duration_minutes, d.max_duration_minutes)),
+        make_interval(mins => COALESCE(o.buffer_minutes, d.buffer_minutes)),
+        COALESCE(o.booking_horizon_days, d.booking_horizon_days)
+    FROM BookingPolicies d
+    LEFT JOIN BookingPolicies o ON o.doctor_id = p_doctor_id
+    WHERE d.doctor_id IS NULL;
+$$ LANGUAGE sql STABLE;
+
+-- The capacity of a schedule day now comes from the booking policy, capped by the time the doctor works that day
+ALTER TABLE Schedules
+ALTER COLUMN max_appointments DROP DEFAULT,
+ALTER COLUMN max_appointment_time DROP DEFAULT;
+
+CREATE OR REPLACE FUNCTION materialise_schedules(p_doctor_id INT, p_from DATE, p_to DATE)
+RETURNS VOID AS $$
+DECLARE
+    schedule_day DATE;
+    day_schedule_id INT;
+    working_time INTERVAL;
+    booking_policy RECORD;
+BEGIN
+    SELECT * INTO booking_policy FROM effective_booking_policy(p_doctor_id);
+
+    FOR schedule_day IN SELECT generate_series(p_from, p_to, INTERVAL '1 day')::DATE LOOP
+        SELECT COALESCE(SUM(end_time - start_time), '0') INTO working_time
+        FROM WorkingHours
+        WHERE doctor_id = p_doctor_id
+        AND weekday = EXTRACT(DOW FROM schedule_day)
+        AND effective_from <= schedule_day
+        AND (effective_to IS NULL OR effective_to >= schedule_day);
+
+        IF working_time = '0' AND NOT EXISTS (
+            SELECT 1 FROM Schedules WHERE doctor_id = p_doctor_id AND date = schedule_day
+        ) THEN
+            CONTINUE;
+        END IF;
+
+        INSERT INTO Schedules (doctor_id, date, max_appointments, max_appointment_time)
+        VALUES (p_doctor_id, schedule_day, booking_policy.max_daily_appointments, LEAST(working_time, booking_policy.max_daily_time))
+        ON CONFLICT (doctor_id, date) DO UPDATE
+        SET max_appointments = EXCLUDED.max_appointments,
+            max_appointment_time = EXCLUDED.max_appointment_time
+        RETURNING schedule_id INTO day_schedule_id;
+
+        DELETE FROM ScheduleBlocks WHERE schedule_id = day_schedule_id;
+
+        INSERT INTO ScheduleBlocks (schedule_id, start_time, end_time)
+        SELECT day_schedule_id, schedule_day + start_time, schedule_day + end_time
+        FROM WorkingHours
+        WHERE doctor_id = p_doctor_id
+        AND weekday = EXTRACT(DOW FROM schedule_day)
+        AND effective_from <= schedule_day
+        AND (effective_to IS NULL OR effective_to >= schedule_day);
+
+        UPDATE Schedules
+        SET availability = CASE
+                WHEN total_appointments >= max_appointments OR total_appointment_time >= max_appointment_time
+                THEN 'unavailable'
+                ELSE 'available'
+            END
+        WHERE schedule_id = day_schedule_id;
+    END LOOP;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Durations are checked against the booking policy when booking, the slot trigger only records the slot
+CREATE OR REPLACE FUNCTION update_slot_on_appointment()
+RETURNS TRIGGER AS $$
+DECLARE
+    appointment_duration INTERVAL;
+BEGIN
+    appointment_duration := NEW.end_time - NEW.start_time;
+
+    -- Check if a slot already exists
+    IF NOT EXISTS (
+        SELECT 1 
+        FROM Slot 
+        WHERE doctor_id = NEW.doctor_id 
+          AND start_time = NEW.start_time 
+          AND end_time = NEW.end_time
+    ) THEN
+        INSERT INTO Slot (appointment_id, doctor_id, start_time, end_time, duration, is_booked, created_at)
+        VALUES (
+            NEW.appointment_id,
+            NEW.doctor_id,
+            NEW.start_time,
+            NEW.end_time,
+            appointment_duration, 
+            TRUE, 
+            CURRENT_TIMESTAMP 
+        );
+    END IF;
+    
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Apply the policy to the schedules already materialised
+SELECT materialise_schedules(doctor_id, CURRENT_DATE, MAX(date))
+FROM Schedules
+WHERE date >= CURRENT_DATE
+GROUP BY doctor_id;
@@ -0,0 +1,16 @@
+-- Responses of mutating requests sent with an Idempotency-Key header, replayed when a client retries.
+-- Anonymous requests such as registration are stored under user 0 with their key scoped to the request. A row without a status code
+-- belongs to a request that is still being processed.
+CREATE TABLE IF NOT EXISTS IdempotencyKeys (
+    user_id INT NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status_code INT,
+    content_type VARCHAR(255),
+    response_body BYTEA,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON IdempotencyKeys (expires_at);
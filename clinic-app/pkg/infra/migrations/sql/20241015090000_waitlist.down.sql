@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS WaitlistOffers;
+DROP TABLE IF EXISTS WaitlistWindows;
+DROP TABLE IF EXISTS WaitlistEntries;
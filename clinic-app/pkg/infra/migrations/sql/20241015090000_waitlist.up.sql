@@ -0,0 +1,44 @@
+-- Patients waiting for a doctor to have room between two dates. An entry is offered one freed slot at a time.
+CREATE TABLE IF NOT EXISTS WaitlistEntries (
+    waitlist_entry_id SERIAL PRIMARY KEY,
+    patient_id INT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+    doctor_id INT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+    date_from DATE NOT NULL,
+    date_to DATE NOT NULL,
+    duration_minutes INT NOT NULL CHECK (duration_minutes > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'waiting' CHECK (status IN ('waiting', 'offered', 'booked', 'left', 'expired')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (date_from <= date_to)
+);
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_status ON WaitlistEntries (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_patient ON WaitlistEntries (patient_id);
+
+-- Preferred times of day of an entry, an entry without windows takes any time
+CREATE TABLE IF NOT EXISTS WaitlistWindows (
+    waitlist_entry_id INT NOT NULL REFERENCES WaitlistEntries(waitlist_entry_id) ON DELETE CASCADE,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    CHECK (start_time < end_time)
+);
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_windows_entry ON WaitlistWindows (waitlist_entry_id);
+
+-- Every slot offered to an entry. An open offer holds the slot for the patient until it expires.
+CREATE TABLE IF NOT EXISTS WaitlistOffers (
+    waitlist_offer_id SERIAL PRIMARY KEY,
+    waitlist_entry_id INT NOT NULL REFERENCES WaitlistEntries(waitlist_entry_id) ON DELETE CASCADE,
+    doctor_id INT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'accepted', 'declined', 'expired', 'withdrawn')),
+    offered_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    responded_at TIMESTAMP,
+    appointment_id INT REFERENCES Appointment(appointment_id) ON DELETE SET NULL,
+    CHECK (start_time < end_time)
+);
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_offers_doctor ON WaitlistOffers (doctor_id, status);
+-- An entry has at most one open offer at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_waitlist_offers_open ON WaitlistOffers (waitlist_entry_id) WHERE status = 'open';
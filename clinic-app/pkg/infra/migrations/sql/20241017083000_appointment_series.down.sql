@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_appointment_series;
+ALTER TABLE Appointment DROP COLUMN IF EXISTS series_id;
+DROP TABLE IF EXISTS AppointmentSeries;
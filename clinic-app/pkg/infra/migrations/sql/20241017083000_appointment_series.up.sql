@@ -0,0 +1,16 @@
+-- Recurring appointments of a patient, booked from a weekly recurrence rule. Every occurrence is an
+-- appointment of its own that points back to its series.
+CREATE TABLE IF NOT EXISTS AppointmentSeries (
+    series_id SERIAL PRIMARY KEY,
+    patient_id INT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+    doctor_id INT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+    rrule VARCHAR(255) NOT NULL,
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (start_time < end_time)
+);
+
+ALTER TABLE Appointment ADD COLUMN IF NOT EXISTS series_id INT REFERENCES AppointmentSeries(series_id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_appointment_series ON Appointment (series_id, start_time);
@@ -0,0 +1,18 @@
+-- Times a patient reserved while completing a booking. An active hold keeps the time and its share of the
+-- doctor's daily caps from everybody else until it is confirmed into an appointment, released or expires.
+CREATE TABLE IF NOT EXISTS AppointmentHolds (
+    hold_id SERIAL PRIMARY KEY,
+    patient_id INT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+    doctor_id INT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+    appointment_date DATE NOT NULL,
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'held' CHECK (status IN ('held', 'confirmed', 'released', 'expired')),
+    expires_at TIMESTAMP NOT NULL,
+    appointment_id INT REFERENCES Appointment(appointment_id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (start_time < end_time)
+);
+
+CREATE INDEX IF NOT EXISTS idx_appointment_holds_doctor ON AppointmentHolds (doctor_id, appointment_date) WHERE status = 'held';
+CREATE INDEX IF NOT EXISTS idx_appointment_holds_expires_at ON AppointmentHolds (expires_at) WHERE status = 'held';
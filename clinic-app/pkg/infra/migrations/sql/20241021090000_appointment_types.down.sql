@@ -0,0 +1,6 @@
+ALTER TABLE AppointmentHolds DROP COLUMN IF EXISTS reason;
+ALTER TABLE AppointmentHolds DROP COLUMN IF EXISTS type_id;
+ALTER TABLE Appointment DROP COLUMN IF EXISTS reason;
+ALTER TABLE Appointment DROP COLUMN IF EXISTS type_id;
+DROP TABLE IF EXISTS DoctorAppointmentTypes;
+DROP TABLE IF EXISTS AppointmentTypes;
@@ -0,0 +1,35 @@
+-- Kinds of visit the clinic offers. Each type brings the duration a booking gets when it leaves out its end,
+-- and the bounds its duration has to stay within on top of the doctor's booking policy.
+CREATE TABLE IF NOT EXISTS AppointmentTypes (
+    type_id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    default_duration_minutes INT NOT NULL,
+    min_duration_minutes INT NOT NULL,
+    max_duration_minutes INT NOT NULL,
+    color VARCHAR(7) NOT NULL CHECK (color ~ '^#[0-9A-Fa-f]{6}$'),
+    prep_instructions TEXT NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (min_duration_minutes > 0 AND min_duration_minutes <= default_duration_minutes AND default_duration_minutes <= max_duration_minutes)
+);
+
+INSERT INTO AppointmentTypes (name, default_duration_minutes, min_duration_minutes, max_duration_minutes, color, prep_instructions)
+VALUES
+    ('New consultation', 30, 20, 60, '#2E86DE', 'Please bring your ID, insurance card and a list of your current medication.'),
+    ('Follow-up', 15, 10, 30, '#27AE60', ''),
+    ('Procedure', 60, 30, 120, '#C0392B', 'Please arrive 15 minutes early. Follow any fasting instructions you were given.')
+ON CONFLICT (name) DO NOTHING;
+
+-- The types a doctor offers. A doctor without any row here offers every active type.
+CREATE TABLE IF NOT EXISTS DoctorAppointmentTypes (
+    doctor_id INT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+    type_id INT NOT NULL REFERENCES AppointmentTypes(type_id) ON DELETE CASCADE,
+    PRIMARY KEY (doctor_id, type_id)
+);
+
+ALTER TABLE Appointment ADD COLUMN IF NOT EXISTS type_id INT REFERENCES AppointmentTypes(type_id);
+ALTER TABLE Appointment ADD COLUMN IF NOT EXISTS reason VARCHAR(500);
+
+ALTER TABLE AppointmentHolds ADD COLUMN IF NOT EXISTS type_id INT REFERENCES AppointmentTypes(type_id);
+ALTER TABLE AppointmentHolds ADD COLUMN IF NOT EXISTS reason VARCHAR(500);
@@ -0,0 +1,120 @@
+ALTER TABLE Appointment DROP CONSTRAINT IF EXISTS appointment_doctor_no_overlap;
+ALTER TABLE Appointment DROP CONSTRAINT IF EXISTS appointment_patient_no_overlap;
+DROP INDEX IF EXISTS idx_time_off_blocks_range;
+
+-- Calendar times go back to the clinic's wall clock
+ALTER TABLE Appointment
+ALTER COLUMN start_time TYPE TIMESTAMP USING start_time AT TIME ZONE current_setting('TimeZone'),
+ALTER COLUMN end_time TYPE TIMESTAMP USING end_time AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE Slot
+ALTER COLUMN start_time TYPE TIMESTAMP USING start_time AT TIME ZONE current_setting('TimeZone'),
+ALTER COLUMN end_time TYPE TIMESTAMP USING end_time AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE ScheduleBlocks
+ALTER COLUMN start_time TYPE TIMESTAMP USING start_time AT TIME ZONE current_setting('TimeZone'),
+ALTER COLUMN end_time TYPE TIMESTAMP USING end_time AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE TimeOff
+ALTER COLUMN start_time TYPE TIMESTAMP USING start_time AT TIME ZONE current_setting('TimeZone'),
+ALTER COLUMN end_time TYPE TIMESTAMP USING end_time AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE TimeOffBlocks
+ALTER COLUMN start_time TYPE TIMESTAMP USING start_time AT TIME ZONE current_setting('TimeZone'),
+ALTER COLUMN end_time TYPE TIMESTAMP USING end_time AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE WaitlistOffers
+ALTER COLUMN start_time TYPE TIMESTAMP USING start_time AT TIME ZONE current_setting('TimeZone'),
+ALTER COLUMN end_time TYPE TIMESTAMP USING end_time AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE AppointmentSeries
+ALTER COLUMN start_time TYPE TIMESTAMP USING start_time AT TIME ZONE current_setting('TimeZone'),
+ALTER COLUMN end_time TYPE TIMESTAMP USING end_time AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE AppointmentHolds
+ALTER COLUMN start_time TYPE TIMESTAMP USING start_time AT TIME ZONE current_setting('TimeZone'),
+ALTER COLUMN end_time TYPE TIMESTAMP USING end_time AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE Appointment ALTER COLUMN appointment_date TYPE TIMESTAMP USING appointment_date::TIMESTAMP;
+
+-- Every other timestamp goes back to the wall clock of the session timezone, like NOW() writes it
+DO $$
+DECLARE
+    col RECORD;
+BEGIN
+    FOR col IN
+        SELECT table_name, column_name
+        FROM information_schema.columns
+        WHERE table_schema = current_schema()
+        AND data_type = 'timestamp with time zone'
+    LOOP
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN %I TYPE TIMESTAMP USING %I AT TIME ZONE %L',
+            col.table_name, col.column_name, col.column_name, current_setting('TimeZone'));
+    END LOOP;
+END;
+$$;
+
+ALTER TABLE Appointment
+ADD CONSTRAINT appointment_doctor_no_overlap
+EXCLUDE USING gist (doctor_id WITH =, tsrange(start_time, end_time) WITH &&)
+WHERE (status NOT IN ('canceled', 'no_show'));
+
+ALTER TABLE Appointment
+ADD CONSTRAINT appointment_patient_no_overlap
+EXCLUDE USING gist (patient_id WITH =, tsrange(start_time, end_time) WITH &&)
+WHERE (status NOT IN ('canceled', 'no_show'));
+
+CREATE INDEX IF NOT EXISTS idx_time_off_blocks_range ON TimeOffBlocks USING gist (tsrange(start_time, end_time));
+
+-- Restore counting working hours as clock hours
+CREATE OR REPLACE FUNCTION materialise_schedules(p_doctor_id INT, p_from DATE, p_to DATE)
+RETURNS VOID AS $$
+DECLARE
+    schedule_day DATE;
+    day_schedule_id INT;
+    working_time INTERVAL;
+    booking_policy RECORD;
+BEGIN
+    SELECT * INTO booking_policy FROM effective_booking_policy(p_doctor_id);
+
+    FOR schedule_day IN SELECT generate_series(p_from, p_to, INTERVAL '1 day')::DATE LOOP
+        SELECT COALESCE(SUM(end_time - start_time), '0') INTO working_time
+        FROM WorkingHours
+        WHERE doctor_id = p_doctor_id
+        AND weekday = EXTRACT(DOW FROM schedule_day)
+        AND effective_from <= schedule_day
+        AND (effective_to IS NULL OR effective_to >= schedule_day);
+
+        IF working_time = '0' AND NOT EXISTS (
+            SELECT 1 FROM Schedules WHERE doctor_id = p_doctor_id AND date = schedule_day
+        ) THEN
+            CONTINUE;
+        END IF;
+
+        INSERT INTO Schedules (doctor_id, date, max_appointments, max_appointment_time)
+        VALUES (p_doctor_id, schedule_day, booking_policy.max_daily_appointments, LEAST(working_time, booking_policy.max_daily_time))
+        ON CONFLICT (doctor_id, date) DO UPDATE
+        SET max_appointments = EXCLUDED.max_appointments,
+            max_appointment_time = EXCLUDED.max_appointment_time
+        RETURNING schedule_id INTO day_schedule_id;
+
+        DELETE FROM ScheduleBlocks WHERE schedule_id = day_schedule_id;
+
+        INSERT INTO ScheduleBlocks (schedule_id, start_time, end_time)
+        SELECT day_schedule_id, schedule_day + start_time, schedule_day + end_time
+        FROM WorkingHours
+        WHERE doctor_id = p_doctor_id
+        AND weekday = EXTRACT(DOW FROM schedule_day)
+        AND effective_from <= schedule_day
+        AND (effective_to IS NULL OR effective_to >= schedule_day);
+
+        UPDATE Schedules
+        SET availability = CASE
+                WHEN total_appointments >= max_appointments OR total_appointment_time >= max_appointment_time
+                THEN 'unavailable'
+                ELSE 'available'
+            END
+        WHERE schedule_id = day_schedule_id;
+    END LOOP;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,133 @@
+-- Times become instants. The application connects with the clinic timezone as session timezone, so times without
+-- an offset, like the working hours materialised into schedule blocks, are read as the clinic's wall clock.
+
+-- The overlap checks are defined on tsrange and are rebuilt on tstzrange below
+ALTER TABLE Appointment DROP CONSTRAINT IF EXISTS appointment_doctor_no_overlap;
+ALTER TABLE Appointment DROP CONSTRAINT IF EXISTS appointment_patient_no_overlap;
+DROP INDEX IF EXISTS idx_time_off_blocks_range;
+
+-- The day of an appointment is a calendar day of the clinic, like the day of a schedule
+ALTER TABLE Appointment ALTER COLUMN appointment_date TYPE DATE USING appointment_date::DATE;
+
+-- Calendar times were stored as the clinic's wall clock, converting them reads them in the session timezone
+ALTER TABLE Appointment
+ALTER COLUMN start_time TYPE TIMESTAMPTZ USING start_time AT TIME ZONE current_setting('TimeZone'),
+ALTER COLUMN end_time TYPE TIMESTAMPTZ USING end_time AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE Slot
+ALTER COLUMN start_time TYPE TIMESTAMPTZ USING start_time AT TIME ZONE current_setting('TimeZone'),
+ALTER COLUMN end_time TYPE TIMESTAMPTZ USING end_time AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE ScheduleBlocks
+ALTER COLUMN start_time TYPE TIMESTAMPTZ USING start_time AT TIME ZONE current_setting('TimeZone'),
+ALTER COLUMN end_time TYPE TIMESTAMPTZ USING end_time AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE TimeOff
+ALTER COLUMN start_time TYPE TIMESTAMPTZ USING start_time AT TIME ZONE current_setting('TimeZone'),
+ALTER COLUMN end_time TYPE TIMESTAMPTZ USING end_time AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE TimeOffBlocks
+ALTER COLUMN start_time TYPE TIMESTAMPTZ USING start_time AT TIME ZONE current_setting('TimeZone'),
+ALTER COLUMN end_time TYPE TIMESTAMPTZ USING end_time AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE WaitlistOffers
+ALTER COLUMN start_time TYPE TIMESTAMPTZ USING start_time AT TIME ZONE current_setting('TimeZone'),
+ALTER COLUMN end_time TYPE TIMESTAMPTZ USING end_time AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE AppointmentSeries
+ALTER COLUMN start_time TYPE TIMESTAMPTZ USING start_time AT TIME ZONE current_setting('TimeZone'),
+ALTER COLUMN end_time TYPE TIMESTAMPTZ USING end_time AT TIME ZONE current_setting('TimeZone');
+
+ALTER TABLE AppointmentHolds
+ALTER COLUMN start_time TYPE TIMESTAMPTZ USING start_time AT TIME ZONE current_setting('TimeZone'),
+ALTER COLUMN end_time TYPE TIMESTAMPTZ USING end_time AT TIME ZONE current_setting('TimeZone');
+
+-- Every other timestamp records when something happened or expires. NOW() wrote those as the wall clock of the
+-- session timezone, so they are read in the timezone of the session the migration runs in instead of assuming UTC.
+DO $$
+DECLARE
+    col RECORD;
+BEGIN
+    FOR col IN
+        SELECT table_name, column_name
+        FROM information_schema.columns
+        WHERE table_schema = current_schema()
+        AND data_type = 'timestamp without time zone'
+    LOOP
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN %I TYPE TIMESTAMPTZ USING %I AT TIME ZONE %L',
+            col.table_name, col.column_name, col.column_name, current_setting('TimeZone'));
+    END LOOP;
+END;
+$$;
+
+ALTER TABLE Appointment
+ADD CONSTRAINT appointment_doctor_no_overlap
+EXCLUDE USING gist (doctor_id WITH =, tstzrange(start_time, end_time) WITH &&)
+WHERE (status NOT IN ('canceled', 'no_show'));
+
+ALTER TABLE Appointment
+ADD CONSTRAINT appointment_patient_no_overlap
+EXCLUDE USING gist (patient_id WITH =, tstzrange(start_time, end_time) WITH &&)
+WHERE (status NOT IN ('canceled', 'no_show'));
+
+CREATE INDEX IF NOT EXISTS idx_time_off_blocks_range ON TimeOffBlocks USING gist (tstzrange(start_time, end_time));
+
+-- Working hours are wall-clock times of the clinic. On the days daylight saving time starts or ends,
+-- the time a doctor works is an hour shorter or longer than the hours on the clock suggest.
+CREATE OR REPLACE FUNCTION materialise_schedules(p_doctor_id INT, p_from DATE, p_to DATE)
+RETURNS VOID AS $$
+DECLARE
+    schedule_day DATE;
+    day_schedule_id INT;
+    working_time INTERVAL;
+    booking_policy RECORD;
+BEGIN
+    SELECT * INTO booking_policy FROM effective_booking_policy(p_doctor_id);
+
+    FOR schedule_day IN SELECT generate_series(p_from, p_to, INTERVAL '1 day')::DATE LOOP
+        SELECT COALESCE(SUM((schedule_day + end_time)::TIMESTAMPTZ - (schedule_day + start_time)::TIMESTAMPTZ), '0') INTO working_time
+        FROM WorkingHours
+        WHERE doctor_id = p_doctor_id
+        AND weekday = EXTRACT(DOW FROM schedule_day)
+        AND effective_from <= schedule_day
+        AND (effective_to IS NULL OR effective_to >= schedule_day);
+
+        IF working_time = '0' AND NOT EXISTS (
+            SELECT 1 FROM Schedules WHERE doctor_id = p_doctor_id AND date = schedule_day
+        ) THEN
+            CONTINUE;
+        END IF;
+
+        INSERT INTO Schedules (doctor_id, date, max_appointments, max_appointment_time)
+        VALUES (p_doctor_id, schedule_day, booking_policy.max_daily_appointments, LEAST(working_time, booking_policy.max_daily_time))
+        ON CONFLICT (doctor_id, date) DO UPDATE
+        SET max_appointments = EXCLUDED.max_appointments,
+            max_appointment_time = EXCLUDED.max_appointment_time
+        RETURNING schedule_id INTO day_schedule_id;
+
+        DELETE FROM ScheduleBlocks WHERE schedule_id = day_schedule_id;
+
+        INSERT INTO ScheduleBlocks (schedule_id, start_time, end_time)
+        SELECT day_schedule_id, (schedule_day + start_time)::TIMESTAMPTZ, (schedule_day + end_time)::TIMESTAMPTZ
+        FROM WorkingHours
+        WHERE doctor_id = p_doctor_id
+        AND weekday = EXTRACT(DOW FROM schedule_day)
+        AND effective_from <= schedule_day
+        AND (effective_to IS NULL OR effective_to >= schedule_day);
+
+        UPDATE Schedules
+        SET availability = CASE
+                WHEN total_appointments >= max_appointments OR total_appointment_time >= max_appointment_time
+                THEN 'unavailable'
+                ELSE 'available'
+            END
+        WHERE schedule_id = day_schedule_id;
+    END LOOP;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Recompute the capacity of the upcoming days with the length of the days
+SELECT materialise_schedules(doctor_id, CURRENT_DATE, MAX(date))
+FROM Schedules
+WHERE date >= CURRENT_DATE
+GROUP BY doctor_id;
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+)
+
+// AppointmentTypeRepository defines methods for managing appointment types and the types doctors offer
+type AppointmentTypeRepository interface {
+	GetTypes(ftx factory.Service, includeInactive bool) ([]models.AppointmentType, error)
+	CreateType(ftx factory.Service, aptmtType models.AppointmentType) (models.AppointmentType, error)
+	UpdateType(ftx factory.Service, aptmtType models.AppointmentType) (models.AppointmentType, error)
+	DeactivateType(ftx factory.Service, typeId int) error
+	GetDoctorTypes(ftx factory.Service, doctorId int) ([]models.AppointmentType, error)
+	SetDoctorTypes(ftx factory.Service, doctorId int, typeIds []int) error
+	GetOfferedType(ftx factory.Service, doctorId, typeId int) (models.AppointmentType, error)
+}
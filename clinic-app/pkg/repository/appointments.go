@@ -3,13 +3,25 @@ package repository
 import (
 	"clinic-app/pkg/domain/models"
 	"clinic-app/pkg/services/factory"
+	"time"
 )
 
 // AppointmentRepository defines methods for managing appointments
 type AppointmentRepository interface {
 	BookAppointment(ftx factory.Service, aptmt models.BookAppointment) error
 	GetAppointmentById(ftx factory.Service, appointmentId int) (models.Appointment, error)
-	GetPatientHistory(ftx factory.Service, patientId int) ([]models.Appointment, error)
-	GetPatientAppointmentHistory(ftx factory.Service) ([]models.Appointment, error)
-	CancelAppointment(ftx factory.Service, appointmentId int) error
+	GetPatientHistory(ftx factory.Service, patientId int, includeCanceled bool) ([]models.Appointment, error)
+	GetPatientAppointmentHistory(ftx factory.Service, includeCanceled bool) ([]models.Appointment, error)
+	CancelAppointment(ftx factory.Service, cancel models.CancelAppointment, from string) error
+	GetAppointmentStatus(ftx factory.Service, appointmentId int) (models.Appointment, error)
+	UpdateAppointmentStatus(ftx factory.Service, appointmentId int, from, to string) error
+	MarkNoShows(ftx factory.Service, grace time.Duration) (int64, error)
+	RescheduleAppointment(ftx factory.Service, reschedule models.RescheduleAppointment) (int, error)
+	BookSeries(ftx factory.Service, series models.BookSeries, occurrences []models.BookAppointment) (models.AppointmentSeries, []models.SeriesOccurrence, error)
+	GetSeriesAppointments(ftx factory.Service, appointmentId int, scope string) ([]models.BookAppointment, error)
+	RescheduleSeries(ftx factory.Service, moves []models.RescheduleAppointment) ([]models.AffectedAppointment, error)
+	CreateHold(ftx factory.Service, aptmt models.BookAppointment, ttl time.Duration) (models.AppointmentHold, error)
+	ConfirmHold(ftx factory.Service, holdId int) (int, error)
+	ReleaseHold(ftx factory.Service, holdId int) error
+	ExpireHolds(ftx factory.Service) (int64, error)
 }
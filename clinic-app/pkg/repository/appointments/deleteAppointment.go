@@ -3,12 +3,15 @@ package appointments
 import (
 	"clinic-app/cmd/rest/middleware"
 	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
 	"clinic-app/pkg/services/factory"
+	"database/sql"
 
 	"go.uber.org/zap"
 )
 
-func (r *repo) CancelAppointment(ftx factory.Service, appointmentId int) error {
+// CancelAppointment cancels an appointment if it still has the status from, the caller checked the transition
+func (r *repo) CancelAppointment(ftx factory.Service, cancel models.CancelAppointment, from string) error {
 	// Start a new transaction
 	tx, err := ftx.TransactionManager().Begin()
 	if err != nil {
@@ -16,7 +19,7 @@ func (r *repo) CancelAppointment(ftx factory.Service, appointmentId int) error {
 		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
 		return errors.ErrDatabase
 	}
-	ftx.Logger().Info("Transaction started for deleting appointment")
+	ftx.Logger().Info("Transaction started for cancelling appointment")
 
 	// Defer a rollback in case anything fails
 	defer func() {
@@ -29,16 +32,26 @@ func (r *repo) CancelAppointment(ftx factory.Service, appointmentId int) error {
 		}
 	}()
 
-	// Execute query to cancel the appointment
-	_, err = tx.ExecContext(ftx.Context(), CancelAppointmentQuery, appointmentId)
+	// Execute query to cancel the appointment, nothing is updated if its status changed in the meantime
+	var canceledId int
+	err = tx.QueryRowContext(ftx.Context(), CancelAppointmentQuery,
+		cancel.AppointmentID,
+		ftx.Principal().UserID,
+		cancel.Reason,
+		from,
+	).Scan(&canceledId)
+	if err == sql.ErrNoRows {
+		// The appointment changed its status concurrently
+		return errors.ErrInvalidTransition
+	}
 	if err != nil {
-		// Log the error if the appointment could not be found or deleted
-		ftx.Logger().Error("Could not find Appointment", zap.Error(err))
-		return errors.ErrNotFound
+		// Log the error if the appointment could not be canceled
+		ftx.Logger().Error("Could not cancel Appointment", zap.Error(err))
+		return errors.ErrDatabase
 	}
 
-	// Execute query to delete the slot associated with the appointment
-	_, err = tx.ExecContext(ftx.Context(), DeleteSlotQuery, appointmentId)
+	// Execute query to delete the slot associated with the appointment, freeing the time for new bookings
+	_, err = tx.ExecContext(ftx.Context(), DeleteSlotQuery, cancel.AppointmentID)
 	if err != nil {
 		// Log the error if the slot could not be found or deleted
 		ftx.Logger().Error("Could not find Appointment", zap.Error(err))
@@ -52,7 +65,10 @@ func (r *repo) CancelAppointment(ftx factory.Service, appointmentId int) error {
 		return errors.ErrDatabase
 	}
 
-	ftx.Logger().Info("Successfully cancelled appointment")
+	ftx.Logger().Info("Successfully cancelled appointment",
+		zap.Int("Appointment ID", cancel.AppointmentID),
+		zap.Int("Canceled By", ftx.Principal().UserID),
+	)
 	// Optionally, use the traceparent for logging or tracing purposes
 	middleware.GetTraceParentFromContext(ftx.Context())
 
@@ -1,7 +1,6 @@
 package appointments
 
 import (
-	"clinic-app/cmd/rest/handler"
 	"clinic-app/cmd/rest/middleware"
 	"clinic-app/pkg/domain/errors"
 	"clinic-app/pkg/domain/models"
@@ -34,21 +33,31 @@ func (r *repo) GetAppointmentById(ftx factory.Service, aptmtID int) (models.Appo
 		}
 	}()
 
-	// Get the user ID from the request context
-	var userID = handler.GetUserId()
-
-	// Query to get the appointment details based on appointment ID and user ID
-	row := tx.QueryRowContext(ftx.Context(), GetAppointmentByIdQuery, aptmtID, userID)
+	// Query to get the appointment details based on appointment ID
+	row := tx.QueryRowContext(ftx.Context(), GetAppointmentByIdQuery, aptmtID)
 
 	// Scan the result into the Appointment struct
 	err = row.Scan(
 		&aptmt.AppointmentID,
 		&aptmt.PatientID,
+		&aptmt.DoctorID,
 		&aptmt.PatientName,
 		&aptmt.DoctorName,
 		&aptmt.StartTime,
 		&aptmt.EndTime,
 		&aptmt.Status,
+		&aptmt.CanceledBy,
+		&aptmt.CanceledAt,
+		&aptmt.CancelReason,
+		&aptmt.RescheduledFrom,
+		&aptmt.SeriesID,
+		&aptmt.TypeID,
+		&aptmt.TypeName,
+		&aptmt.Reason,
+		&aptmt.CheckedInAt,
+		&aptmt.StartedAt,
+		&aptmt.CompletedAt,
+		&aptmt.NoShowAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
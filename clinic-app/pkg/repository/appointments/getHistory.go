@@ -1,7 +1,6 @@
 package appointments
 
 import (
-	"clinic-app/cmd/rest/handler"
 	"clinic-app/cmd/rest/middleware"
 	"clinic-app/pkg/domain/errors"
 	"clinic-app/pkg/domain/models"
@@ -10,7 +9,7 @@ import (
 	"go.uber.org/zap"
 )
 
-func (r *repo) GetPatientHistory(ftx factory.Service, patientId int) ([]models.Appointment, error) {
+func (r *repo) GetPatientHistory(ftx factory.Service, patientId int, includeCanceled bool) ([]models.Appointment, error) {
 	// Start a new transaction
 	tx, err := ftx.TransactionManager().Begin()
 	if err != nil {
@@ -34,7 +33,7 @@ func (r *repo) GetPatientHistory(ftx factory.Service, patientId int) ([]models.A
 	}()
 
 	// Execute the query to retrieve patient history by patientId
-	rows, err := tx.QueryContext(ftx.Context(), GetPatientAppointmentHistoryQuery, patientId)
+	rows, err := tx.QueryContext(ftx.Context(), GetPatientAppointmentHistoryQuery, patientId, includeCanceled)
 	if err != nil {
 		// Log and return error if query execution fails
 		ftx.Logger().Error("Could not find patient history for doctor", zap.Error(err))
@@ -48,11 +47,24 @@ func (r *repo) GetPatientHistory(ftx factory.Service, patientId int) ([]models.A
 		if err := rows.Scan(
 			&aptmt.AppointmentID,
 			&aptmt.PatientID,
+			&aptmt.DoctorID,
 			&aptmt.DoctorName,
 			&aptmt.PatientName,
 			&aptmt.StartTime,
 			&aptmt.EndTime,
 			&aptmt.Status,
+			&aptmt.CanceledBy,
+			&aptmt.CanceledAt,
+			&aptmt.CancelReason,
+			&aptmt.RescheduledFrom,
+			&aptmt.SeriesID,
+			&aptmt.TypeID,
+			&aptmt.TypeName,
+			&aptmt.Reason,
+			&aptmt.CheckedInAt,
+			&aptmt.StartedAt,
+			&aptmt.CompletedAt,
+			&aptmt.NoShowAt,
 		); err != nil {
 			return nil, err
 		}
@@ -76,7 +88,7 @@ func (r *repo) GetPatientHistory(ftx factory.Service, patientId int) ([]models.A
 	return aptmts, nil
 }
 
-func (r *repo) GetPatientAppointmentHistory(ftx factory.Service) ([]models.Appointment, error) {
+func (r *repo) GetPatientAppointmentHistory(ftx factory.Service, includeCanceled bool) ([]models.Appointment, error) {
 	// Start a new transaction
 	tx, err := ftx.TransactionManager().Begin()
 	if err != nil {
@@ -88,8 +100,8 @@ func (r *repo) GetPatientAppointmentHistory(ftx factory.Service) ([]models.Appoi
 
 	var aptmts []models.Appointment
 
-	// Get the user ID from the request context
-	userId := handler.GetUserId()
+	// Get the user ID of the caller
+	userId := ftx.Principal().UserID
 
 	// Defer a rollback in case of failure
 	defer func() {
@@ -103,7 +115,7 @@ func (r *repo) GetPatientAppointmentHistory(ftx factory.Service) ([]models.Appoi
 	}()
 
 	// Execute the query to retrieve patient history by userId
-	rows, err := tx.QueryContext(ftx.Context(), GetPatientAppointmentHistoryQuery, userId)
+	rows, err := tx.QueryContext(ftx.Context(), GetPatientAppointmentHistoryQuery, userId, includeCanceled)
 	if err != nil {
 		// Log and return error if query execution fails
 		ftx.Logger().Error("Could not find patient history", zap.Error(err))
@@ -117,11 +129,24 @@ func (r *repo) GetPatientAppointmentHistory(ftx factory.Service) ([]models.Appoi
 		if err := rows.Scan(
 			&aptmt.AppointmentID,
 			&aptmt.PatientID,
+			&aptmt.DoctorID,
 			&aptmt.DoctorName,
 			&aptmt.PatientName,
 			&aptmt.StartTime,
 			&aptmt.EndTime,
 			&aptmt.Status,
+			&aptmt.CanceledBy,
+			&aptmt.CanceledAt,
+			&aptmt.CancelReason,
+			&aptmt.RescheduledFrom,
+			&aptmt.SeriesID,
+			&aptmt.TypeID,
+			&aptmt.TypeName,
+			&aptmt.Reason,
+			&aptmt.CheckedInAt,
+			&aptmt.StartedAt,
+			&aptmt.CompletedAt,
+			&aptmt.NoShowAt,
 		); err != nil {
 			return nil, err
 		}
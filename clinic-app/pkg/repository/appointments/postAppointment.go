@@ -1,12 +1,17 @@
 package appointments
 
 import (
-	"clinic-app/cmd/rest/handler"
 	"clinic-app/cmd/rest/middleware"
 	"clinic-app/pkg/domain/errors"
 	"clinic-app/pkg/domain/models"
 	"clinic-app/pkg/services/factory"
+	"cmp"
+	"database/sql"
+	stderrors "errors"
+	"slices"
+	"time"
 
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
@@ -20,10 +25,8 @@ func (r *repo) BookAppointment(ftx factory.Service, aptmt models.BookAppointment
 	}
 	ftx.Logger().Info("Transaction started for Booking Appointment")
 
-	// Retrieve the user ID from the request context
-	userId := handler.GetUserId()
-	var appointmentID any
-	var result string
+	// Retrieve the user ID of the caller
+	userId := ftx.Principal().UserID
 
 	// Defer a rollback in case of any errors
 	defer func() {
@@ -36,21 +39,24 @@ func (r *repo) BookAppointment(ftx factory.Service, aptmt models.BookAppointment
 		}
 	}()
 
-	// Execute the query to book an appointment
-	err = tx.QueryRowContext(ftx.Context(),
-		BookAppointmentQuery,
-		aptmt.DoctorID,
-		userId,
-		aptmt.Date,
-		aptmt.StartTime,
-		aptmt.EndTime,
-	).Scan(&appointmentID, &result)
-
+	// Only patients who verified their email address can book
+	var emailVerified bool
+	err = tx.QueryRowContext(ftx.Context(), IsEmailVerifiedQuery, userId).Scan(&emailVerified)
 	if err != nil {
-		// Log and return error if query execution fails
-		ftx.Logger().Error("Error executing query", zap.Error(err))
+		ftx.Logger().Error("Could not check email verification", zap.Error(err))
 		return errors.ErrDatabase
 	}
+	if !emailVerified {
+		err = errors.ErrEmailNotVerified // Roll back the transaction
+		ftx.Logger().Info("Email not verified", zap.Int("PatientID", userId))
+		return err
+	}
+
+	// Book under the same rules as series, holds and reschedules
+	appointmentID, err := bookInTx(ftx, tx, aptmt, userId)
+	if err != nil {
+		return err
+	}
 
 	// Commit the transaction if no errors occurred
 	if err := ftx.TransactionManager().Commit(tx); err != nil {
@@ -59,33 +65,144 @@ func (r *repo) BookAppointment(ftx factory.Service, aptmt models.BookAppointment
 		return errors.ErrDatabase
 	}
 
-	// Handle different results from the query
+	// Log success if the appointment was booked successfully
+	ftx.Logger().Info("Successfully Booked Appointment",
+		zap.Int("Appointment", appointmentID),
+	)
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
+
+// bookingError maps the status reported by BookAppointmentQuery to an error, nil when the appointment was booked
+func bookingError(ftx factory.Service, result string, conflictID sql.NullInt64) error {
 	switch result {
-	case "Valid":
-		// Log success and return nil if the appointment was booked successfully
-		ftx.Logger().Info("Successfully Booked Appointment",
-			zap.Any("Appointment", appointmentID),
-		)
-		return nil
+	case "Invalid Duration":
+		// Log and return error if the duration is outside the doctor's booking policy
+		ftx.Logger().Info("Invalid appointment duration", zap.String("result", result))
+		return errors.ErrDuration
+
+	case "Beyond Booking Horizon":
+		// Log and return error if the appointment starts too far ahead
+		ftx.Logger().Info("Beyond booking horizon", zap.String("result", result))
+		return errors.ErrBeyondHorizon
 
 	case "Appointment Exists":
-		// Log and return error if the appointment already exists
-		ftx.Logger().Info("Appointment already exists", zap.String("result", result))
-		return errors.ErrAppointmentExists
+		// Log and return error naming the appointment the booking overlaps
+		ftx.Logger().Info("Appointment overlaps another appointment", zap.Int64("Conflicting Appointment", conflictID.Int64))
+		return &errors.ConflictError{AppointmentID: int(conflictID.Int64)}
 
-	case "Schedule Not found":
+	case "Schedule Not Found":
 		// Log and return error if the schedule could not be found
 		ftx.Logger().Info("Schedule not found", zap.String("result", result))
 		return errors.ErrNoSchedule
 
+	case "Outside Working Hours":
+		// Log and return error if the appointment does not fit into the doctor's working hours
+		ftx.Logger().Info("Outside working hours", zap.String("result", result))
+		return errors.ErrOutsideHours
+
+	case "Doctor On Time Off":
+		// Log and return error if the doctor is off at that time
+		ftx.Logger().Info("Doctor on time off", zap.String("result", result))
+		return errors.ErrDoctorOnTimeOff
+
+	case "Slot Held":
+		// Log and return error if the time is held for another patient or offered to a patient on the waitlist
+		ftx.Logger().Info("Slot held for another patient", zap.String("result", result))
+		return errors.ErrSlotHeld
+
 	case "Doctor Overbooked":
 		// Log and return error if the doctor is overbooked
 		ftx.Logger().Info("Doctor is overbooked", zap.String("result", result))
 		return errors.ErrDoctorOverbooked
 	}
 
-	// Handle any unexpected results
-	middleware.GetTraceParentFromContext(ftx.Context())
+	return nil
+}
+
+// isOverlapViolation reports whether err is a violation of the appointment overlap exclusion constraints
+func isOverlapViolation(err error) bool {
+	var pqErr *pq.Error
+	return stderrors.As(err, &pqErr) && pqErr.Code == "23P01"
+}
 
+// conflictError looks up the appointment a booking collided with after the overlap constraints rejected it.
+// The failed transaction cannot be used anymore, so the lookup runs on its own.
+func conflictError(ftx factory.Service, doctorId, patientId int, start, end time.Time) error {
+	var conflictID int
+	err := ftx.PSQL().QueryRowContext(ftx.Context(), FindConflictingAppointmentQuery, doctorId, patientId, start, end).Scan(&conflictID)
+	if err != nil && err != sql.ErrNoRows {
+		ftx.Logger().Error("Could not find conflicting appointment", zap.Error(err))
+	}
+
+	ftx.Logger().Info("Appointment overlaps another appointment", zap.Int("Conflicting Appointment", conflictID))
+	return &errors.ConflictError{AppointmentID: conflictID}
+}
+
+// bookInTx books an appointment for a patient in a running transaction and returns its ID.
+// It waits for other bookings of the doctor's day and applies the rules of BookAppointmentQuery.
+func bookInTx(ftx factory.Service, tx *sql.Tx, aptmt models.BookAppointment, patientId int) (int, error) {
+	if err := lockDoctorDay(ftx, tx, aptmt.DoctorID, aptmt.Date); err != nil {
+		return 0, err
+	}
+
+	var appointmentID, conflictID sql.NullInt64
+	var result string
+	err := tx.QueryRowContext(ftx.Context(),
+		BookAppointmentQuery,
+		aptmt.DoctorID,
+		patientId,
+		aptmt.Date,
+		aptmt.StartTime,
+		aptmt.EndTime,
+		aptmt.TypeID,
+		aptmt.Reason,
+	).Scan(&appointmentID, &result, &conflictID)
+	if err != nil {
+		// A concurrent booking got past the check and was stopped by the overlap constraints
+		if isOverlapViolation(err) {
+			return 0, conflictError(ftx, aptmt.DoctorID, patientId, aptmt.StartTime, aptmt.EndTime)
+		}
+		ftx.Logger().Error("Error executing query", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+	if err := bookingError(ftx, result, conflictID); err != nil {
+		return 0, err
+	}
+
+	return int(appointmentID.Int64), nil
+}
+
+// lockDoctorDay takes the advisory lock of a doctor's day, it is released when the transaction ends
+func lockDoctorDay(ftx factory.Service, tx *sql.Tx, doctorId int, date time.Time) error {
+	_, err := tx.ExecContext(ftx.Context(), LockDoctorDayQuery, doctorId, date)
+	if err != nil {
+		ftx.Logger().Error("Could not lock doctor day", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	return nil
+}
+
+// doctorDay is a day of a doctor's schedule
+type doctorDay struct {
+	DoctorID int
+	Date     time.Time
+}
+
+// lockDoctorDays takes the advisory locks of several days ordered by date and doctor, so transactions locking
+// the same days always wait for each other in the same order
+func lockDoctorDays(ftx factory.Service, tx *sql.Tx, days ...doctorDay) error {
+	slices.SortFunc(days, func(a, b doctorDay) int {
+		if c := a.Date.Compare(b.Date); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.DoctorID, b.DoctorID)
+	})
+	for _, day := range days {
+		if err := lockDoctorDay(ftx, tx, day.DoctorID, day.Date); err != nil {
+			return err
+		}
+	}
 	return nil
 }
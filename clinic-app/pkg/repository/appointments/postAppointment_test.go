@@ -0,0 +1,160 @@
+package appointments
+
+import (
+	"clinic-app/internal/testdb"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/clinictime"
+	"database/sql"
+	stderrors "errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// bookConcurrently books every appointment for a patient of its own at once and returns the outcome of each
+func bookConcurrently(t *testing.T, db *sql.DB, bookings []models.BookAppointment) []error {
+	t.Helper()
+	repo := New()
+	patients := make([]int, len(bookings))
+	for i := range bookings {
+		patients[i] = testdb.User(t, db, "patient")
+	}
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make([]error, len(bookings))
+	for i, booking := range bookings {
+		wg.Add(1)
+		go func(i int, booking models.BookAppointment) {
+			defer wg.Done()
+			ftx := testdb.Service(t, db, models.Principal{UserID: patients[i], Role: "patient"})
+			<-start // Fire all bookings together
+			errs[i] = repo.BookAppointment(ftx, booking)
+		}(i, booking)
+	}
+	close(start)
+	wg.Wait()
+	return errs
+}
+
+// booking returns a booking with the doctor of the given minutes from a wall-clock time of a clinic day
+func booking(doctorId int, day time.Time, hour, minute, minutes int) models.BookAppointment {
+	start := testdb.At(day, hour, minute)
+	return models.BookAppointment{
+		DoctorID:  doctorId,
+		Date:      clinictime.Day(day),
+		StartTime: start,
+		EndTime:   start.Add(time.Duration(minutes) * time.Minute),
+	}
+}
+
+// outcomes counts the successful bookings and fails the test on errors other than the expected rejection
+func outcomes(t *testing.T, errs []error, rejection error) int {
+	t.Helper()
+	booked := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			booked++
+		case stderrors.Is(err, rejection):
+		default:
+			t.Errorf("booking failed with %v, want nil or %v", err, rejection)
+		}
+	}
+	return booked
+}
+
+// bookedAppointments counts the appointments of a doctor that hold their time and the minutes they take
+func bookedAppointments(t *testing.T, db *sql.DB, doctorId int) (count int, minutes int) {
+	t.Helper()
+	err := db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(EXTRACT(EPOCH FROM end_time - start_time) / 60), 0)::INT
+		FROM Appointment
+		WHERE doctor_id = $1
+		AND status NOT IN ('canceled', 'no_show');
+	`, doctorId).Scan(&count, &minutes)
+	if err != nil {
+		t.Fatalf("Could not count appointments: %v", err)
+	}
+	return count, minutes
+}
+
+func TestParallelBookingsKeepDailyAppointmentCap(t *testing.T) {
+	db := testdb.Open(t)
+	doctorId := testdb.User(t, db, "doctor")
+	day := clinictime.Today().AddDate(0, 0, 3)
+	testdb.Policy(t, db, doctorId, 3, 480)
+	testdb.WorkingDay(t, db, doctorId, day, "08:00", "17:00")
+
+	// Ten patients go for ten different half hours, only three fit the cap
+	bookings := make([]models.BookAppointment, 10)
+	for i := range bookings {
+		bookings[i] = booking(doctorId, day, 8+i/2, 30*(i%2), 30)
+	}
+	errs := bookConcurrently(t, db, bookings)
+
+	if booked := outcomes(t, errs, errors.ErrDoctorOverbooked); booked != 3 {
+		t.Errorf("%d bookings succeeded, want 3", booked)
+	}
+	if count, _ := bookedAppointments(t, db, doctorId); count != 3 {
+		t.Errorf("doctor has %d appointments, want 3", count)
+	}
+}
+
+func TestParallelBookingsKeepDailyMinutesCap(t *testing.T) {
+	db := testdb.Open(t)
+	doctorId := testdb.User(t, db, "doctor")
+	day := clinictime.Today().AddDate(0, 0, 3)
+	testdb.Policy(t, db, doctorId, 12, 90)
+	testdb.WorkingDay(t, db, doctorId, day, "08:00", "17:00")
+
+	// Eight half hours are asked for, ninety minutes fit three of them
+	bookings := make([]models.BookAppointment, 8)
+	for i := range bookings {
+		bookings[i] = booking(doctorId, day, 8+i/2, 30*(i%2), 30)
+	}
+	errs := bookConcurrently(t, db, bookings)
+
+	if booked := outcomes(t, errs, errors.ErrDoctorOverbooked); booked != 3 {
+		t.Errorf("%d bookings succeeded, want 3", booked)
+	}
+	if _, minutes := bookedAppointments(t, db, doctorId); minutes > 90 {
+		t.Errorf("doctor is booked for %d minutes, want at most 90", minutes)
+	}
+}
+
+func TestParallelBookingsDoNotOverlap(t *testing.T) {
+	db := testdb.Open(t)
+	doctorId := testdb.User(t, db, "doctor")
+	day := clinictime.Today().AddDate(0, 0, 3)
+	testdb.WorkingDay(t, db, doctorId, day, "08:00", "17:00")
+
+	// Ten patients go for overlapping times around 09:00, only one of them can get the doctor
+	bookings := make([]models.BookAppointment, 10)
+	for i := range bookings {
+		bookings[i] = booking(doctorId, day, 9, i*2, 30)
+	}
+	errs := bookConcurrently(t, db, bookings)
+
+	if booked := outcomes(t, errs, errors.ErrAppointmentExists); booked != 1 {
+		t.Errorf("%d bookings succeeded, want 1", booked)
+	}
+
+	var overlaps int
+	err := db.QueryRow(`
+		SELECT COUNT(*)
+		FROM Appointment a
+		INNER JOIN Appointment b ON b.doctor_id = a.doctor_id AND b.appointment_id > a.appointment_id
+		WHERE a.doctor_id = $1
+		AND a.status NOT IN ('canceled', 'no_show')
+		AND b.status NOT IN ('canceled', 'no_show')
+		AND tstzrange(a.start_time, a.end_time) && tstzrange(b.start_time, b.end_time);
+	`, doctorId).Scan(&overlaps)
+	if err != nil {
+		t.Fatalf("Could not look for overlaps: %v", err)
+	}
+	if overlaps != 0 {
+		t.Errorf("doctor has %d overlapping appointments", overlaps)
+	}
+}
@@ -0,0 +1,280 @@
+package appointments
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanHold reads a hold in the column order of the hold queries
+func scanHold(row rowScanner) (models.AppointmentHold, error) {
+	var hold models.AppointmentHold
+	var appointmentId sql.NullInt64
+	err := row.Scan(
+		&hold.ID,
+		&hold.PatientID,
+		&hold.DoctorID,
+		&hold.Date,
+		&hold.StartTime,
+		&hold.EndTime,
+		&hold.Status,
+		&hold.ExpiresAt,
+		&hold.TypeID,
+		&hold.Reason,
+		&appointmentId,
+		&hold.CreatedAt,
+	)
+	if appointmentId.Valid {
+		id := int(appointmentId.Int64)
+		hold.AppointmentID = &id
+	}
+	return hold, err
+}
+
+// CreateHold holds a time for the calling patient for ttl under the same rules as a booking
+func (r *repo) CreateHold(ftx factory.Service, aptmt models.BookAppointment, ttl time.Duration) (models.AppointmentHold, error) {
+	patientId := ftx.Principal().UserID
+	var holdId, conflictID sql.NullInt64
+	var result string
+
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return models.AppointmentHold{}, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for holding appointment")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// A hold can only be confirmed by patients who verified their email address, so tell the others right away
+	var emailVerified bool
+	err = tx.QueryRowContext(ftx.Context(), IsEmailVerifiedQuery, patientId).Scan(&emailVerified)
+	if err != nil {
+		ftx.Logger().Error("Could not check email verification", zap.Error(err))
+		return models.AppointmentHold{}, errors.ErrDatabase
+	}
+	if !emailVerified {
+		err = errors.ErrEmailNotVerified // Roll back the transaction
+		return models.AppointmentHold{}, err
+	}
+
+	// Wait for the bookings and holds of the doctor's day
+	if err = lockDoctorDay(ftx, tx, aptmt.DoctorID, aptmt.Date); err != nil {
+		return models.AppointmentHold{}, err
+	}
+
+	err = tx.QueryRowContext(ftx.Context(),
+		CreateHoldQuery,
+		aptmt.DoctorID,
+		patientId,
+		aptmt.Date,
+		aptmt.StartTime,
+		aptmt.EndTime,
+		ttl.Seconds(),
+		aptmt.TypeID,
+		aptmt.Reason,
+	).Scan(&holdId, &result, &conflictID)
+	if err != nil {
+		ftx.Logger().Error("Error executing query", zap.Error(err))
+		return models.AppointmentHold{}, errors.ErrDatabase
+	}
+	if err = bookingError(ftx, result, conflictID); err != nil {
+		return models.AppointmentHold{}, err
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return models.AppointmentHold{}, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully held appointment", zap.Int64("Hold ID", holdId.Int64))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	hold, err := scanHold(ftx.PSQL().QueryRowContext(ftx.Context(), GetHoldQuery, holdId.Int64))
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve hold", zap.Error(err))
+		return models.AppointmentHold{}, errors.ErrDatabase
+	}
+	return hold, nil
+}
+
+// ConfirmHold books the held time for the patient of the hold and returns the ID of the appointment.
+// It returns ErrHoldNotActive when the hold expired or was already confirmed or released.
+func (r *repo) ConfirmHold(ftx factory.Service, holdId int) (int, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for confirming hold")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Lock the hold so it cannot be confirmed twice
+	hold, err := scanHold(tx.QueryRowContext(ftx.Context(), LockHoldQuery, holdId))
+	if err == sql.ErrNoRows {
+		return 0, errors.ErrNotFound
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not lock hold", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	// Stop counting the hold, then book its time under the booking rules
+	var confirmedId int
+	err = tx.QueryRowContext(ftx.Context(), ConfirmHoldQuery, holdId).Scan(&confirmedId)
+	if err == sql.ErrNoRows {
+		err = errors.ErrHoldNotActive // Roll back the transaction
+		return 0, err
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not confirm hold", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+	aptmt := models.BookAppointment{
+		DoctorID:  hold.DoctorID,
+		Date:      hold.Date,
+		StartTime: hold.StartTime,
+		EndTime:   hold.EndTime,
+		TypeID:    hold.TypeID,
+	}
+	if hold.Reason != nil {
+		aptmt.Reason = *hold.Reason
+	}
+	appointmentId, err := bookInTx(ftx, tx, aptmt, hold.PatientID)
+	if err != nil {
+		return 0, err
+	}
+	_, err = tx.ExecContext(ftx.Context(), LinkHoldAppointmentQuery, holdId, appointmentId)
+	if err != nil {
+		ftx.Logger().Error("Could not link hold to appointment", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully confirmed hold",
+		zap.Int("Hold ID", holdId),
+		zap.Int("Appointment ID", appointmentId),
+	)
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return appointmentId, nil
+}
+
+// ReleaseHold gives up a held time before the hold expires.
+// It returns ErrHoldNotActive when the hold expired or was already confirmed or released.
+func (r *repo) ReleaseHold(ftx factory.Service, holdId int) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for releasing hold")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	var releasedId int
+	err = tx.QueryRowContext(ftx.Context(), ReleaseHoldQuery, holdId).Scan(&releasedId)
+	if err == sql.ErrNoRows {
+		err = errors.ErrHoldNotActive // Roll back the transaction
+		return err
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not release hold", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Released hold", zap.Int("Hold ID", holdId))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
+
+// ExpireHolds marks the holds that ran out as expired and returns how many there were
+func (r *repo) ExpireHolds(ftx factory.Service) (int64, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for expiring holds")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	result, err := tx.ExecContext(ftx.Context(), ExpireHoldsQuery)
+	if err != nil {
+		ftx.Logger().Error("Could not expire holds", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+	expired, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	middleware.GetTraceParentFromContext(ftx.Context())
+	return expired, nil
+}
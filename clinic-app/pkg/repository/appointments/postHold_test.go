@@ -0,0 +1,90 @@
+package appointments
+
+import (
+	"clinic-app/internal/testdb"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/clinictime"
+	"testing"
+	"time"
+)
+
+func TestConfirmHoldBooksWithTypeAndReason(t *testing.T) {
+	db := testdb.Open(t)
+	doctorId := testdb.User(t, db, "doctor")
+	patientId := testdb.User(t, db, "patient")
+	day := clinictime.Today().AddDate(0, 0, 2)
+	testdb.WorkingDay(t, db, doctorId, day, "08:00", "17:00")
+
+	var typeId int
+	if err := db.QueryRow(`SELECT type_id FROM AppointmentTypes WHERE name = 'Follow-up';`).Scan(&typeId); err != nil {
+		t.Fatalf("Could not find appointment type: %v", err)
+	}
+
+	repo := New()
+	ftx := testdb.Service(t, db, models.Principal{UserID: patientId, Role: "patient"})
+	hold, err := repo.CreateHold(ftx, models.BookAppointment{
+		DoctorID:  doctorId,
+		Date:      day,
+		StartTime: testdb.At(day, 9, 0),
+		EndTime:   testdb.At(day, 9, 20),
+		TypeID:    &typeId,
+		Reason:    "Knee still hurts",
+	}, time.Minute)
+	if err != nil {
+		t.Fatalf("CreateHold: %v", err)
+	}
+
+	appointmentId, err := repo.ConfirmHold(ftx, hold.ID)
+	if err != nil {
+		t.Fatalf("ConfirmHold: %v", err)
+	}
+
+	aptmt, err := repo.GetAppointmentById(ftx, appointmentId)
+	if err != nil {
+		t.Fatalf("GetAppointmentById: %v", err)
+	}
+	if aptmt.TypeID == nil || *aptmt.TypeID != typeId {
+		t.Errorf("type = %v, want %d", aptmt.TypeID, typeId)
+	}
+	if aptmt.Reason == nil || *aptmt.Reason != "Knee still hurts" {
+		t.Errorf("reason = %v, want the reason of the hold", aptmt.Reason)
+	}
+	if !aptmt.StartTime.Equal(testdb.At(day, 9, 0)) {
+		t.Errorf("start = %v, want %v", aptmt.StartTime, testdb.At(day, 9, 0))
+	}
+}
+
+func TestBookingOverOwnHoldCountsOnce(t *testing.T) {
+	db := testdb.Open(t)
+	doctorId := testdb.User(t, db, "doctor")
+	patientId := testdb.User(t, db, "patient")
+	day := clinictime.Today().AddDate(0, 0, 2)
+	testdb.Policy(t, db, doctorId, 1, 480)
+	testdb.WorkingDay(t, db, doctorId, day, "08:00", "17:00")
+
+	repo := New()
+	ftx := testdb.Service(t, db, models.Principal{UserID: patientId, Role: "patient"})
+	booking := models.BookAppointment{
+		DoctorID:  doctorId,
+		Date:      day,
+		StartTime: testdb.At(day, 9, 0),
+		EndTime:   testdb.At(day, 9, 30),
+	}
+	hold, err := repo.CreateHold(ftx, booking, time.Minute)
+	if err != nil {
+		t.Fatalf("CreateHold: %v", err)
+	}
+
+	// The hold takes the only appointment of the day, booking it directly must not count it twice
+	if err := repo.BookAppointment(ftx, booking); err != nil {
+		t.Fatalf("BookAppointment over own hold: %v", err)
+	}
+
+	var status string
+	if err := db.QueryRow(`SELECT status FROM AppointmentHolds WHERE hold_id = $1;`, hold.ID).Scan(&status); err != nil {
+		t.Fatalf("Could not read hold: %v", err)
+	}
+	if status != "confirmed" {
+		t.Errorf("hold is %s, want confirmed into the booking", status)
+	}
+}
@@ -0,0 +1,246 @@
+package appointments
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+	stderrors "errors"
+
+	"go.uber.org/zap"
+)
+
+// BookSeries records a recurring appointment and books its occurrences for the calling patient in one transaction.
+// All-or-nothing series are only kept when every occurrence was booked, best-effort ones when at least one was.
+// Otherwise nothing is kept and ErrSeriesNotBooked is returned together with the outcome of every occurrence.
+func (r *repo) BookSeries(ftx factory.Service, series models.BookSeries, occurrences []models.BookAppointment) (models.AppointmentSeries, []models.SeriesOccurrence, error) {
+	booked := models.AppointmentSeries{
+		PatientID: ftx.Principal().UserID,
+		DoctorID:  series.DoctorID,
+		RRule:     series.RRule,
+		StartTime: series.StartTime,
+		EndTime:   series.EndTime,
+	}
+
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return models.AppointmentSeries{}, nil, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for booking appointment series")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Only patients who verified their email address can book
+	var emailVerified bool
+	err = tx.QueryRowContext(ftx.Context(), IsEmailVerifiedQuery, booked.PatientID).Scan(&emailVerified)
+	if err != nil {
+		ftx.Logger().Error("Could not check email verification", zap.Error(err))
+		return models.AppointmentSeries{}, nil, errors.ErrDatabase
+	}
+	if !emailVerified {
+		err = errors.ErrEmailNotVerified // Roll back the transaction
+		return models.AppointmentSeries{}, nil, err
+	}
+
+	err = tx.QueryRowContext(ftx.Context(), InsertSeriesQuery,
+		booked.PatientID,
+		booked.DoctorID,
+		booked.RRule,
+		booked.StartTime,
+		booked.EndTime,
+	).Scan(&booked.ID, &booked.CreatedAt)
+	if err != nil {
+		ftx.Logger().Error("Could not insert appointment series", zap.Error(err))
+		return models.AppointmentSeries{}, nil, errors.ErrDatabase
+	}
+
+	// Book every occurrence and add it to the series
+	outcomes := make([]models.SeriesOccurrence, len(occurrences))
+	bookedCount := 0
+	for i, occurrence := range occurrences {
+		outcomes[i] = models.SeriesOccurrence{StartTime: occurrence.StartTime, EndTime: occurrence.EndTime}
+
+		appointmentId, bookErr := inSavepoint(ftx, tx, func() (int, error) {
+			id, err := bookInTx(ftx, tx, occurrence, booked.PatientID)
+			if err != nil {
+				return 0, err
+			}
+			if _, err := tx.ExecContext(ftx.Context(), SetAppointmentSeriesQuery, id, booked.ID); err != nil {
+				ftx.Logger().Error("Could not add appointment to series", zap.Error(err))
+				return 0, errors.ErrDatabase
+			}
+			return id, nil
+		})
+		if bookErr == errors.ErrDatabase {
+			err = bookErr // Roll back the transaction
+			return models.AppointmentSeries{}, nil, err
+		}
+		if bookErr != nil {
+			outcomes[i].Error, outcomes[i].ConflictingAppointmentID = failureReason(bookErr)
+			continue
+		}
+		outcomes[i].AppointmentID = appointmentId
+		bookedCount++
+	}
+
+	// Keep the series only when enough of it was booked
+	if bookedCount == 0 || (series.Mode == models.SeriesAllOrNothing && bookedCount < len(occurrences)) {
+		err = errors.ErrSeriesNotBooked // Roll back the transaction
+		ftx.Logger().Info("Appointment series not booked", zap.Int("Booked", bookedCount), zap.Int("Occurrences", len(occurrences)))
+		return models.AppointmentSeries{}, outcomes, err
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return models.AppointmentSeries{}, nil, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully booked appointment series",
+		zap.Int("Series ID", booked.ID),
+		zap.Int("Booked", bookedCount),
+		zap.Int("Occurrences", len(occurrences)),
+	)
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return booked, outcomes, nil
+}
+
+// RescheduleSeries moves occurrences of a series in one transaction. Every occurrence has to move,
+// otherwise nothing changes and ErrSeriesNotMoved is returned together with the outcome of every occurrence.
+func (r *repo) RescheduleSeries(ftx factory.Service, moves []models.RescheduleAppointment) ([]models.AffectedAppointment, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for rescheduling appointment series")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	outcomes := make([]models.AffectedAppointment, len(moves))
+	failed := false
+	for i, move := range moves {
+		outcomes[i] = models.AffectedAppointment{AppointmentID: move.AppointmentID}
+
+		appointmentId, moveErr := inSavepoint(ftx, tx, func() (int, error) {
+			return rescheduleInTx(ftx, tx, move)
+		})
+		if moveErr == errors.ErrDatabase {
+			err = moveErr // Roll back the transaction
+			return nil, err
+		}
+		if moveErr != nil {
+			outcomes[i].Error, _ = failureReason(moveErr)
+			failed = true
+			continue
+		}
+		outcomes[i].NewAppointmentID = appointmentId
+	}
+
+	if failed {
+		err = errors.ErrSeriesNotMoved // Roll back the transaction
+		return outcomes, err
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully rescheduled appointment series", zap.Int("Occurrences", len(moves)))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return outcomes, nil
+}
+
+// GetSeriesAppointments retrieves the scheduled occurrences of the series of an appointment in the scope,
+// from the appointment on for ScopeFollowing and every upcoming one for ScopeAll
+func (r *repo) GetSeriesAppointments(ftx factory.Service, appointmentId int, scope string) ([]models.BookAppointment, error) {
+	rows, err := ftx.PSQL().QueryContext(ftx.Context(), GetSeriesAppointmentsQuery, appointmentId, scope)
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve series appointments", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	defer rows.Close()
+
+	appointments := []models.BookAppointment{}
+	for rows.Next() {
+		var aptmt models.BookAppointment
+		if err := rows.Scan(
+			&aptmt.AppointmentID,
+			&aptmt.DoctorID,
+			&aptmt.PatientID,
+			&aptmt.Date,
+			&aptmt.StartTime,
+			&aptmt.EndTime,
+			&aptmt.Status,
+		); err != nil {
+			ftx.Logger().Error("Error scanning series appointment row", zap.Error(err))
+			return nil, errors.ErrDatabase
+		}
+		appointments = append(appointments, aptmt)
+	}
+	if err := rows.Err(); err != nil {
+		ftx.Logger().Error("Could not retrieve series appointments", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	return appointments, nil
+}
+
+// inSavepoint runs step behind a savepoint and rolls back to it when the step fails,
+// so the transaction stays usable for the next occurrence
+func inSavepoint(ftx factory.Service, tx *sql.Tx, step func() (int, error)) (int, error) {
+	if _, err := tx.ExecContext(ftx.Context(), SavepointQuery); err != nil {
+		ftx.Logger().Error("Could not set savepoint", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	id, err := step()
+	if err != nil {
+		if _, rollbackErr := tx.ExecContext(ftx.Context(), RollbackSavepointQuery); rollbackErr != nil {
+			ftx.Logger().Error("Could not roll back to savepoint", zap.Error(rollbackErr))
+			return 0, errors.ErrDatabase
+		}
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ftx.Context(), ReleaseSavepointQuery); err != nil {
+		ftx.Logger().Error("Could not release savepoint", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+	return id, nil
+}
+
+// failureReason tells why an occurrence could not be booked or moved, and which appointment it overlaps
+func failureReason(err error) (string, int) {
+	if conflict := (*errors.ConflictError)(nil); stderrors.As(err, &conflict) {
+		return errors.ErrAppointmentExists.Message, conflict.AppointmentID
+	}
+	if appErr, ok := err.(*errors.ClinicAppError); ok {
+		return appErr.Message, 0
+	}
+	return err.Error(), 0
+}
@@ -0,0 +1,138 @@
+package appointments
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/clinictime"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RescheduleAppointment moves an appointment to a new time and optionally to another doctor.
+// The original appointment is cancelled and the new one is booked under the same rules in one transaction,
+// so the old time is only given up when the new one could be booked.
+func (r *repo) RescheduleAppointment(ftx factory.Service, reschedule models.RescheduleAppointment) (int, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for rescheduling appointment")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				// Log rollback failure
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	appointmentId, err := rescheduleInTx(ftx, tx, reschedule)
+	if err != nil {
+		// Roll back the cancellation as well, the original appointment stays as it was
+		return 0, err
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully rescheduled appointment",
+		zap.Int("Original Appointment", reschedule.AppointmentID),
+		zap.Int("Appointment", appointmentId),
+	)
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return appointmentId, nil
+}
+
+// rescheduleInTx cancels an appointment and books its new time in a running transaction.
+// It returns the ID of the new booking, the caller rolls back when it fails.
+func rescheduleInTx(ftx factory.Service, tx *sql.Tx, reschedule models.RescheduleAppointment) (int, error) {
+	// Lock the original appointment so it cannot be cancelled or moved concurrently
+	var doctorId, patientId int
+	var startTime time.Time
+	var status, reason string
+	var typeId sql.NullInt64
+	err := tx.QueryRowContext(ftx.Context(), LockAppointmentQuery, reschedule.AppointmentID).Scan(&doctorId, &patientId, &startTime, &status, &typeId, &reason)
+	if err == sql.ErrNoRows {
+		return 0, errors.ErrNotFound
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not lock appointment", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+	if status != models.StatusScheduled {
+		return 0, errors.ErrNotReschedulable
+	}
+
+	// Keep the doctor unless another one was requested
+	if reschedule.DoctorID == 0 {
+		reschedule.DoctorID = doctorId
+	}
+
+	// Hold the old and the new day from before the cancel releases the old one until the new time is booked.
+	// The days are locked in date order, so two reschedules between the same days cannot deadlock.
+	err = lockDoctorDays(ftx, tx,
+		doctorDay{DoctorID: doctorId, Date: clinictime.Day(startTime)},
+		doctorDay{DoctorID: reschedule.DoctorID, Date: reschedule.Date},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	// Cancel the original appointment, releasing its schedule, and free its slot
+	var canceledId int
+	err = tx.QueryRowContext(ftx.Context(), CancelAppointmentQuery,
+		reschedule.AppointmentID,
+		ftx.Principal().UserID,
+		"Rescheduled",
+		models.StatusScheduled,
+	).Scan(&canceledId)
+	if err != nil {
+		ftx.Logger().Error("Could not cancel original appointment", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+	_, err = tx.ExecContext(ftx.Context(), DeleteSlotQuery, reschedule.AppointmentID)
+	if err != nil {
+		ftx.Logger().Error("Could not delete slot of original appointment", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	// Book the new time for the same patient with the type and reason of the original under the booking rules
+	booking := models.BookAppointment{
+		DoctorID:  reschedule.DoctorID,
+		Date:      reschedule.Date,
+		StartTime: reschedule.StartTime,
+		EndTime:   reschedule.EndTime,
+		Reason:    reason,
+	}
+	if typeId.Valid {
+		id := int(typeId.Int64)
+		booking.TypeID = &id
+	}
+	appointmentId, err := bookInTx(ftx, tx, booking, patientId)
+	if err != nil {
+		return 0, err
+	}
+
+	// Link the new booking to the original one
+	_, err = tx.ExecContext(ftx.Context(), LinkRescheduledAppointmentQuery, appointmentId, reschedule.AppointmentID)
+	if err != nil {
+		ftx.Logger().Error("Could not link rescheduled appointment", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	return appointmentId, nil
+}
@@ -0,0 +1,66 @@
+package appointments
+
+import (
+	"clinic-app/internal/testdb"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/clinictime"
+	"testing"
+)
+
+func TestRescheduleKeepsTypeAndReason(t *testing.T) {
+	db := testdb.Open(t)
+	doctorId := testdb.User(t, db, "doctor")
+	patientId := testdb.User(t, db, "patient")
+	day := clinictime.Today().AddDate(0, 0, 2)
+	testdb.WorkingDay(t, db, doctorId, day, "08:00", "17:00")
+
+	var typeId int
+	if err := db.QueryRow(`SELECT type_id FROM AppointmentTypes WHERE name = 'Follow-up';`).Scan(&typeId); err != nil {
+		t.Fatalf("Could not find appointment type: %v", err)
+	}
+
+	repo := New()
+	ftx := testdb.Service(t, db, models.Principal{UserID: patientId, Role: "patient"})
+	err := repo.BookAppointment(ftx, models.BookAppointment{
+		DoctorID:  doctorId,
+		Date:      day,
+		StartTime: testdb.At(day, 9, 0),
+		EndTime:   testdb.At(day, 9, 20),
+		TypeID:    &typeId,
+		Reason:    "Knee still hurts",
+	})
+	if err != nil {
+		t.Fatalf("BookAppointment: %v", err)
+	}
+	var originalId int
+	if err := db.QueryRow(`SELECT appointment_id FROM Appointment WHERE patient_id = $1;`, patientId).Scan(&originalId); err != nil {
+		t.Fatalf("Could not find booked appointment: %v", err)
+	}
+
+	appointmentId, err := repo.RescheduleAppointment(ftx, models.RescheduleAppointment{
+		AppointmentID: originalId,
+		Date:          day,
+		StartTime:     testdb.At(day, 11, 0),
+		EndTime:       testdb.At(day, 11, 20),
+	})
+	if err != nil {
+		t.Fatalf("RescheduleAppointment: %v", err)
+	}
+
+	aptmt, err := repo.GetAppointmentById(ftx, appointmentId)
+	if err != nil {
+		t.Fatalf("GetAppointmentById: %v", err)
+	}
+	if aptmt.TypeID == nil || *aptmt.TypeID != typeId {
+		t.Errorf("type = %v, want %d", aptmt.TypeID, typeId)
+	}
+	if aptmt.Reason == nil || *aptmt.Reason != "Knee still hurts" {
+		t.Errorf("reason = %v, want the reason of the original", aptmt.Reason)
+	}
+	if aptmt.DoctorID != doctorId {
+		t.Errorf("doctor = %d, want %d", aptmt.DoctorID, doctorId)
+	}
+	if aptmt.RescheduledFrom == nil || *aptmt.RescheduledFrom != originalId {
+		t.Errorf("rescheduled from = %v, want %d", aptmt.RescheduledFrom, originalId)
+	}
+}
@@ -0,0 +1,143 @@
+package appointments
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// GetAppointmentStatus retrieves the current status of an appointment together with its start and end time
+func (r *repo) GetAppointmentStatus(ftx factory.Service, appointmentId int) (models.Appointment, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return models.Appointment{}, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for retrieving appointment status")
+
+	// Defer a rollback in case anything fails
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	aptmt := models.Appointment{AppointmentID: appointmentId}
+	err = tx.QueryRowContext(ftx.Context(), GetAppointmentStatusQuery, appointmentId).Scan(
+		&aptmt.Status,
+		&aptmt.StartTime,
+		&aptmt.EndTime,
+	)
+	if err == sql.ErrNoRows {
+		return models.Appointment{}, errors.ErrNotFound
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve appointment status", zap.Error(err))
+		return models.Appointment{}, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return models.Appointment{}, errors.ErrDatabase
+	}
+
+	middleware.GetTraceParentFromContext(ftx.Context())
+	return aptmt, nil
+}
+
+// UpdateAppointmentStatus moves an appointment from one status to another, the caller checked the transition.
+// ErrInvalidTransition is returned when the appointment left the from status in the meantime.
+func (r *repo) UpdateAppointmentStatus(ftx factory.Service, appointmentId int, from, to string) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for updating appointment status")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Update the status only if it is still the one the transition was checked against
+	var updatedId int
+	err = tx.QueryRowContext(ftx.Context(), UpdateAppointmentStatusQuery, appointmentId, from, to).Scan(&updatedId)
+	if err == sql.ErrNoRows {
+		return errors.ErrInvalidTransition
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not update appointment status", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully updated appointment status",
+		zap.Int("Appointment ID", appointmentId),
+		zap.String("From", from),
+		zap.String("To", to),
+	)
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
+
+// MarkNoShows marks scheduled appointments that ended longer than grace ago as no-show and returns how many were marked
+func (r *repo) MarkNoShows(ftx factory.Service, grace time.Duration) (int64, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	result, err := tx.ExecContext(ftx.Context(), MarkNoShowsQuery, grace.Seconds())
+	if err != nil {
+		ftx.Logger().Error("Could not mark no-shows", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+	marked, err := result.RowsAffected()
+	if err != nil {
+		ftx.Logger().Error("Could not count no-shows", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	return marked, nil
+}
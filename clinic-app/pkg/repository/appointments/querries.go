@@ -1,56 +1,166 @@
 package appointments
 
 const (
-	// Book an appointment
-	BookAppointmentQuery = `
-	WITH check_appointment AS (
-		SELECT 1
-    	FROM Appointment
-    	WHERE doctor_id = $1
-    	AND appointment_date = $3
-    	AND start_time = $4
+	// Check whether a user verified their email address
+	IsEmailVerifiedQuery = `
+		SELECT email_verified_at IS NOT NULL
+		FROM Users
+		WHERE user_id = $1;
+	`
+
+	// Serialise bookings of a doctor for one day until the transaction ends.
+	// The caps on the doctor's day are checked against the schedule counters, which concurrent bookings
+	// would otherwise both read before either of them incremented them.
+	LockDoctorDayQuery = `
+		SELECT pg_advisory_xact_lock($1, $2::DATE - DATE '2000-01-01');
+	`
+
+	// The rules of a booking under the doctor's booking policy, shared by bookings and holds. $1 is the doctor,
+	// $2 the patient, $3 the clinic day and $4 and $5 the start and end. The duration must lie within the policy's
+	// bounds and the start within the booking horizon. The time must not overlap another appointment of the patient,
+	// nor come closer to another appointment or hold of the doctor than the buffer. It must fit into a working block
+	// of the doctor's schedule for the day, must not fall into time off the doctor requested or was granted, nor
+	// into a slot offered to a patient on the waitlist, and must not exceed the capacity of the day, which the policy
+	// sets when the schedule is materialised. The patient's own holds and offers neither block the time nor count
+	// against the capacity, they turn into the booking. valid_status names the first rule broken, 'Valid' when none is.
+	bookingChecks = `
+	policy AS (
+		SELECT min_duration, max_duration, buffer, booking_horizon_days
+		FROM effective_booking_policy($1)
+	),
+	valid_request AS (
+		SELECT
+			CASE
+				WHEN ($5::TIMESTAMPTZ - $4::TIMESTAMPTZ) NOT BETWEEN min_duration AND max_duration THEN 'Invalid Duration'
+				WHEN $4::TIMESTAMPTZ >= CURRENT_DATE + booking_horizon_days + 1 THEN 'Beyond Booking Horizon'
+				ELSE 'Valid'
+			END AS status
+		FROM policy
+	),
+	check_appointment AS (
+		SELECT appointment_id
+		FROM Appointment, policy
+		WHERE (doctor_id = $1 OR patient_id = $2)
+		AND status NOT IN ('canceled', 'no_show')
+		AND tstzrange(start_time, end_time) && CASE
+			WHEN doctor_id = $1 THEN tstzrange($4::TIMESTAMPTZ - policy.buffer, $5::TIMESTAMPTZ + policy.buffer)
+			ELSE tstzrange($4::TIMESTAMPTZ, $5::TIMESTAMPTZ)
+		END
+		LIMIT 1
 	),
 	check_schedule AS (
-    	SELECT total_appointment_time, total_appointments
-    	FROM Schedules
-    	WHERE doctor_id = $1
-    	AND (date = $3 OR date IS NULL)
+		SELECT schedule_id, total_appointment_time, total_appointments, max_appointments, max_appointment_time
+		FROM Schedules
+		WHERE doctor_id = $1
+		AND date = $3::DATE
+	),
+	within_hours AS (
+		SELECT 1
+		FROM ScheduleBlocks
+		INNER JOIN check_schedule ON ScheduleBlocks.schedule_id = check_schedule.schedule_id
+		WHERE ScheduleBlocks.start_time <= $4::TIMESTAMPTZ
+		AND ScheduleBlocks.end_time >= $5::TIMESTAMPTZ
+	),
+	on_time_off AS (
+		SELECT 1
+		FROM TimeOffBlocks
+		INNER JOIN TimeOff ON TimeOffBlocks.time_off_id = TimeOff.time_off_id
+		WHERE TimeOff.doctor_id = $1
+		AND TimeOff.status IN ('pending', 'approved')
+		AND tstzrange(TimeOffBlocks.start_time, TimeOffBlocks.end_time) && tstzrange($4::TIMESTAMPTZ, $5::TIMESTAMPTZ)
+	),
+	held_slot AS (
+		SELECT 1
+		FROM WaitlistOffers
+		INNER JOIN WaitlistEntries ON WaitlistOffers.waitlist_entry_id = WaitlistEntries.waitlist_entry_id
+		WHERE WaitlistOffers.doctor_id = $1
+		AND WaitlistOffers.status = 'open'
+		AND WaitlistOffers.expires_at > NOW()
+		AND WaitlistEntries.patient_id <> $2
+		AND tstzrange(WaitlistOffers.start_time, WaitlistOffers.end_time) && tstzrange($4::TIMESTAMPTZ, $5::TIMESTAMPTZ)
+	),
+	check_hold AS (
+		SELECT 1
+		FROM AppointmentHolds, policy
+		WHERE AppointmentHolds.doctor_id = $1
+		AND AppointmentHolds.patient_id <> $2
+		AND AppointmentHolds.status = 'held'
+		AND AppointmentHolds.expires_at > NOW()
+		AND tstzrange(AppointmentHolds.start_time, AppointmentHolds.end_time) && tstzrange($4::TIMESTAMPTZ - policy.buffer, $5::TIMESTAMPTZ + policy.buffer)
+	),
+	held_load AS (
+		SELECT COUNT(*) AS holds, COALESCE(SUM(end_time - start_time), INTERVAL '0') AS held_time
+		FROM AppointmentHolds
+		WHERE doctor_id = $1
+		AND patient_id <> $2
+		AND appointment_date = $3::DATE
+		AND status = 'held'
+		AND expires_at > NOW()
 	),
 	valid_duration AS (
-    	SELECT
-        	CASE
-				WHEN COALESCE(total_appointments, 0) + 1 > 12 THEN FALSE
-            	WHEN COALESCE(total_appointment_time, '00:00:00') + ($5 - $4) > '08:00:00' THEN FALSE
-            	ELSE TRUE
-        	END AS is_valid
-    	FROM check_schedule
+		SELECT
+			CASE
+				WHEN total_appointments + holds + 1 > max_appointments THEN FALSE
+				WHEN total_appointment_time + held_time + ($5::TIMESTAMPTZ - $4::TIMESTAMPTZ) > max_appointment_time THEN FALSE
+				ELSE TRUE
+			END AS is_valid
+		FROM check_schedule, held_load
 	),
+	valid_status AS (
+		SELECT
+			CASE
+				WHEN EXISTS (SELECT 1 FROM valid_request WHERE status <> 'Valid') THEN (SELECT status FROM valid_request)
+				WHEN EXISTS (SELECT 1 FROM check_appointment) THEN 'Appointment Exists'
+				WHEN NOT EXISTS (SELECT 1 FROM check_schedule) THEN 'Schedule Not Found'
+				WHEN NOT EXISTS (SELECT 1 FROM within_hours) THEN 'Outside Working Hours'
+				WHEN EXISTS (SELECT 1 FROM on_time_off) THEN 'Doctor On Time Off'
+				WHEN EXISTS (SELECT 1 FROM held_slot) OR EXISTS (SELECT 1 FROM check_hold) THEN 'Slot Held'
+				WHEN EXISTS (SELECT 1 FROM valid_duration WHERE is_valid = FALSE) THEN 'Doctor Overbooked'
+				ELSE 'Valid'
+			END AS status
+	)`
+
+	// Book an appointment under the rules of bookingChecks, $6 is its type and $7 the reason for the visit.
+	// Holds of the patient the appointment overlaps are confirmed into it, so they stop counting against the day.
+	BookAppointmentQuery = `
+	WITH` + bookingChecks + `,
 	insert_appointment AS (
-    	INSERT INTO Appointment (
-        	doctor_id, 
-        	patient_id, 
-        	appointment_date, 
-        	start_time, 
-        	end_time
-    	)
-    	SELECT $1, $2, $3, $4, $5
-    	WHERE NOT EXISTS (SELECT 1 FROM check_appointment)
-    	AND EXISTS (SELECT 1 FROM check_schedule)
-    	AND EXISTS (SELECT 1 FROM valid_duration WHERE is_valid = TRUE)
-    	RETURNING appointment_id
+		INSERT INTO Appointment (
+			doctor_id,
+			patient_id,
+			appointment_date,
+			start_time,
+			end_time,
+			type_id,
+			reason
+		)
+		SELECT $1, $2, $3, $4, $5, $6, NULLIF($7, '')
+		WHERE EXISTS (SELECT 1 FROM valid_status WHERE status = 'Valid')
+		RETURNING appointment_id
 	),
-	valid_status AS (
-    	SELECT 
-        	CASE
-            	WHEN EXISTS (SELECT 1 FROM check_appointment) THEN 'Appointment Exists'
-            	WHEN NOT EXISTS (SELECT 1 FROM check_schedule) THEN 'Schedule Not Found'
-            	WHEN EXISTS (SELECT 1 FROM valid_duration WHERE is_valid = FALSE) THEN 'Doctor Overbooked'
-            	ELSE 'Valid'
-        	END AS status
+	confirm_own_holds AS (
+		UPDATE AppointmentHolds
+		SET status = 'confirmed', appointment_id = (SELECT appointment_id FROM insert_appointment)
+		WHERE doctor_id = $1
+		AND patient_id = $2
+		AND status = 'held'
+		AND tstzrange(start_time, end_time) && tstzrange($4::TIMESTAMPTZ, $5::TIMESTAMPTZ)
+		AND EXISTS (SELECT 1 FROM insert_appointment)
 	)
-	SELECT 
-	(SELECT appointment_id FROM insert_appointment) AS appointment_id,
-    (SELECT status FROM valid_status) AS status;
+	SELECT
+		(SELECT appointment_id FROM insert_appointment) AS appointment_id,
+		(SELECT status FROM valid_status) AS status,
+		(SELECT appointment_id FROM check_appointment) AS conflict_id;
+	`
+
+	// Find the appointment a booking of the doctor or patient between $3 and $4 collides with
+	FindConflictingAppointmentQuery = `
+		SELECT appointment_id
+		FROM Appointment
+		WHERE (doctor_id = $1 OR patient_id = $2)
+		AND status NOT IN ('canceled', 'no_show')
+		AND tstzrange(start_time, end_time) && tstzrange($3::TIMESTAMPTZ, $4::TIMESTAMPTZ)
+		LIMIT 1;
 	`
 
 	// View appointment details
@@ -58,44 +168,223 @@ const (
 		SELECT 
 			Appointment.appointment_id, 
 			Appointment.patient_id,
+			Appointment.doctor_id,
 			Patient.name AS patient_name,
 			Doctor.name AS doctor_name,
 			Appointment.start_time, 
 			Appointment.end_time,
-			Appointment.status
+			Appointment.status,
+			Appointment.canceled_by,
+			Appointment.canceled_at,
+			Appointment.cancel_reason,
+			Appointment.rescheduled_from,
+			Appointment.series_id,
+			Appointment.type_id,
+			AppointmentTypes.name AS type_name,
+			Appointment.reason,
+			Appointment.checked_in_at,
+			Appointment.started_at,
+			Appointment.completed_at,
+			Appointment.no_show_at
 		FROM Appointment
 		INNER JOIN Users AS Patient ON Appointment.patient_id = Patient.user_id
 		INNER JOIN Users AS Doctor ON Appointment.doctor_id = Doctor.user_id
-		WHERE Appointment.appointment_id = $1 
-		AND (Patient.user_id = $2 OR Doctor.user_id = $2);
+		LEFT JOIN AppointmentTypes ON Appointment.type_id = AppointmentTypes.type_id
+		WHERE Appointment.appointment_id = $1;
 	`
 
-	// View patient appointment history
+	// View patient appointment history, cancelled appointments are only included when $2 is true
 	GetPatientAppointmentHistoryQuery = `
 		SELECT 
 			Appointment.appointment_id, 
 			Patient.user_id AS patient_id,
+			Appointment.doctor_id,
 			Doctor.name AS doctor_name,
 			Patient.name AS patient_name,
 			Appointment.start_time, 
 			Appointment.end_time,
-			Appointment.status
+			Appointment.status,
+			Appointment.canceled_by,
+			Appointment.canceled_at,
+			Appointment.cancel_reason,
+			Appointment.rescheduled_from,
+			Appointment.series_id,
+			Appointment.type_id,
+			AppointmentTypes.name AS type_name,
+			Appointment.reason,
+			Appointment.checked_in_at,
+			Appointment.started_at,
+			Appointment.completed_at,
+			Appointment.no_show_at
 		FROM Appointment
 		INNER JOIN Users AS Patient ON Appointment.patient_id = Patient.user_id
 		INNER JOIN Users AS Doctor ON Appointment.doctor_id = Doctor.user_id
+		LEFT JOIN AppointmentTypes ON Appointment.type_id = AppointmentTypes.type_id
 		WHERE Appointment.patient_id = $1
+		AND ($2 OR Appointment.status <> 'canceled')
 		ORDER BY Appointment.appointment_id DESC;
 	`
 
-	// Cancel an appointment
+	// Cancel an appointment that still has the status $4, the schedule is released by a trigger
 	CancelAppointmentQuery = `
-		DELETE FROM Appointment
+		UPDATE Appointment
+		SET status = 'canceled',
+			canceled_by = $2,
+			canceled_at = NOW(),
+			cancel_reason = NULLIF($3, '')
+		WHERE appointment_id = $1
+		AND status = $4
+		RETURNING appointment_id;
+	`
+
+	// Get the status and times of an appointment
+	GetAppointmentStatusQuery = `
+		SELECT status, start_time, end_time
+		FROM Appointment
 		WHERE appointment_id = $1;
 	`
 
-	// Delete slot on delete appointment
+	// Move an appointment from status $2 to status $3 and record when it happened
+	UpdateAppointmentStatusQuery = `
+		UPDATE Appointment
+		SET status = $3::VARCHAR,
+			checked_in_at = CASE WHEN $3::VARCHAR = 'checked_in' THEN NOW() ELSE checked_in_at END,
+			started_at = CASE WHEN $3::VARCHAR = 'in_progress' THEN NOW() ELSE started_at END,
+			completed_at = CASE WHEN $3::VARCHAR = 'completed' THEN NOW() ELSE completed_at END,
+			no_show_at = CASE WHEN $3::VARCHAR = 'no_show' THEN NOW() ELSE no_show_at END
+		WHERE appointment_id = $1
+		AND status = $2
+		RETURNING appointment_id;
+	`
+
+	// Mark scheduled appointments that ended more than $1 seconds ago as no-show
+	MarkNoShowsQuery = `
+		UPDATE Appointment
+		SET status = 'no_show',
+			no_show_at = NOW()
+		WHERE status = 'scheduled'
+		AND end_time < NOW() - make_interval(secs => $1);
+	`
+
+	// Lock an appointment that is about to be rescheduled
+	LockAppointmentQuery = `
+		SELECT doctor_id, patient_id, start_time, status, type_id, COALESCE(reason, '')
+		FROM Appointment
+		WHERE appointment_id = $1
+		FOR UPDATE;
+	`
+
+	// Link a booking to the appointment it replaces, it keeps the series of the original
+	LinkRescheduledAppointmentQuery = `
+		UPDATE Appointment
+		SET rescheduled_from = original.appointment_id,
+			series_id = original.series_id
+		FROM Appointment original
+		WHERE Appointment.appointment_id = $1
+		AND original.appointment_id = $2;
+	`
+
+	// Delete slot on cancel appointment
 	DeleteSlotQuery = `
 	DELETE FROM Slot 
 		WHERE appointment_id = $1; 
 	`
+
+	// Record a recurring appointment
+	InsertSeriesQuery = `
+		INSERT INTO AppointmentSeries (patient_id, doctor_id, rrule, start_time, end_time)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING series_id, created_at;
+	`
+
+	// Add a booked occurrence to its series
+	SetAppointmentSeriesQuery = `
+		UPDATE Appointment
+		SET series_id = $2
+		WHERE appointment_id = $1;
+	`
+
+	// Get the scheduled occurrences of the series of an appointment, from the appointment on for 'following'
+	// and every upcoming one for 'all'
+	GetSeriesAppointmentsQuery = `
+		SELECT a.appointment_id, a.doctor_id, a.patient_id, a.appointment_date, a.start_time, a.end_time, a.status
+		FROM Appointment a
+		INNER JOIN Appointment anchor ON anchor.series_id = a.series_id
+		WHERE anchor.appointment_id = $1
+		AND a.status = 'scheduled'
+		AND CASE
+			WHEN $2 = 'all' THEN a.start_time > NOW()
+			ELSE a.start_time >= anchor.start_time
+		END
+		ORDER BY a.start_time;
+	`
+
+	// Occurrences are booked and moved behind a savepoint, so one failing does not abort the others
+	SavepointQuery         = `SAVEPOINT occurrence;`
+	RollbackSavepointQuery = `ROLLBACK TO SAVEPOINT occurrence;`
+	ReleaseSavepointQuery  = `RELEASE SAVEPOINT occurrence;`
+
+	// Hold a time for a patient under the rules of bookingChecks, the hold lasts $6 seconds
+	CreateHoldQuery = `
+	WITH` + bookingChecks + `,
+	insert_hold AS (
+		INSERT INTO AppointmentHolds (patient_id, doctor_id, appointment_date, start_time, end_time, expires_at, type_id, reason)
+		SELECT $2, $1, $3::DATE, $4, $5, NOW() + make_interval(secs => $6), $7, NULLIF($8, '')
+		WHERE EXISTS (SELECT 1 FROM valid_status WHERE status = 'Valid')
+		RETURNING hold_id
+	)
+	SELECT
+		(SELECT hold_id FROM insert_hold) AS hold_id,
+		(SELECT status FROM valid_status) AS status,
+		(SELECT appointment_id FROM check_appointment) AS conflict_id;
+	`
+
+	// View a hold
+	GetHoldQuery = `
+		SELECT hold_id, patient_id, doctor_id, appointment_date, start_time, end_time, status, expires_at, type_id, reason, appointment_id, created_at
+		FROM AppointmentHolds
+		WHERE hold_id = $1;
+	`
+
+	// Lock a hold while it is confirmed
+	LockHoldQuery = `
+		SELECT hold_id, patient_id, doctor_id, appointment_date, start_time, end_time, status, expires_at, type_id, reason, appointment_id, created_at
+		FROM AppointmentHolds
+		WHERE hold_id = $1
+		FOR UPDATE;
+	`
+
+	// Confirm a hold that has not run out, it stops counting as a hold before its appointment is booked
+	ConfirmHoldQuery = `
+		UPDATE AppointmentHolds
+		SET status = 'confirmed'
+		WHERE hold_id = $1
+		AND status = 'held'
+		AND expires_at > NOW()
+		RETURNING hold_id;
+	`
+
+	// Record the appointment a hold was confirmed into
+	LinkHoldAppointmentQuery = `
+		UPDATE AppointmentHolds
+		SET appointment_id = $2
+		WHERE hold_id = $1;
+	`
+
+	// Stop holding a time before the hold expires
+	ReleaseHoldQuery = `
+		UPDATE AppointmentHolds
+		SET status = 'released'
+		WHERE hold_id = $1
+		AND status = 'held'
+		RETURNING hold_id;
+	`
+
+	// Mark the holds that ran out as expired
+	ExpireHoldsQuery = `
+		UPDATE AppointmentHolds
+		SET status = 'expired'
+		WHERE status = 'held'
+		AND expires_at <= NOW();
+	`
 )
@@ -0,0 +1,140 @@
+package appointmenttypes
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+
+	"go.uber.org/zap"
+)
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanType reads an appointment type in the column order of the type queries
+func scanType(row rowScanner) (models.AppointmentType, error) {
+	var aptmtType models.AppointmentType
+	err := row.Scan(
+		&aptmtType.ID,
+		&aptmtType.Name,
+		&aptmtType.DefaultDurationMinutes,
+		&aptmtType.MinDurationMinutes,
+		&aptmtType.MaxDurationMinutes,
+		&aptmtType.Color,
+		&aptmtType.PrepInstructions,
+		&aptmtType.Active,
+		&aptmtType.CreatedAt,
+		&aptmtType.UpdatedAt,
+	)
+	return aptmtType, err
+}
+
+// GetTypes retrieves the appointment types, the inactive ones only when includeInactive is set
+func (r *repo) GetTypes(ftx factory.Service, includeInactive bool) ([]models.AppointmentType, error) {
+	return queryTypes(ftx, "Could not retrieve appointment types", GetTypesQuery, includeInactive)
+}
+
+// GetDoctorTypes retrieves the active types a doctor offers
+func (r *repo) GetDoctorTypes(ftx factory.Service, doctorId int) ([]models.AppointmentType, error) {
+	return queryTypes(ftx, "Could not retrieve doctor appointment types", GetDoctorTypesQuery, doctorId)
+}
+
+// GetOfferedType retrieves an appointment type for a booking with a doctor.
+// It returns ErrTypeNotOffered when the type is inactive or the doctor does not offer it.
+func (r *repo) GetOfferedType(ftx factory.Service, doctorId, typeId int) (models.AppointmentType, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return models.AppointmentType{}, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for retrieving offered appointment type")
+
+	// Defer a rollback in case anything fails
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	aptmtType, err := scanType(tx.QueryRowContext(ftx.Context(), GetOfferedTypeQuery, doctorId, typeId))
+	if err == sql.ErrNoRows {
+		return aptmtType, errors.ErrTypeNotOffered
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve appointment type", zap.Error(err))
+		return aptmtType, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return models.AppointmentType{}, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully retrieved offered appointment type", zap.Int("Type ID", aptmtType.ID))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return aptmtType, nil
+}
+
+// queryTypes runs a query that returns appointment types and collects them
+func queryTypes(ftx factory.Service, failure string, query string, args ...any) ([]models.AppointmentType, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for retrieving appointment types")
+
+	// Defer a rollback in case anything fails
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	rows, err := tx.QueryContext(ftx.Context(), query, args...)
+	if err != nil {
+		ftx.Logger().Error(failure, zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	defer rows.Close()
+
+	types := []models.AppointmentType{}
+	for rows.Next() {
+		var aptmtType models.AppointmentType
+		aptmtType, err = scanType(rows)
+		if err != nil {
+			ftx.Logger().Error("Error scanning appointment type row", zap.Error(err))
+			return nil, errors.ErrDatabase
+		}
+		types = append(types, aptmtType)
+	}
+	if err = rows.Err(); err != nil {
+		ftx.Logger().Error(failure, zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully retrieved appointment types", zap.Int("Count", len(types)))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return types, nil
+}
@@ -0,0 +1,66 @@
+package appointmenttypes
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	stderrors "errors"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// CreateType adds an appointment type, it is active right away
+func (r *repo) CreateType(ftx factory.Service, aptmtType models.AppointmentType) (models.AppointmentType, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return models.AppointmentType{}, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for creating appointment type")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	created, err := scanType(tx.QueryRowContext(ftx.Context(), InsertTypeQuery,
+		aptmtType.Name,
+		aptmtType.DefaultDurationMinutes,
+		aptmtType.MinDurationMinutes,
+		aptmtType.MaxDurationMinutes,
+		aptmtType.Color,
+		aptmtType.PrepInstructions,
+	))
+	if isUniqueViolation(err) {
+		return created, errors.ErrAptmtTypeExists
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not create appointment type", zap.Error(err))
+		return created, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return models.AppointmentType{}, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Created appointment type", zap.Int("Type ID", created.ID))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return created, nil
+}
+
+// isUniqueViolation reports whether err is a violation of a unique constraint, such as the one on the type name
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return stderrors.As(err, &pqErr) && pqErr.Code == "23505"
+}
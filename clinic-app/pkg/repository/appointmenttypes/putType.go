@@ -0,0 +1,175 @@
+package appointmenttypes
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// UpdateType changes an appointment type, the appointments already booked keep their duration
+func (r *repo) UpdateType(ftx factory.Service, aptmtType models.AppointmentType) (models.AppointmentType, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return models.AppointmentType{}, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for updating appointment type")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	updated, err := scanType(tx.QueryRowContext(ftx.Context(), UpdateTypeQuery,
+		aptmtType.ID,
+		aptmtType.Name,
+		aptmtType.DefaultDurationMinutes,
+		aptmtType.MinDurationMinutes,
+		aptmtType.MaxDurationMinutes,
+		aptmtType.Color,
+		aptmtType.PrepInstructions,
+		aptmtType.Active,
+	))
+	if err == sql.ErrNoRows {
+		return updated, errors.ErrNotFound
+	}
+	if isUniqueViolation(err) {
+		return updated, errors.ErrAptmtTypeExists
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not update appointment type", zap.Error(err))
+		return updated, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return models.AppointmentType{}, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Updated appointment type", zap.Int("Type ID", updated.ID))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return updated, nil
+}
+
+// DeactivateType stops an appointment type from being booked
+func (r *repo) DeactivateType(ftx factory.Service, typeId int) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for deactivating appointment type")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	var deactivatedId int
+	err = tx.QueryRowContext(ftx.Context(), DeactivateTypeQuery, typeId).Scan(&deactivatedId)
+	if err == sql.ErrNoRows {
+		return errors.ErrNotFound
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not deactivate appointment type", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Deactivated appointment type", zap.Int("Type ID", typeId))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
+
+// SetDoctorTypes replaces the types a doctor offers, an empty list lets the doctor offer every active type.
+// It returns ErrNotFound when the user is not a doctor or a type is unknown or inactive.
+func (r *repo) SetDoctorTypes(ftx factory.Service, doctorId int, typeIds []int) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for setting doctor appointment types")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Only doctors offer appointment types
+	var isDoctor bool
+	err = tx.QueryRowContext(ftx.Context(), IsDoctorQuery, doctorId).Scan(&isDoctor)
+	if err != nil {
+		ftx.Logger().Error("Could not check doctor", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	if !isDoctor {
+		err = errors.ErrNotFound // Roll back the transaction
+		return err
+	}
+
+	ids := pq.Array(typeIds)
+	var active int
+	err = tx.QueryRowContext(ftx.Context(), CountActiveTypesQuery, ids).Scan(&active)
+	if err != nil {
+		ftx.Logger().Error("Could not check appointment types", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	if active != len(typeIds) {
+		err = errors.ErrNotFound // Roll back the transaction
+		return err
+	}
+
+	_, err = tx.ExecContext(ftx.Context(), DeleteDoctorTypesQuery, doctorId)
+	if err != nil {
+		ftx.Logger().Error("Could not delete doctor appointment types", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	_, err = tx.ExecContext(ftx.Context(), InsertDoctorTypesQuery, doctorId, ids)
+	if err != nil {
+		ftx.Logger().Error("Could not insert doctor appointment types", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully set doctor appointment types", zap.Int("Doctor ID", doctorId))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
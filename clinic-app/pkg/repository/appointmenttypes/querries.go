@@ -0,0 +1,101 @@
+package appointmenttypes
+
+const (
+	// View the appointment types, inactive ones are only included when $1 is true
+	GetTypesQuery = `
+		SELECT type_id, name, default_duration_minutes, min_duration_minutes, max_duration_minutes,
+			color, prep_instructions, active, created_at, updated_at
+		FROM AppointmentTypes
+		WHERE $1 OR active
+		ORDER BY name;
+	`
+
+	// Add an appointment type
+	InsertTypeQuery = `
+		INSERT INTO AppointmentTypes (name, default_duration_minutes, min_duration_minutes, max_duration_minutes, color, prep_instructions)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING type_id, name, default_duration_minutes, min_duration_minutes, max_duration_minutes,
+			color, prep_instructions, active, created_at, updated_at;
+	`
+
+	// Change an appointment type, it stays active or inactive when $8 is NULL
+	UpdateTypeQuery = `
+		UPDATE AppointmentTypes
+		SET name = $2,
+			default_duration_minutes = $3,
+			min_duration_minutes = $4,
+			max_duration_minutes = $5,
+			color = $6,
+			prep_instructions = $7,
+			active = COALESCE($8, active),
+			updated_at = NOW()
+		WHERE type_id = $1
+		RETURNING type_id, name, default_duration_minutes, min_duration_minutes, max_duration_minutes,
+			color, prep_instructions, active, created_at, updated_at;
+	`
+
+	// Stop offering an appointment type, the appointments already booked keep it
+	DeactivateTypeQuery = `
+		UPDATE AppointmentTypes
+		SET active = FALSE,
+			updated_at = NOW()
+		WHERE type_id = $1
+		RETURNING type_id;
+	`
+
+	// View the active types a doctor offers, every active type when the doctor did not choose any
+	GetDoctorTypesQuery = `
+		SELECT t.type_id, t.name, t.default_duration_minutes, t.min_duration_minutes, t.max_duration_minutes,
+			t.color, t.prep_instructions, t.active, t.created_at, t.updated_at
+		FROM AppointmentTypes t
+		WHERE t.active
+		AND (
+			EXISTS (SELECT 1 FROM DoctorAppointmentTypes dt WHERE dt.doctor_id = $1 AND dt.type_id = t.type_id)
+			OR NOT EXISTS (SELECT 1 FROM DoctorAppointmentTypes dt WHERE dt.doctor_id = $1)
+		)
+		ORDER BY t.name;
+	`
+
+	// Get an active type if the doctor offers it
+	GetOfferedTypeQuery = `
+		SELECT t.type_id, t.name, t.default_duration_minutes, t.min_duration_minutes, t.max_duration_minutes,
+			t.color, t.prep_instructions, t.active, t.created_at, t.updated_at
+		FROM AppointmentTypes t
+		WHERE t.type_id = $2
+		AND t.active
+		AND (
+			EXISTS (SELECT 1 FROM DoctorAppointmentTypes dt WHERE dt.doctor_id = $1 AND dt.type_id = t.type_id)
+			OR NOT EXISTS (SELECT 1 FROM DoctorAppointmentTypes dt WHERE dt.doctor_id = $1)
+		);
+	`
+
+	// Check whether a user is a doctor
+	IsDoctorQuery = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM Users
+			WHERE user_id = $1
+			AND role = 'doctor'
+		);
+	`
+
+	// Count how many of the given types exist and are active
+	CountActiveTypesQuery = `
+		SELECT COUNT(*)
+		FROM AppointmentTypes
+		WHERE type_id = ANY($1)
+		AND active;
+	`
+
+	// Forget the types a doctor offered
+	DeleteDoctorTypesQuery = `
+		DELETE FROM DoctorAppointmentTypes
+		WHERE doctor_id = $1;
+	`
+
+	// Record the types a doctor offers
+	InsertDoctorTypesQuery = `
+		INSERT INTO DoctorAppointmentTypes (doctor_id, type_id)
+		SELECT $1, UNNEST($2::INT[]);
+	`
+)
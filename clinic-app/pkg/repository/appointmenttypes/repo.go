@@ -0,0 +1,12 @@
+package appointmenttypes
+
+import (
+	"clinic-app/pkg/repository"
+)
+
+type repo struct{}
+
+// New creates a new instance of repository with a database connection
+func New() repository.AppointmentTypeRepository {
+	return &repo{}
+}
@@ -3,10 +3,35 @@ package repository
 import (
 	"clinic-app/pkg/domain/models"
 	"clinic-app/pkg/services/factory"
+	"time"
 )
 
 // AuthenticationRepository defines methods for user authentication and registration.
 type AuthenticationRepository interface {
 	RegisterUser(ftx factory.Service, user models.User) (int, error)
-	LoginUser(ftx factory.Service, username, password string) (models.User, error)
+	GetUserByUsername(ftx factory.Service, username string) (models.User, error)
+	UpdatePassword(ftx factory.Service, userID int, passwordHash string) error
+	GetUserByID(ftx factory.Service, userID int) (models.User, error)
+	GetUsersByEmail(ftx factory.Service, email string) ([]models.User, error)
+	CreateUserToken(ftx factory.Service, userID int, purpose, tokenHash string, ttl time.Duration) error
+	VerifyEmail(ftx factory.Service, tokenHash string) (int, error)
+	ResetPassword(ftx factory.Service, tokenHash, passwordHash string) (int, []models.RevokedToken, error)
+	StoreRefreshToken(ftx factory.Service, userID int, token models.RefreshToken) error
+	RotateRefreshToken(ftx factory.Service, oldHash string, next models.RefreshToken) (models.RefreshSession, error)
+	GetRefreshTokenFamily(ftx factory.Service, tokenHash string) (string, error)
+	RevokeTokenFamily(ftx factory.Service, familyID string) ([]models.RevokedToken, error)
+	RevokeAccessToken(ftx factory.Service, token models.RevokedToken) error
+	IsAccessTokenRevoked(ftx factory.Service, jti string) (bool, error)
+	GetMFA(ftx factory.Service, userID int) (models.MFASettings, error)
+	SaveMFASecret(ftx factory.Service, userID int, sealedSecret string) error
+	EnableMFA(ftx factory.Service, userID int, step int64, codeHashes []string) error
+	UseTOTPStep(ftx factory.Service, userID int, step int64) error
+	UseRecoveryCode(ftx factory.Service, userID int, codeHash string) error
+	ReplaceRecoveryCodes(ftx factory.Service, userID int, codeHashes []string) error
+	DeleteMFA(ftx factory.Service, userID int) error
+	CreateInvitation(ftx factory.Service, tokenHash string, invite models.NewInvitation, ttl time.Duration) (models.Invitation, error)
+	AcceptInvitation(ftx factory.Service, tokenHash string, user models.User) (int, error)
+	GetInvitations(ftx factory.Service) ([]models.Invitation, error)
+	GetInvitationEvents(ftx factory.Service, invitationID int) ([]models.InvitationEvent, error)
+	RevokeInvitation(ftx factory.Service, invitationID int) error
 }
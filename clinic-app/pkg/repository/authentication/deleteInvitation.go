@@ -0,0 +1,64 @@
+package authentication
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+
+	"go.uber.org/zap"
+)
+
+// RevokeInvitation revokes an invitation that has not been accepted yet
+func (r *repo) RevokeInvitation(ftx factory.Service, invitationID int) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for revoking invitation")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				// Log rollback failure
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Revoke the invitation if it is still open
+	var revokedID int
+	err = tx.QueryRowContext(ftx.Context(), RevokeInvitationQuery, invitationID).Scan(&revokedID)
+	if err == sql.ErrNoRows {
+		ftx.Logger().Info("No open invitation to revoke", zap.Int("InvitationID", invitationID))
+		return errors.ErrNotFound
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not revoke invitation", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Record the revocation in the audit trail
+	_, err = tx.ExecContext(ftx.Context(), InsertInvitationEventQuery, invitationID, "revoked", nullableID(ftx.Principal().UserID), nil)
+	if err != nil {
+		ftx.Logger().Error("Could not record invitation event", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		// Log and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully revoked invitation", zap.Int("InvitationID", invitationID))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
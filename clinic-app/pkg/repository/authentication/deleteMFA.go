@@ -0,0 +1,53 @@
+package authentication
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/services/factory"
+
+	"go.uber.org/zap"
+)
+
+// DeleteMFA removes a user's TOTP configuration together with their recovery codes
+func (r *repo) DeleteMFA(ftx factory.Service, userID int) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for disabling two-factor authentication")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				// Log rollback failure
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	if _, err = tx.ExecContext(ftx.Context(), DeleteRecoveryCodesQuery, userID); err != nil {
+		ftx.Logger().Error("Could not delete recovery codes", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	if _, err = tx.ExecContext(ftx.Context(), DeleteMFAQuery, userID); err != nil {
+		ftx.Logger().Error("Could not delete two-factor settings", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		// Log and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully disabled two-factor authentication", zap.Int("UserID", userID))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
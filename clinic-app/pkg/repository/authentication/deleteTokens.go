@@ -0,0 +1,103 @@
+package authentication
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RevokeTokenFamily revokes every refresh token of a family together with the access tokens issued alongside them
+func (r *repo) RevokeTokenFamily(ftx factory.Service, familyID string) ([]models.RevokedToken, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for revoking token family")
+
+	var revoked []models.RevokedToken
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				// Log rollback failure
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Execute the query to revoke the family
+	rows, err := tx.QueryContext(ftx.Context(), RevokeTokenFamilyQuery, familyID)
+	if err != nil {
+		ftx.Logger().Error("Could not revoke token family", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	defer rows.Close()
+
+	// Collect the access tokens that are now revoked
+	for rows.Next() {
+		var token models.RevokedToken
+		var expiresAt int64
+		if err = rows.Scan(&token.JTI, &expiresAt); err != nil {
+			return nil, errors.ErrDatabase
+		}
+		token.ExpiresAt = time.Unix(expiresAt, 0)
+		revoked = append(revoked, token)
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		// Log and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully revoked token family",
+		zap.String("Family", familyID),
+		zap.Int("Access tokens", len(revoked)),
+	)
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return revoked, nil
+}
+
+// RevokeAccessToken adds an access token to the revocation list
+func (r *repo) RevokeAccessToken(ftx factory.Service, token models.RevokedToken) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for revoking access token")
+
+	// Execute the insert query within the transaction context
+	_, err = tx.ExecContext(ftx.Context(), RevokeAccessTokenQuery, token.JTI, token.ExpiresAt.Unix())
+	if err != nil {
+		// Log error and rollback transaction if insert fails
+		ftx.Logger().Error("Could not revoke access token", zap.Error(err))
+		tx.Rollback() // Rollback transaction on error
+		return errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		// Log error and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully revoked access token", zap.String("JTI", token.JTI))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package authentication
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+
+	"go.uber.org/zap"
+)
+
+// GetInvitations retrieves all staff invitations
+func (r *repo) GetInvitations(ftx factory.Service) ([]models.Invitation, error) {
+	var invitations []models.Invitation
+
+	// Execute the query to list the invitations
+	rows, err := ftx.PSQL().QueryContext(ftx.Context(), GetInvitationsQuery)
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve invitations", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	defer rows.Close()
+
+	// Scan the results into invitation models
+	for rows.Next() {
+		inv, err := scanInvitation(rows)
+		if err != nil {
+			ftx.Logger().Error("Error scanning invitation row", zap.Error(err))
+			return nil, errors.ErrDatabase
+		}
+		invitations = append(invitations, inv)
+	}
+
+	ftx.Logger().Info("Successfully retrieved invitations", zap.Int("Count", len(invitations)))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return invitations, nil
+}
+
+// GetInvitationEvents retrieves the audit trail of an invitation
+func (r *repo) GetInvitationEvents(ftx factory.Service, invitationID int) ([]models.InvitationEvent, error) {
+	var events []models.InvitationEvent
+
+	// Execute the query to list the events
+	rows, err := ftx.PSQL().QueryContext(ftx.Context(), GetInvitationEventsQuery, invitationID)
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve invitation events", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	defer rows.Close()
+
+	// Scan the results into event models
+	for rows.Next() {
+		var event models.InvitationEvent
+		if err := rows.Scan(
+			&event.EventID,
+			&event.InvitationID,
+			&event.Event,
+			&event.ActorID,
+			&event.Details,
+			&event.CreatedAt,
+		); err != nil {
+			ftx.Logger().Error("Error scanning invitation event row", zap.Error(err))
+			return nil, errors.ErrDatabase
+		}
+		events = append(events, event)
+	}
+
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return events, nil
+}
@@ -11,8 +11,8 @@ import (
 	"go.uber.org/zap"
 )
 
-// LoginUser retrieves a user by email and validates the password
-func (r *repo) LoginUser(ftx factory.Service, username, password string) (models.User, error) {
+// GetUserByUsername retrieves a user and their stored password hash by username
+func (r *repo) GetUserByUsername(ftx factory.Service, username string) (models.User, error) {
 	var user models.User
 
 	// Start a new transaction
@@ -24,8 +24,8 @@ func (r *repo) LoginUser(ftx factory.Service, username, password string) (models
 	}
 	ftx.Logger().Info("Transaction started for user login")
 
-	// Retrieve user data based on email and password
-	err = tx.QueryRowContext(ftx.Context(), LoginUserQuery, username, password).Scan(&user.ID, &user.Username, &user.Password, &user.Role)
+	// Retrieve user data based on username, the password is verified by the usecase
+	err = tx.QueryRowContext(ftx.Context(), GetUserByUsernameQuery, username).Scan(&user.ID, &user.Username, &user.Password, &user.Role)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// Log and return error if the user is not found
@@ -39,14 +39,6 @@ func (r *repo) LoginUser(ftx factory.Service, username, password string) (models
 		return user, errors.ErrDatabase
 	}
 
-	// Validate the provided password (no hashing, just comparison)
-	if user.Password != password {
-		// Log and return error if the password is incorrect
-		ftx.Logger().Error("Invalid password")
-		tx.Rollback() // Rollback transaction in case of invalid password
-		return user, errors.ErrInvalidPassword
-	}
-
 	// Commit the transaction if no errors occurred
 	if err := ftx.TransactionManager().Commit(tx); err != nil {
 		// Log and return error if transaction commit fails
@@ -54,19 +46,8 @@ func (r *repo) LoginUser(ftx factory.Service, username, password string) (models
 		return user, errors.ErrDatabase
 	}
 
-	// Defer a rollback in case of any subsequent errors (redundant here as commit has succeeded)
-	defer func() {
-		if err != nil {
-			rollbackErr := ftx.TransactionManager().Rollback(tx)
-			if rollbackErr != nil {
-				// Log rollback failure
-				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
-			}
-		}
-	}()
-
 	// Log success and return user information
-	ftx.Logger().Info("Successfully logged in user",
+	ftx.Logger().Info("Successfully retrieved user for login",
 		zap.Any("User", user.ID),
 	)
 	middleware.GetTraceParentFromContext(ftx.Context())
@@ -0,0 +1,32 @@
+package authentication
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+
+	"go.uber.org/zap"
+)
+
+// GetMFA retrieves a user's TOTP configuration
+func (r *repo) GetMFA(ftx factory.Service, userID int) (models.MFASettings, error) {
+	var settings models.MFASettings
+
+	// Retrieve the configuration, a single read needs no transaction
+	err := ftx.PSQL().QueryRowContext(ftx.Context(), GetMFAQuery, userID).Scan(
+		&settings.UserID,
+		&settings.Secret,
+		&settings.Enabled,
+		&settings.LastUsedStep,
+	)
+	if err == sql.ErrNoRows {
+		return settings, errors.ErrNotFound
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve two-factor settings", zap.Error(err))
+		return settings, errors.ErrDatabase
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,40 @@
+package authentication
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+
+	"go.uber.org/zap"
+)
+
+// GetRefreshTokenFamily retrieves the family a refresh token belongs to
+func (r *repo) GetRefreshTokenFamily(ftx factory.Service, tokenHash string) (string, error) {
+	var familyID string
+
+	// Retrieve the family of the token, a single read needs no transaction
+	err := ftx.PSQL().QueryRowContext(ftx.Context(), GetRefreshTokenFamilyQuery, tokenHash).Scan(&familyID)
+	if err == sql.ErrNoRows {
+		return "", errors.ErrInvalidToken
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve refresh token family", zap.Error(err))
+		return "", errors.ErrDatabase
+	}
+
+	return familyID, nil
+}
+
+// IsAccessTokenRevoked checks whether an access token is on the revocation list
+func (r *repo) IsAccessTokenRevoked(ftx factory.Service, jti string) (bool, error) {
+	var revoked bool
+
+	// Look the token up, this runs on every authenticated request so it skips the transaction manager
+	err := ftx.PSQL().QueryRowContext(ftx.Context(), IsAccessTokenRevokedQuery, jti).Scan(&revoked)
+	if err != nil {
+		ftx.Logger().Error("Could not check token revocation", zap.Error(err))
+		return false, errors.ErrDatabase
+	}
+
+	return revoked, nil
+}
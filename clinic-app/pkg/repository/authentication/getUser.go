@@ -0,0 +1,68 @@
+package authentication
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+
+	"go.uber.org/zap"
+)
+
+// scanUser scans a row selected by GetUserByIDQuery or GetUsersByEmailQuery
+func scanUser(row scanner) (models.User, error) {
+	var user models.User
+	err := row.Scan(
+		&user.ID,
+		&user.Username,
+		&user.Name,
+		&user.Email,
+		&user.Role,
+		&user.EmailVerified,
+	)
+	return user, err
+}
+
+// GetUserByID retrieves a user's account details, without the password
+func (r *repo) GetUserByID(ftx factory.Service, userID int) (models.User, error) {
+	// Retrieve the user, a single read needs no transaction
+	user, err := scanUser(ftx.PSQL().QueryRowContext(ftx.Context(), GetUserByIDQuery, userID))
+	if err == sql.ErrNoRows {
+		ftx.Logger().Info("User not found", zap.Int("UserID", userID))
+		return user, errors.ErrUserNotFound
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve user", zap.Error(err))
+		return user, errors.ErrDatabase
+	}
+
+	middleware.GetTraceParentFromContext(ftx.Context())
+	return user, nil
+}
+
+// GetUsersByEmail retrieves every account registered with an email address, without the passwords
+func (r *repo) GetUsersByEmail(ftx factory.Service, email string) ([]models.User, error) {
+	var users []models.User
+
+	// Execute the query to find the accounts
+	rows, err := ftx.PSQL().QueryContext(ftx.Context(), GetUsersByEmailQuery, email)
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve users", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	defer rows.Close()
+
+	// Scan the results into user models
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			ftx.Logger().Error("Error scanning user row", zap.Error(err))
+			return nil, errors.ErrDatabase
+		}
+		users = append(users, user)
+	}
+
+	middleware.GetTraceParentFromContext(ftx.Context())
+	return users, nil
+}
@@ -0,0 +1,200 @@
+package authentication
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// scanner is satisfied by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanInvitation scans a row selected with invitationColumns
+func scanInvitation(row scanner) (models.Invitation, error) {
+	var inv models.Invitation
+	err := row.Scan(
+		&inv.InvitationID,
+		&inv.Email,
+		&inv.Role,
+		&inv.InvitedBy,
+		&inv.Status,
+		&inv.ExpiresAt,
+		&inv.AcceptedAt,
+		&inv.AcceptedUserID,
+		&inv.RevokedAt,
+		&inv.CreatedAt,
+	)
+	return inv, err
+}
+
+// nullableID turns a zero user ID into NULL
+func nullableID(id int) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+// CreateInvitation stores a new staff invitation and records who created it
+func (r *repo) CreateInvitation(ftx factory.Service, tokenHash string, invite models.NewInvitation, ttl time.Duration) (models.Invitation, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return models.Invitation{}, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for creating invitation")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				// Log rollback failure
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// The admin creating the invitation
+	adminID := ftx.Principal().UserID
+
+	// Execute the insert query within the transaction context
+	inv, err := scanInvitation(tx.QueryRowContext(ftx.Context(),
+		InsertInvitationQuery,
+		tokenHash,
+		invite.Email,
+		invite.Role,
+		nullableID(adminID),
+		ttl.Seconds()))
+	if err != nil {
+		ftx.Logger().Error("Could not create invitation", zap.Error(err))
+		return models.Invitation{}, errors.ErrDatabase
+	}
+
+	// Record the creation in the audit trail
+	_, err = tx.ExecContext(ftx.Context(), InsertInvitationEventQuery, inv.InvitationID, "created", nullableID(adminID), nil)
+	if err != nil {
+		ftx.Logger().Error("Could not record invitation event", zap.Error(err))
+		return models.Invitation{}, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		// Log and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return models.Invitation{}, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully created invitation",
+		zap.Int("InvitationID", inv.InvitationID),
+		zap.String("Role", inv.Role),
+	)
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return inv, nil
+}
+
+// AcceptInvitation creates the invited staff account and closes the invitation in one transaction.
+// Attempts with an unusable invitation are recorded as rejected.
+func (r *repo) AcceptInvitation(ftx factory.Service, tokenHash string, user models.User) (int, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for accepting invitation")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				// Log rollback failure
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Lock the invitation so it cannot be accepted twice
+	inv, err := scanInvitation(tx.QueryRowContext(ftx.Context(), GetInvitationForUpdateQuery, tokenHash))
+	if err == sql.ErrNoRows {
+		ftx.Logger().Info("Unknown invitation token presented")
+		return 0, errors.ErrInvitationInvalid
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve invitation", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	// Only pending invitations can be accepted, keep a record of the failed attempt
+	if inv.Status != "pending" {
+		_, err = tx.ExecContext(ftx.Context(), InsertInvitationEventQuery, inv.InvitationID, "rejected", nil, "invitation is "+inv.Status)
+		if err != nil {
+			ftx.Logger().Error("Could not record invitation event", zap.Error(err))
+			return 0, errors.ErrDatabase
+		}
+		if err = ftx.TransactionManager().Commit(tx); err != nil {
+			ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+			return 0, errors.ErrDatabase
+		}
+		ftx.Logger().Info("Rejected invitation acceptance", zap.Int("InvitationID", inv.InvitationID), zap.String("Status", inv.Status))
+		return 0, errors.ErrInvitationInvalid
+	}
+
+	// Create the account with the invited email and role
+	var userID int
+	err = tx.QueryRowContext(ftx.Context(),
+		RegisterUserQuery,
+		user.Username,
+		user.Name,
+		inv.Email,
+		user.Password,
+		inv.Role).Scan(&userID)
+	if err != nil {
+		ftx.Logger().Error("Could not register user", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	// The invitation was mailed to this address, so following it proves the address
+	if _, err = tx.ExecContext(ftx.Context(), MarkEmailVerifiedQuery, userID); err != nil {
+		ftx.Logger().Error("Could not verify email", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	// Close the invitation and record the acceptance
+	if _, err = tx.ExecContext(ftx.Context(), AcceptInvitationQuery, inv.InvitationID, userID); err != nil {
+		ftx.Logger().Error("Could not accept invitation", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+	if _, err = tx.ExecContext(ftx.Context(), InsertInvitationEventQuery, inv.InvitationID, "accepted", userID, nil); err != nil {
+		ftx.Logger().Error("Could not record invitation event", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		// Log and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully accepted invitation",
+		zap.Int("InvitationID", inv.InvitationID),
+		zap.Int("UserID", userID),
+		zap.String("Role", inv.Role),
+	)
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return userID, nil
+}
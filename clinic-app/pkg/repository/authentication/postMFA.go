@@ -0,0 +1,131 @@
+package authentication
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+
+	"go.uber.org/zap"
+)
+
+// SaveMFASecret stores a new, not yet enabled TOTP secret for a user
+func (r *repo) SaveMFASecret(ftx factory.Service, userID int, sealedSecret string) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for storing two-factor secret")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				// Log rollback failure
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Execute the upsert, no row comes back when two-factor authentication is already enabled
+	var id int
+	err = tx.QueryRowContext(ftx.Context(), SaveMFASecretQuery, userID, sealedSecret).Scan(&id)
+	if err == sql.ErrNoRows {
+		return errors.ErrMFAAlreadyEnabled
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not store two-factor secret", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		// Log and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully stored two-factor secret", zap.Int("UserID", userID))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
+
+// EnableMFA enables two-factor authentication with the step of the confirming code and stores the first recovery codes
+func (r *repo) EnableMFA(ftx factory.Service, userID int, step int64, codeHashes []string) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for enabling two-factor authentication")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				// Log rollback failure
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// The confirming code cannot be used again
+	var id int
+	err = tx.QueryRowContext(ftx.Context(), UseTOTPStepQuery, userID, step).Scan(&id)
+	if err == sql.ErrNoRows {
+		return errors.ErrInvalidMFACode
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not record two-factor code", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Enable, a concurrent activation may have won already
+	err = tx.QueryRowContext(ftx.Context(), EnableMFAQuery, userID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return errors.ErrMFAAlreadyEnabled
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not enable two-factor authentication", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	if err = replaceRecoveryCodes(ftx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		// Log and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully enabled two-factor authentication", zap.Int("UserID", userID))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
+
+// replaceRecoveryCodes swaps all recovery codes of a user for new ones within a transaction
+func replaceRecoveryCodes(ftx factory.Service, tx *sql.Tx, userID int, codeHashes []string) error {
+	if _, err := tx.ExecContext(ftx.Context(), DeleteRecoveryCodesQuery, userID); err != nil {
+		ftx.Logger().Error("Could not delete recovery codes", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	for _, hash := range codeHashes {
+		if _, err := tx.ExecContext(ftx.Context(), InsertRecoveryCodeQuery, userID, hash); err != nil {
+			ftx.Logger().Error("Could not store recovery code", zap.Error(err))
+			return errors.ErrDatabase
+		}
+	}
+	return nil
+}
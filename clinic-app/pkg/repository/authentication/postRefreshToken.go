@@ -0,0 +1,54 @@
+package authentication
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+
+	"go.uber.org/zap"
+)
+
+// StoreRefreshToken stores a new refresh token for a user
+func (r *repo) StoreRefreshToken(ftx factory.Service, userID int, token models.RefreshToken) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for storing refresh token")
+
+	// Execute the insert query within the transaction context
+	_, err = tx.ExecContext(ftx.Context(),
+		InsertRefreshTokenQuery,
+		token.Hash,
+		token.FamilyID,
+		userID,
+		token.AccessJTI,
+		token.AccessExpiresAt.Unix(),
+		token.TTL.Seconds())
+	if err != nil {
+		// Log error and rollback transaction if insert fails
+		ftx.Logger().Error("Could not store refresh token", zap.Error(err))
+		tx.Rollback() // Rollback transaction on error
+		return errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		// Log error and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Log success
+	ftx.Logger().Info("Successfully stored refresh token",
+		zap.Int("UserID", userID),
+		zap.String("Family", token.FamilyID),
+	)
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package authentication
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/services/factory"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// CreateUserToken stores a single-use token for a user, replacing the unused tokens they have for the same purpose
+func (r *repo) CreateUserToken(ftx factory.Service, userID int, purpose, tokenHash string, ttl time.Duration) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for creating user token")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				// Log rollback failure
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Only the latest link mailed for a purpose keeps working
+	if _, err = tx.ExecContext(ftx.Context(), DeleteUnusedUserTokensQuery, userID, purpose); err != nil {
+		ftx.Logger().Error("Could not delete previous user tokens", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Execute the insert query within the transaction context
+	if _, err = tx.ExecContext(ftx.Context(), InsertUserTokenQuery, tokenHash, userID, purpose, ttl.Seconds()); err != nil {
+		ftx.Logger().Error("Could not store user token", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		// Log and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully created user token",
+		zap.Int("UserID", userID),
+		zap.String("Purpose", purpose),
+	)
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
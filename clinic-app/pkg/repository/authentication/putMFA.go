@@ -0,0 +1,140 @@
+package authentication
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+
+	"go.uber.org/zap"
+)
+
+// UseTOTPStep records the time step of an accepted TOTP code, a step that was already used is rejected
+func (r *repo) UseTOTPStep(ftx factory.Service, userID int, step int64) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for using two-factor code")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				// Log rollback failure
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Execute the update, no row comes back for a replayed code
+	var id int
+	err = tx.QueryRowContext(ftx.Context(), UseTOTPStepQuery, userID, step).Scan(&id)
+	if err == sql.ErrNoRows {
+		ftx.Logger().Warn("Replayed two-factor code rejected", zap.Int("UserID", userID))
+		return errors.ErrInvalidMFACode
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not record two-factor code", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		// Log and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	middleware.GetTraceParentFromContext(ftx.Context())
+	return nil
+}
+
+// UseRecoveryCode uses up a recovery code of a user
+func (r *repo) UseRecoveryCode(ftx factory.Service, userID int, codeHash string) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for using recovery code")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				// Log rollback failure
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Execute the update, no row comes back for unknown or used codes
+	var codeID int
+	err = tx.QueryRowContext(ftx.Context(), UseRecoveryCodeQuery, userID, codeHash).Scan(&codeID)
+	if err == sql.ErrNoRows {
+		return errors.ErrInvalidMFACode
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not use recovery code", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		// Log and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Recovery code used", zap.Int("UserID", userID), zap.Int("CodeID", codeID))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
+
+// ReplaceRecoveryCodes swaps all recovery codes of a user for new ones
+func (r *repo) ReplaceRecoveryCodes(ftx factory.Service, userID int, codeHashes []string) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for replacing recovery codes")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				// Log rollback failure
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	if err = replaceRecoveryCodes(ftx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		// Log and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully replaced recovery codes", zap.Int("UserID", userID))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package authentication
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/services/factory"
+
+	"go.uber.org/zap"
+)
+
+// UpdatePassword replaces the stored password hash of a user
+func (r *repo) UpdatePassword(ftx factory.Service, userID int, passwordHash string) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for updating user password")
+
+	// Execute the update query within the transaction context
+	res, err := tx.ExecContext(ftx.Context(), UpdatePasswordQuery, userID, passwordHash)
+	if err != nil {
+		// Log error and rollback transaction if update fails
+		ftx.Logger().Error("Could not update password", zap.Error(err))
+		tx.Rollback() // Rollback transaction on error
+		return errors.ErrDatabase
+	}
+
+	// Make sure the user actually exists
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		tx.Rollback() // Rollback transaction as nothing was updated
+		return errors.ErrUserNotFound
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		// Log error and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Log success
+	ftx.Logger().Info("Successfully updated user password", zap.Int("UserID", userID))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package authentication
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+
+	"go.uber.org/zap"
+)
+
+// RotateRefreshToken exchanges a refresh token for the next token of the same family.
+// A token that was already rotated or revoked returns ErrTokenReused together with its family.
+func (r *repo) RotateRefreshToken(ftx factory.Service, oldHash string, next models.RefreshToken) (models.RefreshSession, error) {
+	var session models.RefreshSession
+
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return session, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for rotating refresh token")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				// Log rollback failure
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Lock the presented token so concurrent refreshes cannot both rotate it
+	var tokenID int
+	var used, expired bool
+	err = tx.QueryRowContext(ftx.Context(), GetRefreshTokenForUpdateQuery, oldHash).Scan(
+		&tokenID,
+		&session.FamilyID,
+		&session.UserID,
+		&session.Role,
+		&used,
+		&expired,
+	)
+	if err == sql.ErrNoRows {
+		ftx.Logger().Info("Unknown refresh token presented")
+		return session, errors.ErrInvalidToken
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve refresh token", zap.Error(err))
+		return session, errors.ErrDatabase
+	}
+
+	// A token that was already exchanged is being replayed
+	if used {
+		err = errors.ErrTokenReused
+		ftx.Logger().Warn("Refresh token reuse detected", zap.String("Family", session.FamilyID))
+		return session, err
+	}
+	if expired {
+		err = errors.ErrInvalidToken
+		return session, err
+	}
+
+	// Retire the presented token
+	if _, err = tx.ExecContext(ftx.Context(), RotateRefreshTokenQuery, tokenID); err != nil {
+		ftx.Logger().Error("Could not rotate refresh token", zap.Error(err))
+		return session, errors.ErrDatabase
+	}
+
+	// Store its successor in the same family
+	_, err = tx.ExecContext(ftx.Context(),
+		InsertRefreshTokenQuery,
+		next.Hash,
+		session.FamilyID,
+		session.UserID,
+		next.AccessJTI,
+		next.AccessExpiresAt.Unix(),
+		next.TTL.Seconds())
+	if err != nil {
+		ftx.Logger().Error("Could not store refresh token", zap.Error(err))
+		return session, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		// Log and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return session, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully rotated refresh token",
+		zap.Int("UserID", session.UserID),
+		zap.String("Family", session.FamilyID),
+	)
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return session, nil
+}
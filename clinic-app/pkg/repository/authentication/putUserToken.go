@@ -0,0 +1,150 @@
+package authentication
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// VerifyEmail uses up an email verification token and marks the address of its user as verified
+func (r *repo) VerifyEmail(ftx factory.Service, tokenHash string) (int, error) {
+	var userID int
+
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for verifying email")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				// Log rollback failure
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Use up the token
+	err = tx.QueryRowContext(ftx.Context(), UseUserTokenQuery, tokenHash, models.TokenPurposeVerifyEmail).Scan(&userID)
+	if err == sql.ErrNoRows {
+		ftx.Logger().Info("Unusable email verification token presented")
+		return 0, errors.ErrLinkInvalid
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not use email verification token", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	// Mark the address as verified
+	if _, err = tx.ExecContext(ftx.Context(), MarkEmailVerifiedQuery, userID); err != nil {
+		ftx.Logger().Error("Could not verify email", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		// Log and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully verified email", zap.Int("UserID", userID))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return userID, nil
+}
+
+// ResetPassword uses up a password reset token, replaces the password of its user and revokes all of their sessions.
+// The access tokens that were revoked and have not expired yet are returned.
+func (r *repo) ResetPassword(ftx factory.Service, tokenHash, passwordHash string) (int, []models.RevokedToken, error) {
+	var userID int
+	var revoked []models.RevokedToken
+
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return 0, nil, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for resetting password")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				// Log rollback failure
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Use up the token
+	err = tx.QueryRowContext(ftx.Context(), UseUserTokenQuery, tokenHash, models.TokenPurposeResetPassword).Scan(&userID)
+	if err == sql.ErrNoRows {
+		ftx.Logger().Info("Unusable password reset token presented")
+		return 0, nil, errors.ErrLinkInvalid
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not use password reset token", zap.Error(err))
+		return 0, nil, errors.ErrDatabase
+	}
+
+	// Replace the password
+	if _, err = tx.ExecContext(ftx.Context(), UpdatePasswordQuery, userID, passwordHash); err != nil {
+		ftx.Logger().Error("Could not update password", zap.Error(err))
+		return 0, nil, errors.ErrDatabase
+	}
+
+	// The reset link was mailed to the address, so following it proves the address too
+	if _, err = tx.ExecContext(ftx.Context(), MarkEmailVerifiedQuery, userID); err != nil {
+		ftx.Logger().Error("Could not verify email", zap.Error(err))
+		return 0, nil, errors.ErrDatabase
+	}
+
+	// Whoever knew the old password must not stay logged in
+	rows, err := tx.QueryContext(ftx.Context(), RevokeUserTokensQuery, userID)
+	if err != nil {
+		ftx.Logger().Error("Could not revoke user tokens", zap.Error(err))
+		return 0, nil, errors.ErrDatabase
+	}
+	defer rows.Close()
+
+	// Collect the access tokens that are now revoked
+	for rows.Next() {
+		var token models.RevokedToken
+		var expiresAt int64
+		if err = rows.Scan(&token.JTI, &expiresAt); err != nil {
+			return 0, nil, errors.ErrDatabase
+		}
+		token.ExpiresAt = time.Unix(expiresAt, 0)
+		revoked = append(revoked, token)
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		// Log and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return 0, nil, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully reset password",
+		zap.Int("UserID", userID),
+		zap.Int("Access tokens", len(revoked)),
+	)
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return userID, revoked, nil
+}
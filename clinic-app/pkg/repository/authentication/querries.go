@@ -12,14 +12,338 @@ const (
 		VALUES ($1, $2, $3, $4, $5)
 		RETURNING user_id;
 	`
-	// Login
-	LoginUserQuery = `
+	// Login, the password hash is verified by the usecase
+	GetUserByUsernameQuery = `
 		SELECT 
 			user_id, 
 			username, 
 			password, 
 			role
 		FROM Users
-		WHERE username = $1 and password = $2;
+		WHERE username = $1;
+	`
+
+	// Replace a user's password hash
+	UpdatePasswordQuery = `
+		UPDATE Users
+		SET password = $2
+		WHERE user_id = $1;
+	`
+
+	// Store a new refresh token
+	InsertRefreshTokenQuery = `
+		INSERT INTO RefreshTokens (
+			token_hash,
+			family_id,
+			user_id,
+			access_jti,
+			access_expires_at,
+			expires_at)
+		VALUES ($1, $2, $3, $4, to_timestamp($5), NOW() + make_interval(secs => $6));
+	`
+
+	// Lock a refresh token for rotation
+	GetRefreshTokenForUpdateQuery = `
+		SELECT 
+			RefreshTokens.token_id,
+			RefreshTokens.family_id,
+			RefreshTokens.user_id,
+			Users.role,
+			RefreshTokens.rotated_at IS NOT NULL OR RefreshTokens.revoked_at IS NOT NULL AS used,
+			RefreshTokens.expires_at <= NOW() AS expired
+		FROM RefreshTokens
+		INNER JOIN Users ON RefreshTokens.user_id = Users.user_id
+		WHERE RefreshTokens.token_hash = $1
+		FOR UPDATE OF RefreshTokens;
+	`
+
+	// Mark a refresh token as rotated
+	RotateRefreshTokenQuery = `
+		UPDATE RefreshTokens
+		SET rotated_at = NOW()
+		WHERE token_id = $1;
+	`
+
+	// Find the family of a refresh token
+	GetRefreshTokenFamilyQuery = `
+		SELECT family_id
+		FROM RefreshTokens
+		WHERE token_hash = $1;
+	`
+
+	// Revoke every refresh token of a family, block the access tokens issued with them and return those still unexpired
+	RevokeTokenFamilyQuery = `
+		WITH revoked AS (
+			UPDATE RefreshTokens
+			SET revoked_at = COALESCE(revoked_at, NOW())
+			WHERE family_id = $1
+			RETURNING access_jti, access_expires_at
+		),
+		blocked AS (
+			INSERT INTO RevokedTokens (jti, expires_at)
+			SELECT access_jti, access_expires_at
+			FROM revoked
+			WHERE access_expires_at > NOW()
+			ON CONFLICT (jti) DO NOTHING
+		)
+		SELECT 
+			access_jti,
+			EXTRACT(EPOCH FROM access_expires_at::TIMESTAMPTZ)::BIGINT
+		FROM revoked
+		WHERE access_expires_at > NOW();
+	`
+
+	// Revoke an access token
+	RevokeAccessTokenQuery = `
+		INSERT INTO RevokedTokens (jti, expires_at)
+		VALUES ($1, to_timestamp($2))
+		ON CONFLICT (jti) DO NOTHING;
+	`
+
+	// Check whether an access token was revoked
+	IsAccessTokenRevokedQuery = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM RevokedTokens
+			WHERE jti = $1
+		);
+	`
+
+	// Create a staff invitation
+	InsertInvitationQuery = `
+		INSERT INTO Invitations (
+			token_hash,
+			email,
+			role,
+			invited_by,
+			expires_at)
+		VALUES ($1, $2, $3, $4, NOW() + make_interval(secs => $5))
+		RETURNING ` + invitationColumns + `;
+	`
+
+	// List all invitations, newest first
+	GetInvitationsQuery = `
+		SELECT ` + invitationColumns + `
+		FROM Invitations
+		ORDER BY created_at DESC;
+	`
+
+	// Lock an invitation for acceptance
+	GetInvitationForUpdateQuery = `
+		SELECT ` + invitationColumns + `
+		FROM Invitations
+		WHERE token_hash = $1
+		FOR UPDATE;
+	`
+
+	// Mark an invitation as accepted
+	AcceptInvitationQuery = `
+		UPDATE Invitations
+		SET accepted_at = NOW(),
+			accepted_user_id = $2
+		WHERE invitation_id = $1;
+	`
+
+	// Revoke an invitation that is still open
+	RevokeInvitationQuery = `
+		UPDATE Invitations
+		SET revoked_at = NOW()
+		WHERE invitation_id = $1
+		AND accepted_at IS NULL
+		AND revoked_at IS NULL
+		RETURNING invitation_id;
+	`
+
+	// Record an invitation event
+	InsertInvitationEventQuery = `
+		INSERT INTO InvitationEvents (
+			invitation_id,
+			event,
+			actor_id,
+			details)
+		VALUES ($1, $2, $3, $4);
+	`
+
+	// View the audit trail of an invitation
+	GetInvitationEventsQuery = `
+		SELECT 
+			event_id,
+			invitation_id,
+			event,
+			actor_id,
+			details,
+			created_at
+		FROM InvitationEvents
+		WHERE invitation_id = $1
+		ORDER BY created_at, event_id;
+	`
+	// Retrieve a user's account details
+	GetUserByIDQuery = `
+		SELECT 
+			user_id,
+			username,
+			name,
+			email,
+			role,
+			email_verified_at IS NOT NULL
+		FROM Users
+		WHERE user_id = $1;
+	`
+
+	// Retrieve every account registered with an email address
+	GetUsersByEmailQuery = `
+		SELECT 
+			user_id,
+			username,
+			name,
+			email,
+			role,
+			email_verified_at IS NOT NULL
+		FROM Users
+		WHERE LOWER(email) = LOWER($1);
+	`
+
+	// Mark a user's email address as verified
+	MarkEmailVerifiedQuery = `
+		UPDATE Users
+		SET email_verified_at = COALESCE(email_verified_at, NOW())
+		WHERE user_id = $1;
+	`
+
+	// Drop the unused tokens a new token replaces
+	DeleteUnusedUserTokensQuery = `
+		DELETE FROM UserTokens
+		WHERE user_id = $1
+		AND purpose = $2
+		AND used_at IS NULL;
+	`
+
+	// Store a new single-use token
+	InsertUserTokenQuery = `
+		INSERT INTO UserTokens (
+			token_hash,
+			user_id,
+			purpose,
+			expires_at)
+		VALUES ($1, $2, $3, NOW() + make_interval(secs => $4));
+	`
+
+	// Use up a single-use token, nothing is returned if it is unknown, expired or already used
+	UseUserTokenQuery = `
+		UPDATE UserTokens
+		SET used_at = NOW()
+		WHERE token_hash = $1
+		AND purpose = $2
+		AND used_at IS NULL
+		AND expires_at > NOW()
+		RETURNING user_id;
+	`
+
+	// Revoke every refresh token of a user, block the access tokens issued with them and return those still unexpired
+	RevokeUserTokensQuery = `
+		WITH revoked AS (
+			UPDATE RefreshTokens
+			SET revoked_at = COALESCE(revoked_at, NOW())
+			WHERE user_id = $1
+			RETURNING access_jti, access_expires_at
+		),
+		blocked AS (
+			INSERT INTO RevokedTokens (jti, expires_at)
+			SELECT access_jti, access_expires_at
+			FROM revoked
+			WHERE access_expires_at > NOW()
+			ON CONFLICT (jti) DO NOTHING
+		)
+		SELECT 
+			access_jti,
+			EXTRACT(EPOCH FROM access_expires_at::TIMESTAMPTZ)::BIGINT
+		FROM revoked
+		WHERE access_expires_at > NOW();
+	`
+	// Retrieve a user's TOTP configuration
+	GetMFAQuery = `
+		SELECT 
+			user_id,
+			secret,
+			enabled_at IS NOT NULL,
+			last_used_step
+		FROM UserMFA
+		WHERE user_id = $1;
+	`
+
+	// Store a new TOTP secret, an enabled configuration is never overwritten
+	SaveMFASecretQuery = `
+		INSERT INTO UserMFA (user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret,
+			last_used_step = 0,
+			created_at = NOW()
+		WHERE UserMFA.enabled_at IS NULL
+		RETURNING user_id;
+	`
+
+	// Record the time step of an accepted code, nothing is returned when the step was already used
+	UseTOTPStepQuery = `
+		UPDATE UserMFA
+		SET last_used_step = $2
+		WHERE user_id = $1
+		AND last_used_step < $2
+		RETURNING user_id;
+	`
+
+	// Enable two-factor authentication once the first code was confirmed
+	EnableMFAQuery = `
+		UPDATE UserMFA
+		SET enabled_at = NOW()
+		WHERE user_id = $1
+		AND enabled_at IS NULL
+		RETURNING user_id;
+	`
+
+	// Remove a user's TOTP configuration
+	DeleteMFAQuery = `
+		DELETE FROM UserMFA
+		WHERE user_id = $1;
+	`
+
+	// Remove all recovery codes of a user
+	DeleteRecoveryCodesQuery = `
+		DELETE FROM RecoveryCodes
+		WHERE user_id = $1;
+	`
+
+	// Store a recovery code
+	InsertRecoveryCodeQuery = `
+		INSERT INTO RecoveryCodes (user_id, code_hash)
+		VALUES ($1, $2);
+	`
+
+	// Use up a recovery code, nothing is returned if it is unknown or already used
+	UseRecoveryCodeQuery = `
+		UPDATE RecoveryCodes
+		SET used_at = NOW()
+		WHERE user_id = $1
+		AND code_hash = $2
+		AND used_at IS NULL
+		RETURNING code_id;
 	`
 )
+
+// invitationColumns selects an invitation together with its derived status
+const invitationColumns = `
+			invitation_id,
+			email,
+			role,
+			invited_by,
+			CASE
+				WHEN accepted_at IS NOT NULL THEN 'accepted'
+				WHEN revoked_at IS NOT NULL THEN 'revoked'
+				WHEN expires_at <= NOW() THEN 'expired'
+				ELSE 'pending'
+			END AS status,
+			expires_at,
+			accepted_at,
+			accepted_user_id,
+			revoked_at,
+			created_at`
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+)
+
+// AuthorizationRepository answers the ownership questions asked while authorizing a request
+type AuthorizationRepository interface {
+	GetAppointmentParties(ftx factory.Service, appointmentId int) (models.AppointmentParties, error)
+	HasTreatedPatient(ftx factory.Service, doctorId, patientId int) (bool, error)
+	GetTimeOffDoctor(ftx factory.Service, timeOffId int) (int, error)
+	GetWaitlistEntryPatient(ftx factory.Service, entryId int) (int, error)
+	GetWaitlistOfferPatient(ftx factory.Service, offerId int) (int, error)
+	GetHoldPatient(ftx factory.Service, holdId int) (int, error)
+}
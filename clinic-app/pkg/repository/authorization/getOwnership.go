@@ -0,0 +1,99 @@
+package authorization
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+
+	"go.uber.org/zap"
+)
+
+// GetAppointmentParties retrieves the patient and doctor of an appointment
+func (r *repo) GetAppointmentParties(ftx factory.Service, appointmentId int) (models.AppointmentParties, error) {
+	var parties models.AppointmentParties
+	err := readOwnership(ftx, "Could not retrieve appointment parties", GetAppointmentPartiesQuery, []any{appointmentId},
+		&parties.AppointmentID,
+		&parties.PatientID,
+		&parties.DoctorID,
+		&parties.Upcoming,
+	)
+	return parties, err
+}
+
+// HasTreatedPatient reports whether a doctor has seen a patient. Only appointments that have started count,
+// booking an appointment does not let a doctor read the history of the patient.
+func (r *repo) HasTreatedPatient(ftx factory.Service, doctorId, patientId int) (bool, error) {
+	var treated bool
+	err := readOwnership(ftx, "Could not check doctor patient relationship", HasTreatedPatientQuery, []any{doctorId, patientId}, &treated)
+	return treated, err
+}
+
+// GetTimeOffDoctor retrieves the doctor a time off belongs to
+func (r *repo) GetTimeOffDoctor(ftx factory.Service, timeOffId int) (int, error) {
+	var doctorId int
+	err := readOwnership(ftx, "Could not retrieve time off doctor", GetTimeOffDoctorQuery, []any{timeOffId}, &doctorId)
+	return doctorId, err
+}
+
+// GetWaitlistEntryPatient retrieves the patient a waitlist entry belongs to
+func (r *repo) GetWaitlistEntryPatient(ftx factory.Service, entryId int) (int, error) {
+	var patientId int
+	err := readOwnership(ftx, "Could not retrieve waitlist entry patient", GetWaitlistEntryPatientQuery, []any{entryId}, &patientId)
+	return patientId, err
+}
+
+// GetWaitlistOfferPatient retrieves the patient a waitlist offer was made to
+func (r *repo) GetWaitlistOfferPatient(ftx factory.Service, offerId int) (int, error) {
+	var patientId int
+	err := readOwnership(ftx, "Could not retrieve waitlist offer patient", GetWaitlistOfferPatientQuery, []any{offerId}, &patientId)
+	return patientId, err
+}
+
+// GetHoldPatient retrieves the patient a time is held for
+func (r *repo) GetHoldPatient(ftx factory.Service, holdId int) (int, error) {
+	var patientId int
+	err := readOwnership(ftx, "Could not retrieve hold patient", GetHoldPatientQuery, []any{holdId}, &patientId)
+	return patientId, err
+}
+
+// readOwnership reads the single row of an ownership query in a transaction.
+// It returns ErrNotFound when the row does not exist.
+func readOwnership(ftx factory.Service, failure string, query string, args []any, dest ...any) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for checking ownership")
+
+	// Defer a rollback in case anything fails
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	err = tx.QueryRowContext(ftx.Context(), query, args...).Scan(dest...)
+	if err == sql.ErrNoRows {
+		return errors.ErrNotFound
+	}
+	if err != nil {
+		ftx.Logger().Error(failure, zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	middleware.GetTraceParentFromContext(ftx.Context())
+	return nil
+}
@@ -0,0 +1,50 @@
+package authorization
+
+const (
+	// Get the patient and doctor of an appointment
+	GetAppointmentPartiesQuery = `
+		SELECT appointment_id, patient_id, doctor_id, start_time > NOW()
+		FROM Appointment
+		WHERE appointment_id = $1;
+	`
+
+	// Check whether a doctor has seen a patient, in an appointment that has at least started
+	HasTreatedPatientQuery = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM Appointment
+			WHERE doctor_id = $1
+			AND patient_id = $2
+			AND status IN ('checked_in', 'in_progress', 'completed')
+		);
+	`
+
+	// Get the doctor a time off belongs to
+	GetTimeOffDoctorQuery = `
+		SELECT doctor_id
+		FROM TimeOff
+		WHERE time_off_id = $1;
+	`
+
+	// Get the patient a waitlist entry belongs to
+	GetWaitlistEntryPatientQuery = `
+		SELECT patient_id
+		FROM WaitlistEntries
+		WHERE waitlist_entry_id = $1;
+	`
+
+	// Get the patient a waitlist offer was made to
+	GetWaitlistOfferPatientQuery = `
+		SELECT e.patient_id
+		FROM WaitlistOffers o
+		INNER JOIN WaitlistEntries e ON e.waitlist_entry_id = o.waitlist_entry_id
+		WHERE o.waitlist_offer_id = $1;
+	`
+
+	// Get the patient a time is held for
+	GetHoldPatientQuery = `
+		SELECT patient_id
+		FROM AppointmentHolds
+		WHERE hold_id = $1;
+	`
+)
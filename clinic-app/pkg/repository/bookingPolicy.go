@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+)
+
+// BookingPolicyRepository defines methods for managing the booking rules of the clinic and its doctors
+type BookingPolicyRepository interface {
+	GetDefaultPolicy(ftx factory.Service) (models.BookingPolicy, error)
+	GetDoctorPolicies(ftx factory.Service) ([]models.BookingPolicy, error)
+	GetEffectivePolicy(ftx factory.Service, doctorId int) (models.BookingPolicy, error)
+	SaveDefaultPolicy(ftx factory.Service, policy models.BookingPolicy, horizonDays int) error
+	SaveDoctorPolicy(ftx factory.Service, policy models.BookingPolicy, horizonDays int) error
+	DeleteDoctorPolicy(ftx factory.Service, doctorId int, horizonDays int) error
+}
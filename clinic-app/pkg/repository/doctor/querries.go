@@ -1,31 +1,41 @@
 package doctor
 
 const (
-	// View all doctors
+	// View all doctors who have an upcoming day with capacity left
 	GetAllDoctorsQuery = `
 		SELECT 
 			Users.user_id AS doctor_id, 
 			Users.name, 
 			Users.email,
-			Schedules.availability
+			'available' AS availability
 		FROM Users
-		INNER JOIN Schedules ON Users.user_id = Schedules.doctor_id
 		WHERE Users.role = 'doctor'
-		AND Schedules.availability = 'available';
+		AND EXISTS (
+			SELECT 1
+			FROM Schedules
+			WHERE Schedules.doctor_id = Users.user_id
+			AND Schedules.date >= CURRENT_DATE
+			AND Schedules.availability = 'available'
+		);
 	`
 
-	// View specific doctor information
+	// View specific doctor information, if they have an upcoming day with capacity left
 	GetDoctorByIdQuery = `
 		SELECT 
 			Users.user_id AS doctor_id, 
 			Users.name, 
 			Users.email,
-			Schedules.availability
+			'available' AS availability
 		FROM Users
-		INNER JOIN Schedules ON Users.user_id = Schedules.doctor_id
 		WHERE user_id = $1 
 		AND role = 'doctor'
-		AND Schedules.availability = 'available';
+		AND EXISTS (
+			SELECT 1
+			FROM Schedules
+			WHERE Schedules.doctor_id = Users.user_id
+			AND Schedules.date >= CURRENT_DATE
+			AND Schedules.availability = 'available'
+		);
 	`
 	// View available time slots by doctor
 	GetSlotsByDoctorQuery = `
@@ -39,7 +49,7 @@ const (
 			s.is_booked,
 			s.duration
 		FROM Slot s
-		LEFT JOIN Appointment a ON s.doctor_id = a.doctor_id AND s.start_time = a.start_time
+		LEFT JOIN Appointment a ON s.doctor_id = a.doctor_id AND s.start_time = a.start_time AND a.status <> 'canceled'
 		LEFT JOIN Users p ON a.patient_id = p.user_id
 		WHERE s.doctor_id = $1
 	`
@@ -54,7 +64,7 @@ const (
 			s.is_booked,
 			s.duration
 		FROM Slot s
-		LEFT JOIN Appointment a ON s.doctor_id = a.doctor_id AND s.start_time = a.start_time
+		LEFT JOIN Appointment a ON s.doctor_id = a.doctor_id AND s.start_time = a.start_time AND a.status <> 'canceled'
 		LEFT JOIN Users p ON a.patient_id = p.user_id
 		WHERE s.doctor_id = $1;
 	`
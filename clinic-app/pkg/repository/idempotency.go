@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"time"
+)
+
+// IdempotencyRepository defines methods for storing the responses of idempotent requests
+type IdempotencyRepository interface {
+	ClaimKey(ftx factory.Service, request models.IdempotentRequest, ttl, staleAfter time.Duration) (bool, error)
+	GetResponse(ftx factory.Service, request models.IdempotentRequest) (models.IdempotentResponse, error)
+	SaveResponse(ftx factory.Service, request models.IdempotentRequest, response models.IdempotentResponse) error
+	ReleaseKey(ftx factory.Service, request models.IdempotentRequest) error
+	DeleteExpiredKeys(ftx factory.Service) (int64, error)
+}
@@ -0,0 +1,98 @@
+package idempotency
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+
+	"go.uber.org/zap"
+)
+
+// ReleaseKey gives up a key whose request produced no response worth replaying
+func (r *repo) ReleaseKey(ftx factory.Service, request models.IdempotentRequest) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for releasing idempotency key")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				// Log rollback failure
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Execute the query to release the key
+	_, err = tx.ExecContext(ftx.Context(), ReleaseKeyQuery, request.UserID, request.Key, request.RequestHash)
+	if err != nil {
+		ftx.Logger().Error("Could not release idempotency key", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		// Log and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully released idempotency key", zap.Int("UserID", request.UserID))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
+
+// DeleteExpiredKeys removes expired keys and returns how many were removed
+func (r *repo) DeleteExpiredKeys(ftx factory.Service) (int64, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for deleting expired idempotency keys")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				// Log rollback failure
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Execute the query to delete the expired keys
+	result, err := tx.ExecContext(ftx.Context(), DeleteExpiredKeysQuery)
+	if err != nil {
+		ftx.Logger().Error("Could not delete expired idempotency keys", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		// Log and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully deleted expired idempotency keys", zap.Int64("Deleted", deleted))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return deleted, nil
+}
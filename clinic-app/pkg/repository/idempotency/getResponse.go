@@ -0,0 +1,63 @@
+package idempotency
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+
+	"go.uber.org/zap"
+)
+
+// GetResponse retrieves the request and response stored for a key
+func (r *repo) GetResponse(ftx factory.Service, request models.IdempotentRequest) (models.IdempotentResponse, error) {
+	var response models.IdempotentResponse
+
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return response, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for retrieving idempotent response")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				// Log rollback failure
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Execute the query to retrieve the stored request and response
+	err = tx.QueryRowContext(ftx.Context(), GetResponseQuery, request.UserID, request.Key).Scan(
+		&response.RequestHash,
+		&response.StatusCode,
+		&response.ContentType,
+		&response.Body,
+	)
+	if err == sql.ErrNoRows {
+		return response, errors.ErrNotFound
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve idempotent response", zap.Error(err))
+		return response, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		// Log and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return response, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully retrieved idempotent response", zap.Int("UserID", request.UserID))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return response, nil
+}
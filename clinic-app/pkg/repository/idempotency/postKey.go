@@ -0,0 +1,65 @@
+package idempotency
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ClaimKey reserves a key for a request, it reports false when another request holds the key
+func (r *repo) ClaimKey(ftx factory.Service, request models.IdempotentRequest, ttl, staleAfter time.Duration) (bool, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return false, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for claiming idempotency key")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				// Log rollback failure
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Execute the query to claim the key, no row comes back when another request holds it
+	var userId int
+	err = tx.QueryRowContext(ftx.Context(), ClaimKeyQuery,
+		request.UserID,
+		request.Key,
+		request.RequestHash,
+		ttl.Seconds(),
+		staleAfter.Seconds(),
+	).Scan(&userId)
+	claimed := err == nil
+	if err == sql.ErrNoRows {
+		err = nil
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not claim idempotency key", zap.Error(err))
+		return false, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		// Log and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return false, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Checked idempotency key", zap.Int("UserID", request.UserID), zap.Bool("Claimed", claimed))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return claimed, nil
+}
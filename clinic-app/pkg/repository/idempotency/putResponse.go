@@ -0,0 +1,59 @@
+package idempotency
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+
+	"go.uber.org/zap"
+)
+
+// SaveResponse stores the response of the request that claimed a key
+func (r *repo) SaveResponse(ftx factory.Service, request models.IdempotentRequest, response models.IdempotentResponse) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		// Log and return error if transaction start fails
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for saving idempotent response")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				// Log rollback failure
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Execute the query to store the response
+	_, err = tx.ExecContext(ftx.Context(), SaveResponseQuery,
+		request.UserID,
+		request.Key,
+		request.RequestHash,
+		response.StatusCode,
+		response.ContentType,
+		response.Body,
+	)
+	if err != nil {
+		ftx.Logger().Error("Could not save idempotent response", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err = ftx.TransactionManager().Commit(tx); err != nil {
+		// Log and return error if commit fails
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully saved idempotent response", zap.Int("UserID", request.UserID), zap.Int("Status", response.StatusCode))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package idempotency
+
+const (
+	// Claim a key for a request. An expired key, or one whose request stopped being processed
+	// more than $5 seconds ago without a response, is taken over. Returns no row when the key is held.
+	ClaimKeyQuery = `
+		INSERT INTO IdempotencyKeys (user_id, idempotency_key, request_hash, expires_at)
+		VALUES ($1, $2, $3, NOW() + make_interval(secs => $4))
+		ON CONFLICT (user_id, idempotency_key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash,
+			status_code = NULL,
+			content_type = NULL,
+			response_body = NULL,
+			created_at = NOW(),
+			expires_at = EXCLUDED.expires_at
+		WHERE IdempotencyKeys.expires_at <= NOW()
+		OR (IdempotencyKeys.status_code IS NULL AND IdempotencyKeys.created_at < NOW() - make_interval(secs => $5))
+		RETURNING user_id;
+	`
+
+	// View the request and response stored for a key
+	GetResponseQuery = `
+		SELECT request_hash, COALESCE(status_code, 0), COALESCE(content_type, ''), COALESCE(response_body, '')
+		FROM IdempotencyKeys
+		WHERE user_id = $1
+		AND idempotency_key = $2;
+	`
+
+	// Store the response of the request that claimed a key
+	SaveResponseQuery = `
+		UPDATE IdempotencyKeys
+		SET status_code = $4,
+			content_type = $5,
+			response_body = $6
+		WHERE user_id = $1
+		AND idempotency_key = $2
+		AND request_hash = $3;
+	`
+
+	// Give up a key, so a retry runs the request again
+	ReleaseKeyQuery = `
+		DELETE FROM IdempotencyKeys
+		WHERE user_id = $1
+		AND idempotency_key = $2
+		AND request_hash = $3
+		AND status_code IS NULL;
+	`
+
+	// Remove expired keys
+	DeleteExpiredKeysQuery = `
+		DELETE FROM IdempotencyKeys
+		WHERE expires_at <= NOW();
+	`
+)
@@ -0,0 +1,173 @@
+package policy
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+
+	"go.uber.org/zap"
+)
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanPolicy reads a policy in the column order of the policy queries
+func scanPolicy(row rowScanner) (models.BookingPolicy, error) {
+	var policy models.BookingPolicy
+	err := row.Scan(
+		&policy.DoctorID,
+		&policy.MaxDailyAppointments,
+		&policy.MaxDailyMinutes,
+		&policy.MinDurationMinutes,
+		&policy.MaxDurationMinutes,
+		&policy.BufferMinutes,
+		&policy.BookingHorizonDays,
+		&policy.UpdatedBy,
+		&policy.UpdatedAt,
+	)
+	return policy, err
+}
+
+// GetDefaultPolicy retrieves the clinic-wide default policy
+func (r *repo) GetDefaultPolicy(ftx factory.Service) (models.BookingPolicy, error) {
+	var policy models.BookingPolicy
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return policy, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for retrieving the default booking policy")
+
+	// Defer a rollback in case anything fails
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	policy, err = scanPolicy(tx.QueryRowContext(ftx.Context(), GetDefaultPolicyQuery))
+	if err == sql.ErrNoRows {
+		ftx.Logger().Error("Default booking policy is missing")
+		return policy, errors.ErrNotFound
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve default booking policy", zap.Error(err))
+		return policy, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return policy, errors.ErrDatabase
+	}
+
+	middleware.GetTraceParentFromContext(ftx.Context())
+	return policy, nil
+}
+
+// GetDoctorPolicies retrieves the policies of all doctors that override the default
+func (r *repo) GetDoctorPolicies(ftx factory.Service) ([]models.BookingPolicy, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for retrieving doctor booking policies")
+
+	// Defer a rollback in case anything fails
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	rows, err := tx.QueryContext(ftx.Context(), GetDoctorPoliciesQuery)
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve doctor booking policies", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	defer rows.Close()
+
+	policies := []models.BookingPolicy{}
+	for rows.Next() {
+		var policy models.BookingPolicy
+		if policy, err = scanPolicy(rows); err != nil {
+			ftx.Logger().Error("Error scanning booking policy row", zap.Error(err))
+			return nil, errors.ErrDatabase
+		}
+		policies = append(policies, policy)
+	}
+	if err = rows.Err(); err != nil {
+		ftx.Logger().Error("Could not retrieve doctor booking policies", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	middleware.GetTraceParentFromContext(ftx.Context())
+	return policies, nil
+}
+
+// GetEffectivePolicy retrieves the rules that apply to a doctor
+func (r *repo) GetEffectivePolicy(ftx factory.Service, doctorId int) (models.BookingPolicy, error) {
+	policy := models.BookingPolicy{DoctorID: &doctorId}
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return policy, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for retrieving the effective booking policy")
+
+	// Defer a rollback in case anything fails
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	err = tx.QueryRowContext(ftx.Context(), GetEffectivePolicyQuery, doctorId).Scan(
+		&policy.MaxDailyAppointments,
+		&policy.MaxDailyMinutes,
+		&policy.MinDurationMinutes,
+		&policy.MaxDurationMinutes,
+		&policy.BufferMinutes,
+		&policy.BookingHorizonDays,
+	)
+	if err == sql.ErrNoRows {
+		ftx.Logger().Error("Default booking policy is missing")
+		return policy, errors.ErrNotFound
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve effective booking policy", zap.Error(err))
+		return policy, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return policy, errors.ErrDatabase
+	}
+
+	middleware.GetTraceParentFromContext(ftx.Context())
+	return policy, nil
+}
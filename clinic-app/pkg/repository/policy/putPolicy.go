@@ -0,0 +1,132 @@
+package policy
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+
+	"go.uber.org/zap"
+)
+
+// SaveDefaultPolicy changes the clinic-wide default policy and applies it to the schedules up to horizonDays from today
+func (r *repo) SaveDefaultPolicy(ftx factory.Service, policy models.BookingPolicy, horizonDays int) error {
+	return r.inTransaction(ftx, "changing the default booking policy", func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ftx.Context(), UpdateDefaultPolicyQuery,
+			policy.MaxDailyAppointments,
+			policy.MaxDailyMinutes,
+			policy.MinDurationMinutes,
+			policy.MaxDurationMinutes,
+			policy.BufferMinutes,
+			policy.BookingHorizonDays,
+			ftx.Principal().UserID,
+		)
+		if err != nil {
+			ftx.Logger().Error("Could not update default booking policy", zap.Error(err))
+			return errors.ErrDatabase
+		}
+
+		_, err = tx.ExecContext(ftx.Context(), MaterialiseAllSchedulesQuery, horizonDays)
+		if err != nil {
+			ftx.Logger().Error("Could not materialise schedules", zap.Error(err))
+			return errors.ErrDatabase
+		}
+		return nil
+	})
+}
+
+// SaveDoctorPolicy sets the policy of a doctor and applies it to their schedules up to horizonDays from today
+func (r *repo) SaveDoctorPolicy(ftx factory.Service, policy models.BookingPolicy, horizonDays int) error {
+	return r.inTransaction(ftx, "setting a doctor booking policy", func(tx *sql.Tx) error {
+		// Only doctors have a policy of their own
+		var isDoctor bool
+		err := tx.QueryRowContext(ftx.Context(), IsDoctorQuery, *policy.DoctorID).Scan(&isDoctor)
+		if err != nil {
+			ftx.Logger().Error("Could not check doctor", zap.Error(err))
+			return errors.ErrDatabase
+		}
+		if !isDoctor {
+			return errors.ErrNotFound
+		}
+
+		_, err = tx.ExecContext(ftx.Context(), UpsertDoctorPolicyQuery,
+			*policy.DoctorID,
+			policy.MaxDailyAppointments,
+			policy.MaxDailyMinutes,
+			policy.MinDurationMinutes,
+			policy.MaxDurationMinutes,
+			policy.BufferMinutes,
+			policy.BookingHorizonDays,
+			ftx.Principal().UserID,
+		)
+		if err != nil {
+			ftx.Logger().Error("Could not save doctor booking policy", zap.Error(err))
+			return errors.ErrDatabase
+		}
+
+		_, err = tx.ExecContext(ftx.Context(), MaterialiseDoctorSchedulesQuery, *policy.DoctorID, horizonDays)
+		if err != nil {
+			ftx.Logger().Error("Could not materialise schedules", zap.Error(err))
+			return errors.ErrDatabase
+		}
+		return nil
+	})
+}
+
+// DeleteDoctorPolicy removes the policy of a doctor, so the default applies to their schedules again
+func (r *repo) DeleteDoctorPolicy(ftx factory.Service, doctorId int, horizonDays int) error {
+	return r.inTransaction(ftx, "removing a doctor booking policy", func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ftx.Context(), DeleteDoctorPolicyQuery, doctorId)
+		if err != nil {
+			ftx.Logger().Error("Could not delete doctor booking policy", zap.Error(err))
+			return errors.ErrDatabase
+		}
+		if deleted, err := result.RowsAffected(); err != nil || deleted == 0 {
+			return errors.ErrNotFound
+		}
+
+		_, err = tx.ExecContext(ftx.Context(), MaterialiseDoctorSchedulesQuery, doctorId, horizonDays)
+		if err != nil {
+			ftx.Logger().Error("Could not materialise schedules", zap.Error(err))
+			return errors.ErrDatabase
+		}
+		return nil
+	})
+}
+
+// inTransaction runs fn in a transaction that is committed when fn succeeds and rolled back otherwise
+func (r *repo) inTransaction(ftx factory.Service, purpose string, fn func(tx *sql.Tx) error) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for " + purpose)
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully finished " + purpose)
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
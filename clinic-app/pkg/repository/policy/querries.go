@@ -0,0 +1,114 @@
+package policy
+
+const (
+	// Check whether a user is a doctor
+	IsDoctorQuery = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM Users
+			WHERE user_id = $1
+			AND role = 'doctor'
+		);
+	`
+
+	// View the clinic-wide default policy
+	GetDefaultPolicyQuery = `
+		SELECT
+			doctor_id,
+			max_daily_appointments,
+			max_daily_minutes,
+			min_duration_minutes,
+			max_duration_minutes,
+			buffer_minutes,
+			booking_horizon_days,
+			updated_by,
+			updated_at
+		FROM BookingPolicies
+		WHERE doctor_id IS NULL;
+	`
+
+	// View the policies of all doctors that override the default
+	GetDoctorPoliciesQuery = `
+		SELECT
+			doctor_id,
+			max_daily_appointments,
+			max_daily_minutes,
+			min_duration_minutes,
+			max_duration_minutes,
+			buffer_minutes,
+			booking_horizon_days,
+			updated_by,
+			updated_at
+		FROM BookingPolicies
+		WHERE doctor_id IS NOT NULL
+		ORDER BY doctor_id;
+	`
+
+	// View the rules that apply to a doctor, overrides merged with the default
+	GetEffectivePolicyQuery = `
+		SELECT
+			d.max_daily_appointments,
+			CAST(EXTRACT(EPOCH FROM d.max_daily_time) / 60 AS INT),
+			CAST(EXTRACT(EPOCH FROM d.min_duration) / 60 AS INT),
+			CAST(EXTRACT(EPOCH FROM d.max_duration) / 60 AS INT),
+			CAST(EXTRACT(EPOCH FROM d.buffer) / 60 AS INT),
+			d.booking_horizon_days
+		FROM effective_booking_policy($1) d;
+	`
+
+	// Change the clinic-wide default policy
+	UpdateDefaultPolicyQuery = `
+		UPDATE BookingPolicies
+		SET max_daily_appointments = $1,
+			max_daily_minutes = $2,
+			min_duration_minutes = $3,
+			max_duration_minutes = $4,
+			buffer_minutes = $5,
+			booking_horizon_days = $6,
+			updated_by = $7,
+			updated_at = NOW()
+		WHERE doctor_id IS NULL;
+	`
+
+	// Set the policy of a doctor, replacing the rules it overrode before
+	UpsertDoctorPolicyQuery = `
+		INSERT INTO BookingPolicies (
+			doctor_id,
+			max_daily_appointments,
+			max_daily_minutes,
+			min_duration_minutes,
+			max_duration_minutes,
+			buffer_minutes,
+			booking_horizon_days,
+			updated_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (doctor_id) DO UPDATE
+		SET max_daily_appointments = EXCLUDED.max_daily_appointments,
+			max_daily_minutes = EXCLUDED.max_daily_minutes,
+			min_duration_minutes = EXCLUDED.min_duration_minutes,
+			max_duration_minutes = EXCLUDED.max_duration_minutes,
+			buffer_minutes = EXCLUDED.buffer_minutes,
+			booking_horizon_days = EXCLUDED.booking_horizon_days,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = NOW();
+	`
+
+	// Remove the policy of a doctor, the default applies again
+	DeleteDoctorPolicyQuery = `
+		DELETE FROM BookingPolicies
+		WHERE doctor_id = $1;
+	`
+
+	// Apply the policy to the schedules of a doctor until $2 days from today
+	MaterialiseDoctorSchedulesQuery = `
+		SELECT materialise_schedules($1, CURRENT_DATE, CURRENT_DATE + $2::INT);
+	`
+
+	// Apply the policies to the schedules of every doctor until $1 days from today
+	MaterialiseAllSchedulesQuery = `
+		SELECT materialise_schedules(user_id, CURRENT_DATE, CURRENT_DATE + $1::INT)
+		FROM Users
+		WHERE role = 'doctor';
+	`
+)
@@ -0,0 +1,12 @@
+package policy
+
+import (
+	"clinic-app/pkg/repository"
+)
+
+type repo struct{}
+
+// New creates a new instance of repository with a database connection
+func New() repository.BookingPolicyRepository {
+	return &repo{}
+}
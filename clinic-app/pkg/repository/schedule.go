@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+)
+
+// ScheduleRepository defines methods for managing working hours and the schedules materialised from them
+type ScheduleRepository interface {
+	GetWorkingHours(ftx factory.Service, doctorId int) ([]models.WorkingHours, error)
+	ReplaceWorkingHours(ftx factory.Service, set models.SetWorkingHours, horizonDays int) error
+	MaterialiseSchedules(ftx factory.Service, horizonDays int) error
+	GetFreeSlots(ftx factory.Service, query models.FreeSlotQuery) ([]models.FreeSlot, error)
+}
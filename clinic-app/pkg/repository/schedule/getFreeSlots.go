@@ -0,0 +1,72 @@
+package schedule
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+
+	"go.uber.org/zap"
+)
+
+// GetFreeSlots computes the free slots of a doctor from the schedules and the booked appointments
+func (r *repo) GetFreeSlots(ftx factory.Service, query models.FreeSlotQuery) ([]models.FreeSlot, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for computing free slots")
+
+	// Defer a rollback in case anything fails
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	rows, err := tx.QueryContext(ftx.Context(), GetFreeSlotsQuery,
+		query.DoctorID,
+		query.From,
+		query.To,
+		query.Duration,
+		query.Granularity,
+	)
+	if err != nil {
+		ftx.Logger().Error("Could not compute free slots", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	defer rows.Close()
+
+	slots := []models.FreeSlot{}
+	for rows.Next() {
+		var slot models.FreeSlot
+		if err = rows.Scan(&slot.DoctorID, &slot.Date, &slot.StartTime, &slot.EndTime); err != nil {
+			ftx.Logger().Error("Error scanning free slot row", zap.Error(err))
+			return nil, errors.ErrDatabase
+		}
+		slots = append(slots, slot)
+	}
+	if err = rows.Err(); err != nil {
+		ftx.Logger().Error("Could not compute free slots", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully computed free slots",
+		zap.Int("Doctor ID", query.DoctorID),
+		zap.Int("Slots", len(slots)),
+	)
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return slots, nil
+}
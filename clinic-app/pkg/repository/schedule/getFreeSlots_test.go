@@ -0,0 +1,147 @@
+package schedule
+
+import (
+	"clinic-app/internal/testdb"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/clinictime"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestMain runs the clinic in a timezone with daylight saving time, before the database sessions are opened in it
+func TestMain(m *testing.M) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		panic(err)
+	}
+	clinictime.SetUpLocation(newYork)
+	os.Exit(m.Run())
+}
+
+// dstDay returns the next clinic day after tomorrow that lasts the given hours
+func dstDay(t *testing.T, hours time.Duration) time.Time {
+	t.Helper()
+	day := clinictime.Today().AddDate(0, 0, 2)
+	for i := 0; i < 366; i++ {
+		if clinictime.EndOfDay(day).Sub(day) == hours*time.Hour {
+			return day
+		}
+		day = clinictime.EndOfDay(day)
+	}
+	t.Fatalf("No %v hour day within a year in %s", hours, clinictime.Location())
+	return time.Time{}
+}
+
+// dstDoctor creates a doctor working the night from 00:00 to 04:00 on a day, with a policy letting
+// the whole night be booked up to a year ahead
+func dstDoctor(t *testing.T, db *sql.DB, day time.Time) int {
+	t.Helper()
+	doctorId := testdb.User(t, db, "doctor")
+	_, err := db.Exec(`
+		INSERT INTO BookingPolicies (doctor_id, max_daily_appointments, max_daily_minutes, max_duration_minutes, booking_horizon_days)
+		VALUES ($1, 12, 600, 600, 400);
+	`, doctorId)
+	if err != nil {
+		t.Fatalf("Could not set booking policy: %v", err)
+	}
+	testdb.WorkingDay(t, db, doctorId, day, "00:00", "04:00")
+	return doctorId
+}
+
+// freeSlots returns the wall-clock starts of the free slots of a doctor on a day
+func freeSlots(t *testing.T, db *sql.DB, doctorId int, day time.Time, duration int) []string {
+	t.Helper()
+	date := day.Format("2006-01-02")
+	slots, err := New().GetFreeSlots(testdb.Service(t, db, models.Principal{}), models.FreeSlotQuery{
+		DoctorID:    doctorId,
+		From:        date,
+		To:          date,
+		Duration:    duration,
+		Granularity: 30,
+	})
+	if err != nil {
+		t.Fatalf("Could not get free slots: %v", err)
+	}
+
+	starts := make([]string, len(slots))
+	for i, slot := range slots {
+		if slot.EndTime.Sub(slot.StartTime) != time.Duration(duration)*time.Minute {
+			t.Errorf("slot at %s lasts %v, want %d minutes", slot.StartTime, slot.EndTime.Sub(slot.StartTime), duration)
+		}
+		starts[i] = clinictime.In(slot.StartTime).Format("15:04 MST")
+	}
+	return starts
+}
+
+// scheduleCap returns the minutes of appointments the schedule of a doctor's day takes
+func scheduleCap(t *testing.T, db *sql.DB, doctorId int, day time.Time) int {
+	t.Helper()
+	var minutes int
+	err := db.QueryRow(`
+		SELECT (EXTRACT(EPOCH FROM max_appointment_time) / 60)::INT
+		FROM Schedules
+		WHERE doctor_id = $1
+		AND date = $2::DATE;
+	`, doctorId, day.Format("2006-01-02")).Scan(&minutes)
+	if err != nil {
+		t.Fatalf("Could not read schedule: %v", err)
+	}
+	return minutes
+}
+
+func TestFreeSlotsAcrossDaylightSavingTime(t *testing.T) {
+	db := testdb.Open(t)
+	tests := []struct {
+		name      string
+		hours     time.Duration
+		wantSlots []string
+		wantCap   int // Minutes the doctor works from 00:00 to 04:00
+	}{
+		{
+			name:      "spring forward",
+			hours:     23,
+			wantSlots: []string{"00:00 EST", "00:30 EST", "01:00 EST", "01:30 EST", "03:00 EDT", "03:30 EDT"},
+			wantCap:   180,
+		},
+		{
+			name:  "fall back",
+			hours: 25,
+			wantSlots: []string{
+				"00:00 EDT", "00:30 EDT", "01:00 EDT", "01:30 EDT",
+				"01:00 EST", "01:30 EST", "02:00 EST", "02:30 EST", "03:00 EST", "03:30 EST",
+			},
+			wantCap: 300,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			day := dstDay(t, tt.hours)
+			doctorId := dstDoctor(t, db, day)
+
+			// Slots follow the clock, skipping the lost hour and offering the repeated one twice
+			slots := freeSlots(t, db, doctorId, day, 30)
+			if len(slots) != len(tt.wantSlots) {
+				t.Fatalf("slots = %v, want %v", slots, tt.wantSlots)
+			}
+			for i := range slots {
+				if slots[i] != tt.wantSlots[i] {
+					t.Errorf("slot %d = %s, want %s", i, slots[i], tt.wantSlots[i])
+				}
+			}
+
+			// The daily cap is the time actually worked, so the whole night fits and not a minute more
+			if minutes := scheduleCap(t, db, doctorId, day); minutes != tt.wantCap {
+				t.Errorf("schedule caps the day at %d minutes, want %d", minutes, tt.wantCap)
+			}
+			if slots := freeSlots(t, db, doctorId, day, tt.wantCap); len(slots) != 1 {
+				t.Errorf("%d slots of the whole night, want 1", len(slots))
+			}
+			if slots := freeSlots(t, db, doctorId, day, tt.wantCap+30); len(slots) != 0 {
+				t.Errorf("%d slots longer than the night, want none", len(slots))
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package schedule
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+
+	"go.uber.org/zap"
+)
+
+// GetWorkingHours retrieves the current and future working hours of a doctor
+func (r *repo) GetWorkingHours(ftx factory.Service, doctorId int) ([]models.WorkingHours, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for retrieving working hours")
+
+	// Defer a rollback in case anything fails
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	rows, err := tx.QueryContext(ftx.Context(), GetWorkingHoursQuery, doctorId)
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve working hours", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	defer rows.Close()
+
+	var hours []models.WorkingHours
+	for rows.Next() {
+		var block models.WorkingHours
+		if err = rows.Scan(
+			&block.ID,
+			&block.DoctorID,
+			&block.Weekday,
+			&block.StartTime,
+			&block.EndTime,
+			&block.EffectiveFrom,
+			&block.EffectiveTo,
+		); err != nil {
+			ftx.Logger().Error("Error scanning working hours row", zap.Error(err))
+			return nil, errors.ErrDatabase
+		}
+		hours = append(hours, block)
+	}
+	if err = rows.Err(); err != nil {
+		ftx.Logger().Error("Could not retrieve working hours", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully retrieved working hours", zap.Int("Doctor ID", doctorId))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return hours, nil
+}
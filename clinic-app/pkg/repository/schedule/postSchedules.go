@@ -0,0 +1,47 @@
+package schedule
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/services/factory"
+
+	"go.uber.org/zap"
+)
+
+// MaterialiseSchedules materialises the schedules of every doctor up to horizonDays from today
+func (r *repo) MaterialiseSchedules(ftx factory.Service, horizonDays int) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for materialising schedules")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	_, err = tx.ExecContext(ftx.Context(), MaterialiseAllSchedulesQuery, horizonDays)
+	if err != nil {
+		ftx.Logger().Error("Could not materialise schedules", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully materialised schedules", zap.Int("Horizon Days", horizonDays))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
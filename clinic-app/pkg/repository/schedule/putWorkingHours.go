@@ -0,0 +1,93 @@
+package schedule
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+
+	"go.uber.org/zap"
+)
+
+// ReplaceWorkingHours replaces a doctor's working hours from the day the new ones take effect
+// and materialises the affected schedules up to horizonDays from today
+func (r *repo) ReplaceWorkingHours(ftx factory.Service, set models.SetWorkingHours, horizonDays int) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for replacing working hours")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Only doctors have working hours
+	var isDoctor bool
+	err = tx.QueryRowContext(ftx.Context(), IsDoctorQuery, set.DoctorID).Scan(&isDoctor)
+	if err != nil {
+		ftx.Logger().Error("Could not check doctor", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	if !isDoctor {
+		err = errors.ErrNotFound // Roll back the transaction
+		return err
+	}
+
+	// End the previous hours the day before and drop hours that were planned for later
+	_, err = tx.ExecContext(ftx.Context(), EndWorkingHoursQuery, set.DoctorID, set.EffectiveFrom)
+	if err != nil {
+		ftx.Logger().Error("Could not end working hours", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	_, err = tx.ExecContext(ftx.Context(), DeleteFutureWorkingHoursQuery, set.DoctorID, set.EffectiveFrom)
+	if err != nil {
+		ftx.Logger().Error("Could not delete future working hours", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Add the new blocks
+	for _, block := range set.Blocks {
+		_, err = tx.ExecContext(ftx.Context(), InsertWorkingHoursQuery,
+			set.DoctorID,
+			block.Weekday,
+			block.StartTime,
+			block.EndTime,
+			set.EffectiveFrom,
+			set.EffectiveTo,
+		)
+		if err != nil {
+			ftx.Logger().Error("Could not insert working hours", zap.Error(err))
+			return errors.ErrDatabase
+		}
+	}
+
+	// Bring the schedules in line with the new hours
+	_, err = tx.ExecContext(ftx.Context(), MaterialiseDoctorSchedulesQuery, set.DoctorID, set.EffectiveFrom, horizonDays)
+	if err != nil {
+		ftx.Logger().Error("Could not materialise schedules", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully replaced working hours",
+		zap.Int("Doctor ID", set.DoctorID),
+		zap.String("Effective From", set.EffectiveFrom),
+	)
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package schedule
+
+const (
+	// Check whether a user is a doctor
+	IsDoctorQuery = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM Users
+			WHERE user_id = $1
+			AND role = 'doctor'
+		);
+	`
+
+	// View the current and future working hours of a doctor
+	GetWorkingHoursQuery = `
+		SELECT
+			working_hours_id,
+			doctor_id,
+			weekday,
+			to_char(start_time, 'HH24:MI'),
+			to_char(end_time, 'HH24:MI'),
+			to_char(effective_from, 'YYYY-MM-DD'),
+			to_char(effective_to, 'YYYY-MM-DD')
+		FROM WorkingHours
+		WHERE doctor_id = $1
+		AND (effective_to IS NULL OR effective_to >= CURRENT_DATE)
+		ORDER BY effective_from, weekday, start_time;
+	`
+
+	// End the working hours that would still apply on or after the day new ones take effect
+	EndWorkingHoursQuery = `
+		UPDATE WorkingHours
+		SET effective_to = $2::DATE - 1
+		WHERE doctor_id = $1
+		AND effective_from < $2::DATE
+		AND (effective_to IS NULL OR effective_to >= $2::DATE);
+	`
+
+	// Remove the working hours that would only have taken effect on or after the day new ones take effect
+	DeleteFutureWorkingHoursQuery = `
+		DELETE FROM WorkingHours
+		WHERE doctor_id = $1
+		AND effective_from >= $2::DATE;
+	`
+
+	// Add a working block
+	InsertWorkingHoursQuery = `
+		INSERT INTO WorkingHours (doctor_id, weekday, start_time, end_time, effective_from, effective_to)
+		VALUES ($1, $2, $3::TIME, $4::TIME, $5::DATE, $6::DATE);
+	`
+
+	// Materialise the schedules of a doctor from $2 until $3 days from today
+	MaterialiseDoctorSchedulesQuery = `
+		SELECT materialise_schedules($1, GREATEST($2::DATE, CURRENT_DATE), CURRENT_DATE + $3::INT);
+	`
+
+	// Materialise the schedules of every doctor until $1 days from today
+	MaterialiseAllSchedulesQuery = `
+		SELECT materialise_schedules(user_id, CURRENT_DATE, CURRENT_DATE + $1::INT)
+		FROM Users
+		WHERE role = 'doctor';
+	`
+
+	// Compute the free slots of a doctor between two days. Candidates start every $5 minutes within the working
+	// blocks and last $4 minutes, they must lie in the future, fit the remaining capacity of their day
+	// and overlap neither an appointment that still takes place, a held time nor time off that was requested or granted.
+	// The doctor's booking policy bounds the duration and the horizon and keeps its buffer around appointments.
+	GetFreeSlotsQuery = `
+		SELECT
+			s.doctor_id,
+			s.date::TIMESTAMP,
+			slot_start,
+			slot_start + make_interval(mins => $4)
+		FROM Schedules s
+		JOIN ScheduleBlocks b ON b.schedule_id = s.schedule_id
+		CROSS JOIN LATERAL generate_series(
+			b.start_time,
+			b.end_time - make_interval(mins => $4),
+			make_interval(mins => $5)
+		) AS slot_start
+		CROSS JOIN effective_booking_policy($1) p
+		CROSS JOIN LATERAL (
+			SELECT COUNT(*) AS holds, COALESCE(SUM(hd.end_time - hd.start_time), INTERVAL '0') AS held_time
+			FROM AppointmentHolds hd
+			WHERE hd.doctor_id = s.doctor_id
+			AND hd.appointment_date = s.date
+			AND hd.status = 'held'
+			AND hd.expires_at > NOW()
+		) h
+		WHERE s.doctor_id = $1
+		AND s.date BETWEEN $2::DATE AND $3::DATE
+		AND make_interval(mins => $4) BETWEEN p.min_duration AND p.max_duration
+		AND slot_start < CURRENT_DATE + p.booking_horizon_days + 1
+		AND s.total_appointments + h.holds < s.max_appointments
+		AND s.total_appointment_time + h.held_time + make_interval(mins => $4) <= s.max_appointment_time
+		AND slot_start > CURRENT_TIMESTAMP
+		AND NOT EXISTS (
+			SELECT 1
+			FROM Appointment a
+			WHERE a.doctor_id = s.doctor_id
+			AND a.status NOT IN ('canceled', 'no_show')
+			AND tstzrange(a.start_time, a.end_time) && tstzrange(slot_start - p.buffer, slot_start + make_interval(mins => $4) + p.buffer)
+		)
+		AND NOT EXISTS (
+			SELECT 1
+			FROM AppointmentHolds hd
+			WHERE hd.doctor_id = s.doctor_id
+			AND hd.status = 'held'
+			AND hd.expires_at > NOW()
+			AND tstzrange(hd.start_time, hd.end_time) && tstzrange(slot_start - p.buffer, slot_start + make_interval(mins => $4) + p.buffer)
+		)
+		AND NOT EXISTS (
+			SELECT 1
+			FROM TimeOffBlocks tb
+			INNER JOIN TimeOff t ON tb.time_off_id = t.time_off_id
+			WHERE t.doctor_id = s.doctor_id
+			AND t.status IN ('pending', 'approved')
+			AND tstzrange(tb.start_time, tb.end_time) && tstzrange(slot_start, slot_start + make_interval(mins => $4))
+		)
+		AND NOT EXISTS (
+			SELECT 1
+			FROM WaitlistOffers o
+			WHERE o.doctor_id = s.doctor_id
+			AND o.status = 'open'
+			AND o.expires_at > NOW()
+			AND tstzrange(o.start_time, o.end_time) && tstzrange(slot_start, slot_start + make_interval(mins => $4))
+		)
+		ORDER BY slot_start;
+	`
+)
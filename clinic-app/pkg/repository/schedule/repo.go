@@ -0,0 +1,12 @@
+package schedule
+
+import (
+	"clinic-app/pkg/repository"
+)
+
+type repo struct{}
+
+// New creates a new instance of repository with a database connection
+func New() repository.ScheduleRepository {
+	return &repo{}
+}
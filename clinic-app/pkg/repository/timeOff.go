@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+)
+
+// TimeOffRepository defines methods for managing the time off of doctors
+type TimeOffRepository interface {
+	CreateTimeOff(ftx factory.Service, request models.RequestTimeOff) (models.TimeOff, error)
+	GetTimeOff(ftx factory.Service, timeOffId int) (models.TimeOff, error)
+	GetDoctorTimeOff(ftx factory.Service, doctorId int) ([]models.TimeOff, error)
+	GetTimeOffByStatus(ftx factory.Service, status string) ([]models.TimeOff, error)
+	UpdateTimeOffStatus(ftx factory.Service, timeOffId int, from, to string) error
+	GetAffectedAppointments(ftx factory.Service, timeOffId int) ([]models.Appointment, error)
+}
@@ -0,0 +1,196 @@
+package timeoff
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+
+	"go.uber.org/zap"
+)
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTimeOff reads a time off in the column order of the time off queries
+func scanTimeOff(row rowScanner) (models.TimeOff, error) {
+	var timeOff models.TimeOff
+	err := row.Scan(
+		&timeOff.ID,
+		&timeOff.DoctorID,
+		&timeOff.StartTime,
+		&timeOff.EndTime,
+		&timeOff.RepeatUntil,
+		&timeOff.Reason,
+		&timeOff.Status,
+		&timeOff.RequestedBy,
+		&timeOff.DecidedBy,
+		&timeOff.DecidedAt,
+		&timeOff.CreatedAt,
+	)
+	return timeOff, err
+}
+
+// GetTimeOff retrieves a time off by its ID
+func (r *repo) GetTimeOff(ftx factory.Service, timeOffId int) (models.TimeOff, error) {
+	var timeOff models.TimeOff
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return timeOff, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for retrieving a time off")
+
+	// Defer a rollback in case anything fails
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	timeOff, err = scanTimeOff(tx.QueryRowContext(ftx.Context(), GetTimeOffQuery, timeOffId))
+	if err == sql.ErrNoRows {
+		return timeOff, errors.ErrNotFound
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve time off", zap.Error(err))
+		return timeOff, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return timeOff, errors.ErrDatabase
+	}
+
+	middleware.GetTraceParentFromContext(ftx.Context())
+	return timeOff, nil
+}
+
+// GetDoctorTimeOff retrieves the time off of a doctor that has not ended yet
+func (r *repo) GetDoctorTimeOff(ftx factory.Service, doctorId int) ([]models.TimeOff, error) {
+	return r.listTimeOff(ftx, GetDoctorTimeOffQuery, doctorId)
+}
+
+// GetTimeOffByStatus retrieves the time off with a status that has not ended yet
+func (r *repo) GetTimeOffByStatus(ftx factory.Service, status string) ([]models.TimeOff, error) {
+	return r.listTimeOff(ftx, GetTimeOffByStatusQuery, status)
+}
+
+// listTimeOff runs one of the time off list queries
+func (r *repo) listTimeOff(ftx factory.Service, query string, arg any) ([]models.TimeOff, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for retrieving time off")
+
+	// Defer a rollback in case anything fails
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	rows, err := tx.QueryContext(ftx.Context(), query, arg)
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve time off", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	defer rows.Close()
+
+	timeOffs := []models.TimeOff{}
+	for rows.Next() {
+		var timeOff models.TimeOff
+		if timeOff, err = scanTimeOff(rows); err != nil {
+			ftx.Logger().Error("Error scanning time off row", zap.Error(err))
+			return nil, errors.ErrDatabase
+		}
+		timeOffs = append(timeOffs, timeOff)
+	}
+	if err = rows.Err(); err != nil {
+		ftx.Logger().Error("Could not retrieve time off", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	middleware.GetTraceParentFromContext(ftx.Context())
+	return timeOffs, nil
+}
+
+// GetAffectedAppointments retrieves the upcoming appointments of the doctor that fall into a time off
+func (r *repo) GetAffectedAppointments(ftx factory.Service, timeOffId int) ([]models.Appointment, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for retrieving affected appointments")
+
+	// Defer a rollback in case anything fails
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	rows, err := tx.QueryContext(ftx.Context(), GetAffectedAppointmentsQuery, timeOffId)
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve affected appointments", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	defer rows.Close()
+
+	appointments := []models.Appointment{}
+	for rows.Next() {
+		var aptmt models.Appointment
+		if err = rows.Scan(
+			&aptmt.AppointmentID,
+			&aptmt.PatientID,
+			&aptmt.DoctorID,
+			&aptmt.PatientName,
+			&aptmt.DoctorName,
+			&aptmt.StartTime,
+			&aptmt.EndTime,
+			&aptmt.Status,
+		); err != nil {
+			ftx.Logger().Error("Error scanning affected appointment row", zap.Error(err))
+			return nil, errors.ErrDatabase
+		}
+		appointments = append(appointments, aptmt)
+	}
+	if err = rows.Err(); err != nil {
+		ftx.Logger().Error("Could not retrieve affected appointments", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	middleware.GetTraceParentFromContext(ftx.Context())
+	return appointments, nil
+}
@@ -0,0 +1,78 @@
+package timeoff
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+
+	"go.uber.org/zap"
+)
+
+// CreateTimeOff records a pending time off request together with all of its weekly occurrences
+func (r *repo) CreateTimeOff(ftx factory.Service, request models.RequestTimeOff) (models.TimeOff, error) {
+	var timeOffId int
+
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return models.TimeOff{}, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for requesting time off")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Only doctors take time off
+	var isDoctor bool
+	err = tx.QueryRowContext(ftx.Context(), IsDoctorQuery, request.DoctorID).Scan(&isDoctor)
+	if err != nil {
+		ftx.Logger().Error("Could not check doctor", zap.Error(err))
+		return models.TimeOff{}, errors.ErrDatabase
+	}
+	if !isDoctor {
+		err = errors.ErrNotFound // Roll back the transaction
+		return models.TimeOff{}, err
+	}
+
+	// Record the request and its occurrences
+	err = tx.QueryRowContext(ftx.Context(), InsertTimeOffQuery,
+		request.DoctorID,
+		request.StartTime,
+		request.EndTime,
+		request.RepeatUntil,
+		request.Reason,
+		ftx.Principal().UserID,
+	).Scan(&timeOffId)
+	if err != nil {
+		ftx.Logger().Error("Could not insert time off", zap.Error(err))
+		return models.TimeOff{}, errors.ErrDatabase
+	}
+	_, err = tx.ExecContext(ftx.Context(), InsertTimeOffBlocksQuery, timeOffId)
+	if err != nil {
+		ftx.Logger().Error("Could not insert time off blocks", zap.Error(err))
+		return models.TimeOff{}, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return models.TimeOff{}, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully requested time off",
+		zap.Int("Time Off ID", timeOffId),
+		zap.Int("Doctor ID", request.DoctorID),
+	)
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return r.GetTimeOff(ftx, timeOffId)
+}
@@ -0,0 +1,63 @@
+package timeoff
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+
+	"go.uber.org/zap"
+)
+
+// UpdateTimeOffStatus moves a time off from one status to another and records the caller as the one who decided.
+// It fails with ErrTimeOffStatus when the status changed in the meantime.
+func (r *repo) UpdateTimeOffStatus(ftx factory.Service, timeOffId int, from, to string) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for updating time off status")
+
+	// Defer a rollback in case anything fails
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	var updatedId int
+	err = tx.QueryRowContext(ftx.Context(), UpdateTimeOffStatusQuery,
+		timeOffId,
+		from,
+		to,
+		ftx.Principal().UserID,
+	).Scan(&updatedId)
+	if err == sql.ErrNoRows {
+		ftx.Logger().Info("Time off status changed concurrently", zap.Int("Time Off ID", timeOffId))
+		return errors.ErrTimeOffStatus
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not update time off status", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Updated time off status",
+		zap.Int("Time Off ID", timeOffId),
+		zap.String("From", from),
+		zap.String("To", to),
+	)
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
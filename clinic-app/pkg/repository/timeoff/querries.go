@@ -0,0 +1,121 @@
+package timeoff
+
+const (
+	// Check whether a user is a doctor
+	IsDoctorQuery = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM Users
+			WHERE user_id = $1
+			AND role = 'doctor'
+		);
+	`
+
+	// Request time off
+	InsertTimeOffQuery = `
+		INSERT INTO TimeOff (doctor_id, start_time, end_time, repeat_until, reason, requested_by)
+		VALUES ($1, $2, $3, $4::DATE, NULLIF($5, ''), $6)
+		RETURNING time_off_id;
+	`
+
+	// Add every weekly occurrence of a time off until its repeat_until, or only the first one without it
+	InsertTimeOffBlocksQuery = `
+		INSERT INTO TimeOffBlocks (time_off_id, start_time, end_time)
+		SELECT time_off_id, start_time + week * INTERVAL '7 days', end_time + week * INTERVAL '7 days'
+		FROM TimeOff
+		CROSS JOIN LATERAL generate_series(0, COALESCE((repeat_until - start_time::DATE) / 7, 0)) AS week
+		WHERE time_off_id = $1;
+	`
+
+	// View a time off
+	GetTimeOffQuery = `
+		SELECT
+			time_off_id,
+			doctor_id,
+			start_time,
+			end_time,
+			to_char(repeat_until, 'YYYY-MM-DD'),
+			reason,
+			status,
+			requested_by,
+			decided_by,
+			decided_at,
+			created_at
+		FROM TimeOff
+		WHERE time_off_id = $1;
+	`
+
+	// View the time off of a doctor that has not ended yet
+	GetDoctorTimeOffQuery = `
+		SELECT
+			time_off_id,
+			doctor_id,
+			start_time,
+			end_time,
+			to_char(repeat_until, 'YYYY-MM-DD'),
+			reason,
+			status,
+			requested_by,
+			decided_by,
+			decided_at,
+			created_at
+		FROM TimeOff
+		WHERE doctor_id = $1
+		AND (SELECT MAX(end_time) FROM TimeOffBlocks WHERE TimeOffBlocks.time_off_id = TimeOff.time_off_id) > NOW()
+		ORDER BY start_time;
+	`
+
+	// View the time off with a status that has not ended yet
+	GetTimeOffByStatusQuery = `
+		SELECT
+			time_off_id,
+			doctor_id,
+			start_time,
+			end_time,
+			to_char(repeat_until, 'YYYY-MM-DD'),
+			reason,
+			status,
+			requested_by,
+			decided_by,
+			decided_at,
+			created_at
+		FROM TimeOff
+		WHERE status = $1
+		AND (SELECT MAX(end_time) FROM TimeOffBlocks WHERE TimeOffBlocks.time_off_id = TimeOff.time_off_id) > NOW()
+		ORDER BY start_time;
+	`
+
+	// Move a time off from status $2 to status $3 and record who decided it
+	UpdateTimeOffStatusQuery = `
+		UPDATE TimeOff
+		SET status = $3,
+			decided_by = $4,
+			decided_at = NOW()
+		WHERE time_off_id = $1
+		AND status = $2
+		RETURNING time_off_id;
+	`
+
+	// View the upcoming appointments of the doctor that fall into a time off
+	GetAffectedAppointmentsQuery = `
+		SELECT DISTINCT
+			Appointment.appointment_id,
+			Appointment.patient_id,
+			Appointment.doctor_id,
+			Patient.name AS patient_name,
+			Doctor.name AS doctor_name,
+			Appointment.start_time,
+			Appointment.end_time,
+			Appointment.status
+		FROM TimeOff
+		INNER JOIN TimeOffBlocks ON TimeOffBlocks.time_off_id = TimeOff.time_off_id
+		INNER JOIN Appointment ON Appointment.doctor_id = TimeOff.doctor_id
+		INNER JOIN Users AS Patient ON Appointment.patient_id = Patient.user_id
+		INNER JOIN Users AS Doctor ON Appointment.doctor_id = Doctor.user_id
+		WHERE TimeOff.time_off_id = $1
+		AND Appointment.status IN ('scheduled', 'checked_in')
+		AND Appointment.end_time > NOW()
+		AND tstzrange(Appointment.start_time, Appointment.end_time) && tstzrange(TimeOffBlocks.start_time, TimeOffBlocks.end_time)
+		ORDER BY Appointment.start_time;
+	`
+)
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"time"
+)
+
+// WaitlistRepository defines methods for keeping patients on the waitlist of a doctor and offering them freed slots
+type WaitlistRepository interface {
+	CreateEntry(ftx factory.Service, request models.JoinWaitlist) (models.WaitlistEntry, error)
+	GetEntry(ftx factory.Service, entryId int) (models.WaitlistEntry, error)
+	GetPatientEntries(ftx factory.Service, patientId int) ([]models.WaitlistEntry, error)
+	GetDoctorEntries(ftx factory.Service, doctorId int) ([]models.WaitlistEntry, error)
+	LeaveWaitlist(ftx factory.Service, entryId int) error
+	GetOffer(ftx factory.Service, offerId int) (models.WaitlistOffer, error)
+	AcceptOffer(ftx factory.Service, offerId int) (int, error)
+	DeclineOffer(ftx factory.Service, offerId int) error
+	ExpireOffers(ftx factory.Service) (int64, error)
+	ExpireEntries(ftx factory.Service) (int64, error)
+	GetWaitingEntries(ftx factory.Service) ([]int, error)
+	GetReleasedEntries(ftx factory.Service, doctorId int, date time.Time) ([]int, error)
+	OfferSlot(ftx factory.Service, entryId int, hold time.Duration, granularity int) (*models.OfferNotice, error)
+}
@@ -0,0 +1,353 @@
+package waitlist
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanEntry reads a waitlist entry in the column order of the entry queries
+func scanEntry(row rowScanner) (models.WaitlistEntry, error) {
+	entry := models.WaitlistEntry{
+		Windows: []models.WaitlistWindow{},
+		Offers:  []models.WaitlistOffer{},
+	}
+	err := row.Scan(
+		&entry.ID,
+		&entry.PatientID,
+		&entry.DoctorID,
+		&entry.DateFrom,
+		&entry.DateTo,
+		&entry.DurationMinutes,
+		&entry.Status,
+		&entry.CreatedAt,
+	)
+	return entry, err
+}
+
+// scanOffer reads a waitlist offer in the column order of the offer queries
+func scanOffer(row rowScanner) (models.WaitlistOffer, error) {
+	var offer models.WaitlistOffer
+	var appointmentId sql.NullInt64
+	err := row.Scan(
+		&offer.ID,
+		&offer.EntryID,
+		&offer.DoctorID,
+		&offer.StartTime,
+		&offer.EndTime,
+		&offer.Status,
+		&offer.OfferedAt,
+		&offer.ExpiresAt,
+		&offer.RespondedAt,
+		&appointmentId,
+	)
+	if appointmentId.Valid {
+		id := int(appointmentId.Int64)
+		offer.AppointmentID = &id
+	}
+	return offer, err
+}
+
+// GetEntry retrieves a waitlist entry with its windows and offers
+func (r *repo) GetEntry(ftx factory.Service, entryId int) (models.WaitlistEntry, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return models.WaitlistEntry{}, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for retrieving waitlist entry")
+
+	// Defer a rollback in case anything fails
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	entry, err := scanEntry(tx.QueryRowContext(ftx.Context(), GetEntryQuery, entryId))
+	if err == sql.ErrNoRows {
+		return entry, errors.ErrNotFound
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve waitlist entry", zap.Error(err))
+		return entry, errors.ErrDatabase
+	}
+
+	entries := []models.WaitlistEntry{entry}
+	if err = attachDetails(ftx, tx, entries); err != nil {
+		return entry, err
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return models.WaitlistEntry{}, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully retrieved waitlist entry", zap.Int("Waitlist Entry ID", entryId))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return entries[0], nil
+}
+
+// GetPatientEntries retrieves every waitlist entry of a patient, newest first
+func (r *repo) GetPatientEntries(ftx factory.Service, patientId int) ([]models.WaitlistEntry, error) {
+	return listEntries(ftx, GetPatientEntriesQuery, patientId)
+}
+
+// GetDoctorEntries retrieves the active waitlist of a doctor in the order patients are offered slots
+func (r *repo) GetDoctorEntries(ftx factory.Service, doctorId int) ([]models.WaitlistEntry, error) {
+	return listEntries(ftx, GetDoctorEntriesQuery, doctorId)
+}
+
+// GetOffer retrieves a waitlist offer
+func (r *repo) GetOffer(ftx factory.Service, offerId int) (models.WaitlistOffer, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return models.WaitlistOffer{}, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for retrieving waitlist offer")
+
+	// Defer a rollback in case anything fails
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	offer, err := getOffer(ftx, tx, offerId)
+	if err != nil {
+		return offer, err
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return models.WaitlistOffer{}, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully retrieved waitlist offer", zap.Int("Waitlist Offer ID", offerId))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return offer, nil
+}
+
+// getOffer reads a waitlist offer within a transaction
+func getOffer(ftx factory.Service, tx *sql.Tx, offerId int) (models.WaitlistOffer, error) {
+	offer, err := scanOffer(tx.QueryRowContext(ftx.Context(), GetOfferQuery, offerId))
+	if err == sql.ErrNoRows {
+		return offer, errors.ErrNotFound
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve waitlist offer", zap.Error(err))
+		return offer, errors.ErrDatabase
+	}
+	return offer, nil
+}
+
+// GetWaitingEntries retrieves the IDs of the waiting entries in the order they joined the waitlist
+func (r *repo) GetWaitingEntries(ftx factory.Service) ([]int, error) {
+	return waitingEntries(ftx, GetWaitingEntriesQuery)
+}
+
+// GetReleasedEntries retrieves the IDs of the entries waiting for capacity a doctor has again, first come first served.
+// A zero doctor stands for every doctor and a zero date for every day.
+func (r *repo) GetReleasedEntries(ftx factory.Service, doctorId int, date time.Time) ([]int, error) {
+	var day any // Every day when left NULL
+	if !date.IsZero() {
+		day = date
+	}
+	return waitingEntries(ftx, GetReleasedEntriesQuery, doctorId, day)
+}
+
+// waitingEntries runs one of the waiting entry queries and collects the IDs
+func waitingEntries(ftx factory.Service, query string, args ...any) ([]int, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for retrieving waiting entries")
+
+	// Defer a rollback in case anything fails
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	rows, err := tx.QueryContext(ftx.Context(), query, args...)
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve waiting entries", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	defer rows.Close()
+
+	var entryIds []int
+	for rows.Next() {
+		var entryId int
+		if err = rows.Scan(&entryId); err != nil {
+			ftx.Logger().Error("Error scanning waiting entry row", zap.Error(err))
+			return nil, errors.ErrDatabase
+		}
+		entryIds = append(entryIds, entryId)
+	}
+	if err = rows.Err(); err != nil {
+		ftx.Logger().Error("Could not retrieve waiting entries", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully retrieved waiting entries", zap.Int("Count", len(entryIds)))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return entryIds, nil
+}
+
+// listEntries runs one of the entry list queries and attaches the windows and offers of the entries
+func listEntries(ftx factory.Service, query string, arg any) ([]models.WaitlistEntry, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for retrieving waitlist entries")
+
+	// Defer a rollback in case anything fails
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	rows, err := tx.QueryContext(ftx.Context(), query, arg)
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve waitlist entries", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	defer rows.Close()
+
+	entries := []models.WaitlistEntry{}
+	for rows.Next() {
+		var entry models.WaitlistEntry
+		entry, err = scanEntry(rows)
+		if err != nil {
+			ftx.Logger().Error("Error scanning waitlist entry row", zap.Error(err))
+			return nil, errors.ErrDatabase
+		}
+		entries = append(entries, entry)
+	}
+	if err = rows.Err(); err != nil {
+		ftx.Logger().Error("Could not retrieve waitlist entries", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	rows.Close() // The connection of the transaction reads one result at a time
+
+	if err = attachDetails(ftx, tx, entries); err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully retrieved waitlist entries", zap.Int("Count", len(entries)))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return entries, nil
+}
+
+// attachDetails loads the windows and offers of the entries in two queries within a transaction
+func attachDetails(ftx factory.Service, tx *sql.Tx, entries []models.WaitlistEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	index := make(map[int]int, len(entries))
+	entryIds := make([]int64, len(entries))
+	for i, entry := range entries {
+		index[entry.ID] = i
+		entryIds[i] = int64(entry.ID)
+	}
+
+	// Windows of the entries
+	rows, err := tx.QueryContext(ftx.Context(), GetWindowsQuery, pq.Array(entryIds))
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve waitlist windows", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var entryId int
+		var window models.WaitlistWindow
+		if err := rows.Scan(&entryId, &window.StartTime, &window.EndTime); err != nil {
+			ftx.Logger().Error("Error scanning waitlist window row", zap.Error(err))
+			return errors.ErrDatabase
+		}
+		i := index[entryId]
+		entries[i].Windows = append(entries[i].Windows, window)
+	}
+	if err := rows.Err(); err != nil {
+		ftx.Logger().Error("Could not retrieve waitlist windows", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	rows.Close() // The connection of the transaction reads one result at a time
+
+	// Offers made to the entries
+	offerRows, err := tx.QueryContext(ftx.Context(), GetOffersQuery, pq.Array(entryIds))
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve waitlist offers", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	defer offerRows.Close()
+	for offerRows.Next() {
+		offer, err := scanOffer(offerRows)
+		if err != nil {
+			ftx.Logger().Error("Error scanning waitlist offer row", zap.Error(err))
+			return errors.ErrDatabase
+		}
+		i := index[offer.EntryID]
+		entries[i].Offers = append(entries[i].Offers, offer)
+	}
+	if err := offerRows.Err(); err != nil {
+		ftx.Logger().Error("Could not retrieve waitlist offers", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	return nil
+}
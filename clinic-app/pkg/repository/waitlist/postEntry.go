@@ -0,0 +1,79 @@
+package waitlist
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+
+	"go.uber.org/zap"
+)
+
+// CreateEntry puts a patient on the waitlist of a doctor together with their preferred times of day
+func (r *repo) CreateEntry(ftx factory.Service, request models.JoinWaitlist) (models.WaitlistEntry, error) {
+	var entryId int
+
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return models.WaitlistEntry{}, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for joining the waitlist")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Patients can only wait for doctors
+	var isDoctor bool
+	err = tx.QueryRowContext(ftx.Context(), IsDoctorQuery, request.DoctorID).Scan(&isDoctor)
+	if err != nil {
+		ftx.Logger().Error("Could not check doctor", zap.Error(err))
+		return models.WaitlistEntry{}, errors.ErrDatabase
+	}
+	if !isDoctor {
+		err = errors.ErrNotFound // Roll back the transaction
+		return models.WaitlistEntry{}, err
+	}
+
+	// Record the entry and its windows
+	err = tx.QueryRowContext(ftx.Context(), InsertEntryQuery,
+		request.PatientID,
+		request.DoctorID,
+		request.DateFrom,
+		request.DateTo,
+		request.DurationMinutes,
+	).Scan(&entryId)
+	if err != nil {
+		ftx.Logger().Error("Could not insert waitlist entry", zap.Error(err))
+		return models.WaitlistEntry{}, errors.ErrDatabase
+	}
+	for _, window := range request.Windows {
+		_, err = tx.ExecContext(ftx.Context(), InsertWindowQuery, entryId, window.StartTime, window.EndTime)
+		if err != nil {
+			ftx.Logger().Error("Could not insert waitlist window", zap.Error(err))
+			return models.WaitlistEntry{}, errors.ErrDatabase
+		}
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return models.WaitlistEntry{}, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully joined the waitlist",
+		zap.Int("Waitlist Entry ID", entryId),
+		zap.Int("Doctor ID", request.DoctorID),
+	)
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return r.GetEntry(ftx, entryId)
+}
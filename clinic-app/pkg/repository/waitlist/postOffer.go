@@ -0,0 +1,101 @@
+package waitlist
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// OfferSlot offers the earliest matching free slot to a waiting entry and holds it for the patient.
+// It returns nil when there is no slot for the entry.
+func (r *repo) OfferSlot(ftx factory.Service, entryId int, hold time.Duration, granularity int) (*models.OfferNotice, error) {
+	var notice models.OfferNotice
+
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for offering a waitlist slot")
+
+	// Defer a rollback in case of any errors, a run that finds no slot rolls back as well
+	found := false
+	defer func() {
+		if err != nil || !found {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	// Wait for other runs, so the slot cannot be offered twice
+	_, err = tx.ExecContext(ftx.Context(), LockOffersQuery)
+	if err != nil {
+		ftx.Logger().Error("Could not lock waitlist offers", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	err = tx.QueryRowContext(ftx.Context(), FindSlotQuery, entryId, granularity).Scan(&notice.StartTime, &notice.EndTime)
+	if err == sql.ErrNoRows {
+		err = nil
+		return nil, nil
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not find a slot for waitlist entry", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	// Hold the slot for the patient
+	err = tx.QueryRowContext(ftx.Context(), InsertOfferQuery,
+		entryId,
+		notice.StartTime,
+		notice.EndTime,
+		hold.Seconds(),
+	).Scan(&notice.ID)
+	if err == sql.ErrNoRows {
+		// The entry stopped waiting in the meantime
+		err = nil
+		return nil, nil
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not insert waitlist offer", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	// Read the whole offer and who it goes to before committing, so a failed read does not leave an offer nobody is told about
+	notice.WaitlistOffer, err = getOffer(ftx, tx, notice.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.QueryRowContext(ftx.Context(), GetOfferNoticeQuery, notice.ID).Scan(
+		&notice.PatientName,
+		&notice.PatientEmail,
+		&notice.DoctorName,
+	)
+	if err != nil {
+		ftx.Logger().Error("Could not retrieve waitlist offer recipient", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	found = true
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return nil, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Offered waitlist slot",
+		zap.Int("Waitlist Offer ID", notice.ID),
+		zap.Int("Waitlist Entry ID", entryId),
+		zap.Time("Start Time", notice.StartTime),
+	)
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return &notice, nil
+}
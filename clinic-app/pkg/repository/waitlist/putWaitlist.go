@@ -0,0 +1,195 @@
+package waitlist
+
+import (
+	"clinic-app/cmd/rest/middleware"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/services/factory"
+	"database/sql"
+
+	"go.uber.org/zap"
+)
+
+// LeaveWaitlist takes a patient off the waitlist and withdraws an open offer.
+// It returns ErrNotOnWaitlist when the entry is no longer waiting or offered.
+func (r *repo) LeaveWaitlist(ftx factory.Service, entryId int) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for leaving the waitlist")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	var leftId int
+	err = tx.QueryRowContext(ftx.Context(), LeaveWaitlistQuery, entryId).Scan(&leftId)
+	if err == sql.ErrNoRows {
+		return errors.ErrNotOnWaitlist
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not leave waitlist", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Left waitlist", zap.Int("Waitlist Entry ID", entryId))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
+
+// AcceptOffer records that the patient booked the offered slot and returns the appointment they booked.
+// It returns ErrOfferClosed when the offer was already answered.
+func (r *repo) AcceptOffer(ftx factory.Service, offerId int) (int, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for accepting a waitlist offer")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	var appointmentId sql.NullInt64
+	err = tx.QueryRowContext(ftx.Context(), AcceptOfferQuery, offerId).Scan(&appointmentId)
+	if err == sql.ErrNoRows {
+		return 0, errors.ErrOfferClosed
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not accept waitlist offer", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Accepted waitlist offer",
+		zap.Int("Waitlist Offer ID", offerId),
+		zap.Int64("Appointment ID", appointmentId.Int64),
+	)
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return int(appointmentId.Int64), nil
+}
+
+// DeclineOffer records that the patient declined the offered slot, the patient keeps waiting for another one.
+// It returns ErrOfferClosed when the offer is no longer open.
+func (r *repo) DeclineOffer(ftx factory.Service, offerId int) error {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for declining a waitlist offer")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	var entryId int
+	err = tx.QueryRowContext(ftx.Context(), DeclineOfferQuery, offerId).Scan(&entryId)
+	if err == sql.ErrNoRows {
+		return errors.ErrOfferClosed
+	}
+	if err != nil {
+		ftx.Logger().Error("Could not decline waitlist offer", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Declined waitlist offer", zap.Int("Waitlist Offer ID", offerId))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return nil
+}
+
+// ExpireOffers expires the offers nobody answered in time and returns how many entries are waiting again
+func (r *repo) ExpireOffers(ftx factory.Service) (int64, error) {
+	return expire(ftx, "Could not expire waitlist offers", ExpireOffersQuery)
+}
+
+// ExpireEntries expires the waiting entries whose days have passed and returns how many there were
+func (r *repo) ExpireEntries(ftx factory.Service) (int64, error) {
+	return expire(ftx, "Could not expire waitlist entries", ExpireEntriesQuery)
+}
+
+// expire runs one of the expiry queries in a transaction and returns how many rows it changed
+func expire(ftx factory.Service, failure string, query string) (int64, error) {
+	// Start a new transaction
+	tx, err := ftx.TransactionManager().Begin()
+	if err != nil {
+		ftx.Logger().Error("Could not begin transaction", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+	ftx.Logger().Info("Transaction started for expiring the waitlist")
+
+	// Defer a rollback in case of any errors
+	defer func() {
+		if err != nil {
+			rollbackErr := ftx.TransactionManager().Rollback(tx)
+			if rollbackErr != nil {
+				ftx.Logger().Error("Failed to rollback transaction", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	result, err := tx.ExecContext(ftx.Context(), query)
+	if err != nil {
+		ftx.Logger().Error(failure, zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+	expired, err := result.RowsAffected()
+	if err != nil {
+		ftx.Logger().Error("Could not count rows affected", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	// Commit the transaction if no errors occurred
+	if err := ftx.TransactionManager().Commit(tx); err != nil {
+		ftx.Logger().Error("Could not commit transaction", zap.Error(err))
+		return 0, errors.ErrDatabase
+	}
+
+	ftx.Logger().Info("Successfully expired the waitlist", zap.Int64("Expired", expired))
+	middleware.GetTraceParentFromContext(ftx.Context())
+
+	return expired, nil
+}
@@ -0,0 +1,341 @@
+package waitlist
+
+const (
+	// Check whether a user is a doctor
+	IsDoctorQuery = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM Users
+			WHERE user_id = $1
+			AND role = 'doctor'
+		);
+	`
+
+	// Put a patient on the waitlist of a doctor
+	InsertEntryQuery = `
+		INSERT INTO WaitlistEntries (patient_id, doctor_id, date_from, date_to, duration_minutes)
+		VALUES ($1, $2, $3::DATE, $4::DATE, $5)
+		RETURNING waitlist_entry_id;
+	`
+
+	// Add a preferred time of day to a waitlist entry
+	InsertWindowQuery = `
+		INSERT INTO WaitlistWindows (waitlist_entry_id, start_time, end_time)
+		VALUES ($1, $2::TIME, $3::TIME);
+	`
+
+	// View a waitlist entry
+	GetEntryQuery = `
+		SELECT
+			waitlist_entry_id,
+			patient_id,
+			doctor_id,
+			to_char(date_from, 'YYYY-MM-DD'),
+			to_char(date_to, 'YYYY-MM-DD'),
+			duration_minutes,
+			status,
+			created_at
+		FROM WaitlistEntries
+		WHERE waitlist_entry_id = $1;
+	`
+
+	// View all waitlist entries of a patient, newest first
+	GetPatientEntriesQuery = `
+		SELECT
+			waitlist_entry_id,
+			patient_id,
+			doctor_id,
+			to_char(date_from, 'YYYY-MM-DD'),
+			to_char(date_to, 'YYYY-MM-DD'),
+			duration_minutes,
+			status,
+			created_at
+		FROM WaitlistEntries
+		WHERE patient_id = $1
+		ORDER BY created_at DESC, waitlist_entry_id DESC;
+	`
+
+	// View the active waitlist of a doctor in the order patients are offered slots
+	GetDoctorEntriesQuery = `
+		SELECT
+			waitlist_entry_id,
+			patient_id,
+			doctor_id,
+			to_char(date_from, 'YYYY-MM-DD'),
+			to_char(date_to, 'YYYY-MM-DD'),
+			duration_minutes,
+			status,
+			created_at
+		FROM WaitlistEntries
+		WHERE doctor_id = $1
+		AND status IN ('waiting', 'offered')
+		ORDER BY created_at, waitlist_entry_id;
+	`
+
+	// View the preferred times of day of waitlist entries
+	GetWindowsQuery = `
+		SELECT waitlist_entry_id, to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI')
+		FROM WaitlistWindows
+		WHERE waitlist_entry_id = ANY($1)
+		ORDER BY waitlist_entry_id, start_time;
+	`
+
+	// View the offers made to waitlist entries
+	GetOffersQuery = `
+		SELECT
+			waitlist_offer_id,
+			waitlist_entry_id,
+			doctor_id,
+			start_time,
+			end_time,
+			status,
+			offered_at,
+			expires_at,
+			responded_at,
+			appointment_id
+		FROM WaitlistOffers
+		WHERE waitlist_entry_id = ANY($1)
+		ORDER BY waitlist_entry_id, offered_at;
+	`
+
+	// View a waitlist offer
+	GetOfferQuery = `
+		SELECT
+			waitlist_offer_id,
+			waitlist_entry_id,
+			doctor_id,
+			start_time,
+			end_time,
+			status,
+			offered_at,
+			expires_at,
+			responded_at,
+			appointment_id
+		FROM WaitlistOffers
+		WHERE waitlist_offer_id = $1;
+	`
+
+	// Take a patient off the waitlist and withdraw the slot offered to them
+	LeaveWaitlistQuery = `
+		WITH entry AS (
+			UPDATE WaitlistEntries
+			SET status = 'left'
+			WHERE waitlist_entry_id = $1
+			AND status IN ('waiting', 'offered')
+			RETURNING waitlist_entry_id
+		),
+		withdrawn AS (
+			UPDATE WaitlistOffers
+			SET status = 'withdrawn', responded_at = NOW()
+			WHERE waitlist_entry_id IN (SELECT waitlist_entry_id FROM entry)
+			AND status = 'open'
+		)
+		SELECT waitlist_entry_id FROM entry;
+	`
+
+	// Record that an offer was accepted together with the appointment the patient booked for it.
+	// An offer that expired while the patient was booking is still accepted.
+	AcceptOfferQuery = `
+		WITH offer AS (
+			UPDATE WaitlistOffers o
+			SET status = 'accepted', responded_at = NOW(), appointment_id = (
+				SELECT a.appointment_id
+				FROM Appointment a
+				INNER JOIN WaitlistEntries e ON e.waitlist_entry_id = o.waitlist_entry_id
+				WHERE a.patient_id = e.patient_id
+				AND a.doctor_id = o.doctor_id
+				AND a.start_time = o.start_time
+				AND a.status = 'scheduled'
+				ORDER BY a.appointment_id DESC
+				LIMIT 1
+			)
+			WHERE o.waitlist_offer_id = $1
+			AND o.status IN ('open', 'expired')
+			RETURNING o.waitlist_entry_id, o.appointment_id
+		),
+		entry AS (
+			UPDATE WaitlistEntries
+			SET status = 'booked'
+			WHERE waitlist_entry_id IN (SELECT waitlist_entry_id FROM offer)
+		)
+		SELECT appointment_id FROM offer;
+	`
+
+	// Record that an offer was declined and put the patient back in line for the next slot
+	DeclineOfferQuery = `
+		WITH offer AS (
+			UPDATE WaitlistOffers
+			SET status = 'declined', responded_at = NOW()
+			WHERE waitlist_offer_id = $1
+			AND status = 'open'
+			RETURNING waitlist_entry_id
+		),
+		entry AS (
+			UPDATE WaitlistEntries
+			SET status = 'waiting'
+			WHERE waitlist_entry_id IN (SELECT waitlist_entry_id FROM offer)
+			AND status = 'offered'
+		)
+		SELECT waitlist_entry_id FROM offer;
+	`
+
+	// Expire the offers nobody answered in time and put their patients back in line
+	ExpireOffersQuery = `
+		WITH expired AS (
+			UPDATE WaitlistOffers
+			SET status = 'expired'
+			WHERE status = 'open'
+			AND expires_at <= NOW()
+			RETURNING waitlist_entry_id
+		)
+		UPDATE WaitlistEntries
+		SET status = 'waiting'
+		WHERE waitlist_entry_id IN (SELECT waitlist_entry_id FROM expired)
+		AND status = 'offered';
+	`
+
+	// Expire the waiting entries whose days have passed
+	ExpireEntriesQuery = `
+		UPDATE WaitlistEntries
+		SET status = 'expired'
+		WHERE status = 'waiting'
+		AND date_to < CURRENT_DATE;
+	`
+
+	// Get the waiting entries in the order they joined the waitlist
+	GetWaitingEntriesQuery = `
+		SELECT waitlist_entry_id
+		FROM WaitlistEntries
+		WHERE status = 'waiting'
+		ORDER BY created_at, waitlist_entry_id;
+	`
+
+	// View the waiting entries of a doctor, or of every doctor for 0, that take a day, or any day for NULL, first come first served
+	GetReleasedEntriesQuery = `
+		SELECT waitlist_entry_id
+		FROM WaitlistEntries
+		WHERE status = 'waiting'
+		AND ($1::INT = 0 OR doctor_id = $1::INT)
+		AND ($2::DATE IS NULL OR $2::DATE BETWEEN date_from AND date_to)
+		ORDER BY created_at, waitlist_entry_id;
+	`
+
+	// Serialise the offers, so two runs cannot offer the same slot to two patients
+	LockOffersQuery = `
+		SELECT pg_advisory_xact_lock(hashtext('waitlist_offers'));
+	`
+
+	// Find the earliest free slot for a waiting entry. It has to fit one of the entry's windows, if it has any,
+	// must not be held by another offer or a hold or have been offered to the entry before, and the patient must be free.
+	FindSlotQuery = `
+		SELECT slot_start, slot_start + d.duration
+		FROM WaitlistEntries e
+		CROSS JOIN LATERAL (SELECT make_interval(mins => e.duration_minutes) AS duration) d
+		CROSS JOIN effective_booking_policy(e.doctor_id) p
+		JOIN Schedules s ON s.doctor_id = e.doctor_id
+		JOIN ScheduleBlocks b ON b.schedule_id = s.schedule_id
+		CROSS JOIN LATERAL generate_series(
+			b.start_time,
+			b.end_time - d.duration,
+			make_interval(mins => $2)
+		) AS slot_start
+		CROSS JOIN LATERAL (
+			SELECT COUNT(*) AS holds, COALESCE(SUM(hd.end_time - hd.start_time), INTERVAL '0') AS held_time
+			FROM AppointmentHolds hd
+			WHERE hd.doctor_id = s.doctor_id
+			AND hd.appointment_date = s.date
+			AND hd.status = 'held'
+			AND hd.expires_at > NOW()
+		) h
+		WHERE e.waitlist_entry_id = $1
+		AND e.status = 'waiting'
+		AND s.date BETWEEN e.date_from AND e.date_to
+		AND d.duration BETWEEN p.min_duration AND p.max_duration
+		AND slot_start < CURRENT_DATE + p.booking_horizon_days + 1
+		AND slot_start > CURRENT_TIMESTAMP
+		AND s.total_appointments + h.holds < s.max_appointments
+		AND s.total_appointment_time + h.held_time + d.duration <= s.max_appointment_time
+		AND (
+			NOT EXISTS (
+				SELECT 1
+				FROM WaitlistWindows w
+				WHERE w.waitlist_entry_id = e.waitlist_entry_id
+			)
+			OR EXISTS (
+				SELECT 1
+				FROM WaitlistWindows w
+				WHERE w.waitlist_entry_id = e.waitlist_entry_id
+				AND slot_start >= slot_start::DATE + w.start_time
+				AND slot_start + d.duration <= slot_start::DATE + w.end_time
+			)
+		)
+		AND NOT EXISTS (
+			SELECT 1
+			FROM Appointment a
+			WHERE a.status NOT IN ('canceled', 'no_show')
+			AND (
+				(a.doctor_id = e.doctor_id
+					AND tstzrange(a.start_time, a.end_time) && tstzrange(slot_start - p.buffer, slot_start + d.duration + p.buffer))
+				OR (a.patient_id = e.patient_id
+					AND tstzrange(a.start_time, a.end_time) && tstzrange(slot_start, slot_start + d.duration))
+			)
+		)
+		AND NOT EXISTS (
+			SELECT 1
+			FROM TimeOffBlocks tb
+			INNER JOIN TimeOff t ON tb.time_off_id = t.time_off_id
+			WHERE t.doctor_id = e.doctor_id
+			AND t.status IN ('pending', 'approved')
+			AND tstzrange(tb.start_time, tb.end_time) && tstzrange(slot_start, slot_start + d.duration)
+		)
+		AND NOT EXISTS (
+			SELECT 1
+			FROM WaitlistOffers o
+			WHERE o.doctor_id = e.doctor_id
+			AND o.status = 'open'
+			AND o.expires_at > NOW()
+			AND tstzrange(o.start_time, o.end_time) && tstzrange(slot_start, slot_start + d.duration)
+		)
+		AND NOT EXISTS (
+			SELECT 1
+			FROM AppointmentHolds hd
+			WHERE hd.doctor_id = e.doctor_id
+			AND hd.status = 'held'
+			AND hd.expires_at > NOW()
+			AND tstzrange(hd.start_time, hd.end_time) && tstzrange(slot_start - p.buffer, slot_start + d.duration + p.buffer)
+		)
+		AND NOT EXISTS (
+			SELECT 1
+			FROM WaitlistOffers o
+			WHERE o.waitlist_entry_id = e.waitlist_entry_id
+			AND o.start_time = slot_start
+		)
+		ORDER BY slot_start
+		LIMIT 1;
+	`
+
+	// Offer a slot to a waiting entry and hold it for the patient
+	InsertOfferQuery = `
+		WITH entry AS (
+			UPDATE WaitlistEntries
+			SET status = 'offered'
+			WHERE waitlist_entry_id = $1
+			AND status = 'waiting'
+			RETURNING waitlist_entry_id, doctor_id
+		)
+		INSERT INTO WaitlistOffers (waitlist_entry_id, doctor_id, start_time, end_time, expires_at)
+		SELECT waitlist_entry_id, doctor_id, $2, $3, NOW() + make_interval(secs => $4)
+		FROM entry
+		RETURNING waitlist_offer_id;
+	`
+
+	// Get who an offer goes to and the doctor it is with
+	GetOfferNoticeQuery = `
+		SELECT p.name, p.email, d.name
+		FROM WaitlistOffers o
+		INNER JOIN WaitlistEntries e ON e.waitlist_entry_id = o.waitlist_entry_id
+		INNER JOIN Users p ON p.user_id = e.patient_id
+		INNER JOIN Users d ON d.user_id = o.doctor_id
+		WHERE o.waitlist_offer_id = $1;
+	`
+)
@@ -0,0 +1,12 @@
+package waitlist
+
+import (
+	"clinic-app/pkg/repository"
+)
+
+type repo struct{}
+
+// New creates a new instance of repository with a database connection
+func New() repository.WaitlistRepository {
+	return &repo{}
+}
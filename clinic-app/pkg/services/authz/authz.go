@@ -0,0 +1,60 @@
+package authz
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/repository"
+	"clinic-app/pkg/services/factory"
+
+	"go.uber.org/zap"
+)
+
+// Authorizer evaluates the policy. Routes are gated with Permits before anything is loaded,
+// usecases call Authorize once they know which resource the request is about.
+type Authorizer struct {
+	repo repository.AuthorizationRepository
+}
+
+// New creates an Authorizer that answers ownership questions with the given repository
+func New(repo repository.AuthorizationRepository) *Authorizer {
+	return &Authorizer{repo: repo}
+}
+
+// Permits reports whether a role may perform an action on at least some resources
+func (a *Authorizer) Permits(role string, action Action) bool {
+	_, ok := policy[action][role]
+	return ok
+}
+
+// Allowed reports whether the caller of ftx may perform an action on a resource.
+// Errors of the ownership lookups, such as ErrNotFound for a missing resource, are returned as they are.
+func (a *Authorizer) Allowed(ftx factory.Service, action Action, resourceID int) (bool, error) {
+	principal := ftx.Principal()
+	if !principal.IsAuthenticated() {
+		return false, nil
+	}
+
+	rule, ok := policy[action][principal.Role]
+	if !ok {
+		return false, nil
+	}
+	return rule(a, ftx, resourceID)
+}
+
+// Authorize is like Allowed but returns ErrForbidden when the caller may not perform the action
+func (a *Authorizer) Authorize(ftx factory.Service, action Action, resourceID int) error {
+	allowed, err := a.Allowed(ftx, action, resourceID)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		principal := ftx.Principal()
+		ftx.Logger().Info("Access denied",
+			zap.String("action", string(action)),
+			zap.Int("resource", resourceID),
+			zap.Int("user", principal.UserID),
+			zap.String("role", principal.Role),
+		)
+		return errors.ErrForbidden
+	}
+	return nil
+}
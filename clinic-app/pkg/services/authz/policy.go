@@ -0,0 +1,241 @@
+package authz
+
+import (
+	"clinic-app/pkg/services/factory"
+)
+
+// Action is something a caller can do, written as resource:verb
+type Action string
+
+const (
+	AppointmentBook       Action = "appointment:book"        // Book an appointment for yourself
+	AppointmentRead       Action = "appointment:read"        // View an appointment
+	AppointmentCancel     Action = "appointment:cancel"      // Cancel an appointment
+	AppointmentReschedule Action = "appointment:reschedule"  // Move an appointment to another time or doctor
+	AppointmentCheckIn    Action = "appointment:check-in"    // Check a patient in for their appointment
+	AppointmentStart      Action = "appointment:start"       // Start the consultation
+	AppointmentComplete   Action = "appointment:complete"    // Finish the consultation
+	AppointmentNoShow     Action = "appointment:no-show"     // Record that the patient did not come
+	PatientHistoryRead    Action = "patient-history:read"    // View the appointment history of a patient
+	DoctorRead            Action = "doctor:read"             // View doctors
+	SlotRead              Action = "slot:read"               // View the slots of a doctor
+	SlotPatientsRead      Action = "slot:read-patients"      // See which patients booked the slots of a doctor
+	WorkingHoursManage    Action = "working-hours:manage"    // Set the weekly working hours of a doctor
+	TimeOffRequest        Action = "time-off:request"        // Request time off for a doctor
+	TimeOffRead           Action = "time-off:read"           // View the time off of a doctor
+	TimeOffManage         Action = "time-off:manage"         // Withdraw a time off and handle the appointments it affects
+	TimeOffApprove        Action = "time-off:approve"        // Approve or reject time off requests
+	BookingPolicyManage   Action = "booking-policy:manage"   // Set the booking rules of the clinic and its doctors
+	WaitlistJoin          Action = "waitlist:join"           // Wait for a doctor to have room
+	WaitlistRead          Action = "waitlist:read"           // View the waitlist of a doctor
+	WaitlistLeave         Action = "waitlist:leave"          // Take an entry off the waitlist
+	WaitlistRespond       Action = "waitlist:respond"        // Accept or decline a slot offered from the waitlist
+	HoldManage            Action = "hold:manage"             // Confirm or release a held time
+	AppointmentTypeRead   Action = "appointment-type:read"   // View the appointment types
+	AppointmentTypeManage Action = "appointment-type:manage" // Add, change and deactivate appointment types
+	DoctorTypesManage     Action = "doctor-types:manage"     // Choose the appointment types a doctor offers
+	ReportRead            Action = "report:read"             // View the doctor reports
+	InvitationManage      Action = "invitation:manage"       // Invite staff and manage invitations
+	LockoutManage         Action = "lockout:manage"          // Lift login lockouts
+	AccountManage         Action = "account:manage"          // Manage your own account security
+)
+
+// Rule decides whether the caller of ftx may perform an action on the resource with the given ID
+type Rule func(a *Authorizer, ftx factory.Service, resourceID int) (bool, error)
+
+// policy grants actions to roles. A role missing from an action may never perform it,
+// otherwise its rule decides about the individual resource.
+var policy = map[Action]map[string]Rule{
+	AppointmentBook: {
+		"patient": always,
+	},
+	AppointmentRead: {
+		"patient": isAppointmentPatient,
+		"doctor":  isAppointmentDoctor,
+	},
+	AppointmentCancel: {
+		"patient": isUpcomingAppointmentPatient,
+		"doctor":  isAppointmentDoctor,
+		"admin":   always,
+	},
+	AppointmentReschedule: {
+		"patient": isUpcomingAppointmentPatient,
+		"doctor":  isAppointmentDoctor,
+		"admin":   always,
+	},
+	AppointmentCheckIn: {
+		"doctor": isAppointmentDoctor,
+		"admin":  always,
+	},
+	AppointmentStart: {
+		"doctor": isAppointmentDoctor,
+	},
+	AppointmentComplete: {
+		"doctor": isAppointmentDoctor,
+	},
+	AppointmentNoShow: {
+		"doctor": isAppointmentDoctor,
+		"admin":  always,
+	},
+	PatientHistoryRead: {
+		"patient": isSelf,
+		"doctor":  hasTreatedPatient,
+	},
+	DoctorRead: {
+		"patient": always,
+		"doctor":  always,
+		"admin":   always,
+	},
+	SlotRead: {
+		"patient": always,
+		"doctor":  always,
+		"admin":   always,
+	},
+	SlotPatientsRead: {
+		"doctor": isSelf,
+		"admin":  always,
+	},
+	WorkingHoursManage: {
+		"doctor": isSelf,
+		"admin":  always,
+	},
+	TimeOffRequest: {
+		"doctor": isSelf,
+		"admin":  always,
+	},
+	TimeOffRead: {
+		"doctor": isSelf,
+		"admin":  always,
+	},
+	TimeOffManage: {
+		"doctor": isTimeOffDoctor,
+		"admin":  always,
+	},
+	TimeOffApprove: {
+		"admin": always,
+	},
+	BookingPolicyManage: {
+		"admin": always,
+	},
+	WaitlistJoin: {
+		"patient": always,
+	},
+	WaitlistRead: {
+		"doctor": isSelf,
+		"admin":  always,
+	},
+	WaitlistLeave: {
+		"patient": isWaitlistEntryPatient,
+		"admin":   always,
+	},
+	WaitlistRespond: {
+		"patient": isWaitlistOfferPatient,
+	},
+	HoldManage: {
+		"patient": isHoldPatient,
+	},
+	AppointmentTypeRead: {
+		"patient": always,
+		"doctor":  always,
+		"admin":   always,
+	},
+	AppointmentTypeManage: {
+		"admin": always,
+	},
+	DoctorTypesManage: {
+		"doctor": isSelf,
+		"admin":  always,
+	},
+	ReportRead: {
+		"admin": always,
+	},
+	InvitationManage: {
+		"admin": always,
+	},
+	LockoutManage: {
+		"admin": always,
+	},
+	AccountManage: {
+		"patient": always,
+		"doctor":  always,
+		"admin":   always,
+	},
+}
+
+// always grants the action on every resource
+func always(a *Authorizer, ftx factory.Service, resourceID int) (bool, error) {
+	return true, nil
+}
+
+// isSelf grants the action when the resource is the caller's own user
+func isSelf(a *Authorizer, ftx factory.Service, userID int) (bool, error) {
+	return userID == ftx.Principal().UserID, nil
+}
+
+// isAppointmentPatient grants the action when the caller is the patient of the appointment
+func isAppointmentPatient(a *Authorizer, ftx factory.Service, appointmentID int) (bool, error) {
+	parties, err := a.repo.GetAppointmentParties(ftx, appointmentID)
+	if err != nil {
+		return false, err
+	}
+	return parties.PatientID == ftx.Principal().UserID, nil
+}
+
+// isUpcomingAppointmentPatient grants the action when the caller is the patient of an appointment that has not started yet
+func isUpcomingAppointmentPatient(a *Authorizer, ftx factory.Service, appointmentID int) (bool, error) {
+	parties, err := a.repo.GetAppointmentParties(ftx, appointmentID)
+	if err != nil {
+		return false, err
+	}
+	return parties.PatientID == ftx.Principal().UserID && parties.Upcoming, nil
+}
+
+// isAppointmentDoctor grants the action when the caller is the doctor of the appointment
+func isAppointmentDoctor(a *Authorizer, ftx factory.Service, appointmentID int) (bool, error) {
+	parties, err := a.repo.GetAppointmentParties(ftx, appointmentID)
+	if err != nil {
+		return false, err
+	}
+	return parties.DoctorID == ftx.Principal().UserID, nil
+}
+
+// hasTreatedPatient grants the action when the calling doctor has seen the patient in an appointment that has started
+func hasTreatedPatient(a *Authorizer, ftx factory.Service, patientID int) (bool, error) {
+	return a.repo.HasTreatedPatient(ftx, ftx.Principal().UserID, patientID)
+}
+
+// isTimeOffDoctor grants the action when the time off belongs to the calling doctor
+func isTimeOffDoctor(a *Authorizer, ftx factory.Service, timeOffID int) (bool, error) {
+	doctorID, err := a.repo.GetTimeOffDoctor(ftx, timeOffID)
+	if err != nil {
+		return false, err
+	}
+	return doctorID == ftx.Principal().UserID, nil
+}
+
+// isWaitlistEntryPatient grants the action when the waitlist entry belongs to the calling patient
+func isWaitlistEntryPatient(a *Authorizer, ftx factory.Service, entryID int) (bool, error) {
+	patientID, err := a.repo.GetWaitlistEntryPatient(ftx, entryID)
+	if err != nil {
+		return false, err
+	}
+	return patientID == ftx.Principal().UserID, nil
+}
+
+// isWaitlistOfferPatient grants the action when the waitlist offer was made to the calling patient
+func isWaitlistOfferPatient(a *Authorizer, ftx factory.Service, offerID int) (bool, error) {
+	patientID, err := a.repo.GetWaitlistOfferPatient(ftx, offerID)
+	if err != nil {
+		return false, err
+	}
+	return patientID == ftx.Principal().UserID, nil
+}
+
+// isHoldPatient grants the action when the time is held for the calling patient
+func isHoldPatient(a *Authorizer, ftx factory.Service, holdID int) (bool, error) {
+	patientID, err := a.repo.GetHoldPatient(ftx, holdID)
+	if err != nil {
+		return false, err
+	}
+	return patientID == ftx.Principal().UserID, nil
+}
@@ -0,0 +1,307 @@
+package authz
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"context"
+	"testing"
+)
+
+// The users and resources of the fake clinic the policy is checked against
+const (
+	patientID      = 10
+	otherPatientID = 11
+	doctorID       = 20
+	otherDoctorID  = 21
+	adminID        = 30
+
+	upcomingAptmt = 100 // Patient and doctor, not started yet
+	pastAptmt     = 101 // Patient and doctor, already started
+	otherAptmt    = 102 // Other patient and other doctor
+	ownTimeOff    = 200
+	otherTimeOff  = 201
+	ownEntry      = 300
+	otherEntry    = 301
+	ownOffer      = 400
+	otherOffer    = 401
+	ownHold       = 500
+	otherHold     = 501
+	missing       = 999
+	anyResource   = 0
+)
+
+var (
+	anonymous = models.Principal{}
+	patient   = models.Principal{UserID: patientID, Role: "patient"}
+	doctor    = models.Principal{UserID: doctorID, Role: "doctor"}
+	admin     = models.Principal{UserID: adminID, Role: "admin"}
+)
+
+// fakeOwnership answers the ownership questions from the fake clinic
+type fakeOwnership struct{}
+
+func (fakeOwnership) GetAppointmentParties(ftx factory.Service, appointmentId int) (models.AppointmentParties, error) {
+	switch appointmentId {
+	case upcomingAptmt:
+		return models.AppointmentParties{AppointmentID: appointmentId, PatientID: patientID, DoctorID: doctorID, Upcoming: true}, nil
+	case pastAptmt:
+		return models.AppointmentParties{AppointmentID: appointmentId, PatientID: patientID, DoctorID: doctorID}, nil
+	case otherAptmt:
+		return models.AppointmentParties{AppointmentID: appointmentId, PatientID: otherPatientID, DoctorID: otherDoctorID, Upcoming: true}, nil
+	}
+	return models.AppointmentParties{}, errors.ErrNotFound
+}
+
+func (fakeOwnership) HasTreatedPatient(ftx factory.Service, doctorId, patientId int) (bool, error) {
+	return doctorId == doctorID && patientId == patientID, nil
+}
+
+func (fakeOwnership) GetTimeOffDoctor(ftx factory.Service, timeOffId int) (int, error) {
+	return owner(timeOffId, ownTimeOff, otherTimeOff, doctorID, otherDoctorID)
+}
+
+func (fakeOwnership) GetWaitlistEntryPatient(ftx factory.Service, entryId int) (int, error) {
+	return owner(entryId, ownEntry, otherEntry, patientID, otherPatientID)
+}
+
+func (fakeOwnership) GetWaitlistOfferPatient(ftx factory.Service, offerId int) (int, error) {
+	return owner(offerId, ownOffer, otherOffer, patientID, otherPatientID)
+}
+
+func (fakeOwnership) GetHoldPatient(ftx factory.Service, holdId int) (int, error) {
+	return owner(holdId, ownHold, otherHold, patientID, otherPatientID)
+}
+
+// owner returns the user a resource of the fake clinic belongs to
+func owner(id, own, other, ownUser, otherUser int) (int, error) {
+	switch id {
+	case own:
+		return ownUser, nil
+	case other:
+		return otherUser, nil
+	}
+	return 0, errors.ErrNotFound
+}
+
+// access is one caller asking for an action on one resource
+type access struct {
+	caller   models.Principal
+	resource int
+	allowed  bool
+}
+
+func allow(caller models.Principal, resource int) access {
+	return access{caller, resource, true}
+}
+
+func deny(caller models.Principal, resource int) access {
+	return access{caller, resource, false}
+}
+
+// forAll allows every role the action on any resource
+func forAll() []access {
+	return []access{allow(patient, anyResource), allow(doctor, anyResource), allow(admin, anyResource)}
+}
+
+// adminOnly allows only admins the action
+func adminOnly(resource int) []access {
+	return []access{deny(patient, resource), deny(doctor, resource), allow(admin, resource)}
+}
+
+// doctorSelfOrAdmin allows doctors the action on themselves and admins on every doctor
+func doctorSelfOrAdmin() []access {
+	return []access{
+		deny(patient, doctorID),
+		allow(doctor, doctorID),
+		deny(doctor, otherDoctorID),
+		allow(admin, otherDoctorID),
+	}
+}
+
+var policyTests = map[Action][]access{
+	AppointmentBook: {allow(patient, anyResource), deny(doctor, anyResource), deny(admin, anyResource)},
+	AppointmentRead: {
+		allow(patient, upcomingAptmt),
+		allow(patient, pastAptmt),
+		deny(patient, otherAptmt),
+		allow(doctor, upcomingAptmt),
+		deny(doctor, otherAptmt),
+		deny(admin, upcomingAptmt),
+	},
+	AppointmentCancel: {
+		allow(patient, upcomingAptmt),
+		deny(patient, pastAptmt),
+		deny(patient, otherAptmt),
+		allow(doctor, pastAptmt),
+		deny(doctor, otherAptmt),
+		allow(admin, otherAptmt),
+	},
+	AppointmentReschedule: {
+		allow(patient, upcomingAptmt),
+		deny(patient, pastAptmt),
+		deny(patient, otherAptmt),
+		allow(doctor, pastAptmt),
+		deny(doctor, otherAptmt),
+		allow(admin, otherAptmt),
+	},
+	AppointmentCheckIn: {
+		deny(patient, upcomingAptmt),
+		allow(doctor, upcomingAptmt),
+		deny(doctor, otherAptmt),
+		allow(admin, otherAptmt),
+	},
+	AppointmentStart: {
+		deny(patient, upcomingAptmt),
+		allow(doctor, upcomingAptmt),
+		deny(doctor, otherAptmt),
+		deny(admin, upcomingAptmt),
+	},
+	AppointmentComplete: {
+		deny(patient, upcomingAptmt),
+		allow(doctor, upcomingAptmt),
+		deny(doctor, otherAptmt),
+		deny(admin, upcomingAptmt),
+	},
+	AppointmentNoShow: {
+		deny(patient, pastAptmt),
+		allow(doctor, pastAptmt),
+		deny(doctor, otherAptmt),
+		allow(admin, otherAptmt),
+	},
+	PatientHistoryRead: {
+		allow(patient, patientID),
+		deny(patient, otherPatientID),
+		allow(doctor, patientID),
+		deny(doctor, otherPatientID),
+		deny(admin, patientID),
+	},
+	DoctorRead:         forAll(),
+	SlotRead:           forAll(),
+	SlotPatientsRead:   doctorSelfOrAdmin(),
+	WorkingHoursManage: doctorSelfOrAdmin(),
+	TimeOffRequest:     doctorSelfOrAdmin(),
+	TimeOffRead:        doctorSelfOrAdmin(),
+	TimeOffManage: {
+		deny(patient, ownTimeOff),
+		allow(doctor, ownTimeOff),
+		deny(doctor, otherTimeOff),
+		allow(admin, otherTimeOff),
+	},
+	TimeOffApprove:      adminOnly(ownTimeOff),
+	BookingPolicyManage: adminOnly(anyResource),
+	WaitlistJoin:        {allow(patient, anyResource), deny(doctor, anyResource), deny(admin, anyResource)},
+	WaitlistRead:        doctorSelfOrAdmin(),
+	WaitlistLeave: {
+		allow(patient, ownEntry),
+		deny(patient, otherEntry),
+		deny(doctor, ownEntry),
+		allow(admin, otherEntry),
+	},
+	WaitlistRespond: {
+		allow(patient, ownOffer),
+		deny(patient, otherOffer),
+		deny(doctor, ownOffer),
+		deny(admin, ownOffer),
+	},
+	HoldManage: {
+		allow(patient, ownHold),
+		deny(patient, otherHold),
+		deny(doctor, ownHold),
+		deny(admin, ownHold),
+	},
+	AppointmentTypeRead:   forAll(),
+	AppointmentTypeManage: adminOnly(anyResource),
+	DoctorTypesManage:     doctorSelfOrAdmin(),
+	ReportRead:            adminOnly(anyResource),
+	InvitationManage:      adminOnly(anyResource),
+	LockoutManage:         adminOnly(anyResource),
+	AccountManage:         forAll(),
+}
+
+// service returns a factory service scoped to the caller, the fake ownership needs no database
+func service(t *testing.T, caller models.Principal) factory.Service {
+	t.Helper()
+	ftx, err := factory.NewFactory(nil, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ftx.WithPrincipal(caller)
+}
+
+func TestPolicy(t *testing.T) {
+	authorizer := New(fakeOwnership{})
+
+	for action, cases := range policyTests {
+		for _, c := range cases {
+			allowed, err := authorizer.Allowed(service(t, c.caller), action, c.resource)
+			if err != nil {
+				t.Errorf("%s by %s on %d: %v", action, c.caller.Role, c.resource, err)
+				continue
+			}
+			if allowed != c.allowed {
+				t.Errorf("%s by %s on %d: allowed = %v, want %v", action, c.caller.Role, c.resource, allowed, c.allowed)
+			}
+
+			// Permits gates the routes on the role alone
+			if c.allowed && !authorizer.Permits(c.caller.Role, action) {
+				t.Errorf("%s: route is closed to %s", action, c.caller.Role)
+			}
+		}
+
+		// Anonymous callers never get past the policy
+		allowed, err := authorizer.Allowed(service(t, anonymous), action, anyResource)
+		if err != nil || allowed {
+			t.Errorf("%s by anonymous caller: allowed = %v, err = %v", action, allowed, err)
+		}
+	}
+}
+
+func TestPolicyCoversEveryAction(t *testing.T) {
+	for action := range policy {
+		if _, ok := policyTests[action]; !ok {
+			t.Errorf("%s has no test cases", action)
+		}
+	}
+	for action := range policyTests {
+		if _, ok := policy[action]; !ok {
+			t.Errorf("%s is tested but not in the policy", action)
+		}
+	}
+}
+
+func TestPolicyCoversEveryRole(t *testing.T) {
+	for action, cases := range policyTests {
+		roles := map[string]bool{}
+		for _, c := range cases {
+			roles[c.caller.Role] = true
+		}
+		for _, role := range []string{"patient", "doctor", "admin"} {
+			if !roles[role] {
+				t.Errorf("%s is not tested for %s", action, role)
+			}
+		}
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	authorizer := New(fakeOwnership{})
+
+	if err := authorizer.Authorize(service(t, patient), AppointmentCancel, upcomingAptmt); err != nil {
+		t.Errorf("patient canceling their appointment: %v", err)
+	}
+	if err := authorizer.Authorize(service(t, patient), AppointmentCancel, otherAptmt); err != errors.ErrForbidden {
+		t.Errorf("patient canceling another patient's appointment: err = %v, want ErrForbidden", err)
+	}
+	if err := authorizer.Authorize(service(t, anonymous), DoctorRead, anyResource); err != errors.ErrForbidden {
+		t.Errorf("anonymous caller: err = %v, want ErrForbidden", err)
+	}
+
+	// Missing resources are reported as such rather than as forbidden
+	if err := authorizer.Authorize(service(t, patient), AppointmentRead, missing); err != errors.ErrNotFound {
+		t.Errorf("missing appointment: err = %v, want ErrNotFound", err)
+	}
+	if err := authorizer.Authorize(service(t, doctor), TimeOffManage, missing); err != errors.ErrNotFound {
+		t.Errorf("missing time off: err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,41 @@
+package clinictime
+
+import "time"
+
+// location is the timezone the clinic works in, UTC until SetUpLocation is called
+var location = time.UTC
+
+// SetUpLocation sets the timezone the clinic works in, it is called once at startup
+func SetUpLocation(loc *time.Location) {
+	location = loc
+}
+
+// Location returns the timezone the clinic works in
+func Location() *time.Location {
+	return location
+}
+
+// In returns t as a wall-clock time of the clinic
+func In(t time.Time) time.Time {
+	return t.In(location)
+}
+
+// Day returns the start of the clinic day t falls on.
+// Days are taken from the clinic's wall clock, so an appointment at 00:30 in the clinic
+// belongs to that day even when the caller sent the time in a zone where it is still the day before.
+func Day(t time.Time) time.Time {
+	t = t.In(location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// Today returns the start of the current clinic day
+func Today() time.Time {
+	return Day(time.Now())
+}
+
+// EndOfDay returns the start of the clinic day after the one t falls on, which is 23, 24 or 25 hours
+// after Day(t) depending on daylight saving time
+func EndOfDay(t time.Time) time.Time {
+	day := Day(t)
+	return time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, day.Location())
+}
@@ -3,6 +3,7 @@ package factory
 import (
 	"clinic-app/internal/constants"
 	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
 	"clinic-app/pkg/services/transaction"
 	"context"
 	"database/sql"
@@ -16,6 +17,8 @@ type Service interface {
 	PSQL() *sql.DB
 	TransactionManager() *transaction.TransactionManager
 	Context() context.Context
+	Principal() models.Principal
+	WithPrincipal(principal models.Principal) Service
 }
 
 // serviceImpl is the concrete implementation of the Service interface.
@@ -24,6 +27,8 @@ type ServiceImpl struct {
 	db     *sql.DB
 	trx    *transaction.TransactionManager
 	ctx    context.Context
+	// principal is the authenticated caller, it is empty until the auth middleware has run
+	principal models.Principal
 }
 
 var deps *ServiceImpl
@@ -108,3 +113,19 @@ func (s *ServiceImpl) TransactionManager() *transaction.TransactionManager {
 func (s *ServiceImpl) Context() context.Context {
 	return s.ctx
 }
+
+// Principal returns the authenticated caller of the request
+func (s *ServiceImpl) Principal() models.Principal {
+	return s.principal
+}
+
+// WithPrincipal returns a copy of the service scoped to the given caller
+func (s *ServiceImpl) WithPrincipal(principal models.Principal) Service {
+	return &ServiceImpl{
+		db:        s.db,
+		logger:    s.logger.With(zap.Int("user_id", principal.UserID), zap.String("role", principal.Role)),
+		trx:       s.trx,
+		ctx:       s.ctx,
+		principal: principal,
+	}
+}
@@ -0,0 +1,53 @@
+package lockout
+
+import (
+	"context"
+	"time"
+)
+
+// Limiter tracks failed attempts per key, such as a username or a client IP, and decides when the next attempt is allowed.
+// The in-memory implementation only sees the attempts made against its own instance, deployments with several
+// instances need an implementation backed by a shared store.
+type Limiter interface {
+	// Allow returns how long the key has to wait before its next attempt, zero when it may try now
+	Allow(ctx context.Context, key string) (time.Duration, error)
+	// Fail records a failed attempt and returns the resulting state of the key
+	Fail(ctx context.Context, key string) (Status, error)
+	// Reset forgets all failed attempts of the key, unlocking it
+	Reset(ctx context.Context, key string) error
+}
+
+// Status is the state of a key after a failed attempt
+type Status struct {
+	Failures   int           // Failed attempts within the failure window
+	RetryAfter time.Duration // How long the key has to wait before its next attempt
+	Locked     bool          // The threshold was reached and the key is locked out
+}
+
+// Policy controls how quickly a key is slowed down and locked out
+type Policy struct {
+	Threshold       int           // Failures after which the key is locked out
+	BaseDelay       time.Duration // Wait after the first failure, doubled with every further failure
+	MaxDelay        time.Duration // Upper bound of the backoff below the threshold
+	LockoutDuration time.Duration // How long a key stays locked once the threshold is reached
+	Window          time.Duration // Failures are forgotten once no attempt failed for this long
+}
+
+// Delay returns how long a key has to wait after its n-th consecutive failure and whether it is locked out.
+func (p Policy) Delay(failures int) (time.Duration, bool) {
+	if failures <= 0 {
+		return 0, false
+	}
+	if p.Threshold > 0 && failures >= p.Threshold {
+		return p.LockoutDuration, true
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay, false
+}
@@ -0,0 +1,94 @@
+package lockout
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// entry is the state of one key
+type entry struct {
+	failures     int
+	lastFailure  time.Time
+	blockedUntil time.Time
+}
+
+// MemoryLimiter keeps the attempt counters in process memory.
+type MemoryLimiter struct {
+	mu         sync.Mutex
+	policy     Policy
+	entries    map[string]*entry
+	lastPruned time.Time
+}
+
+// NewMemoryLimiter creates an in-memory limiter applying the given policy.
+func NewMemoryLimiter(policy Policy) *MemoryLimiter {
+	return &MemoryLimiter{
+		policy:  policy,
+		entries: make(map[string]*entry),
+	}
+}
+
+// Allow returns how long the key has to wait before its next attempt.
+func (l *MemoryLimiter) Allow(ctx context.Context, key string) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.entries[key]
+	if !ok {
+		return 0, nil
+	}
+	if wait := time.Until(e.blockedUntil); wait > 0 {
+		return wait, nil
+	}
+	return 0, nil
+}
+
+// Fail records a failed attempt of the key.
+func (l *MemoryLimiter) Fail(ctx context.Context, key string) (Status, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	e, ok := l.entries[key]
+	if !ok || l.expired(e, now) {
+		e = &entry{}
+		l.entries[key] = e
+	}
+
+	e.failures++
+	e.lastFailure = now
+	delay, locked := l.policy.Delay(e.failures)
+	e.blockedUntil = now.Add(delay)
+
+	l.prune(now)
+	return Status{Failures: e.failures, RetryAfter: delay, Locked: locked}, nil
+}
+
+// Reset forgets the failed attempts of the key.
+func (l *MemoryLimiter) Reset(ctx context.Context, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.entries, key)
+	return nil
+}
+
+// expired reports whether an entry is no longer blocked and its failures fell out of the window
+func (l *MemoryLimiter) expired(e *entry, now time.Time) bool {
+	return now.After(e.blockedUntil) && now.Sub(e.lastFailure) > l.policy.Window
+}
+
+// prune drops entries that can no longer matter, callers must hold the lock.
+func (l *MemoryLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPruned) < time.Minute {
+		return
+	}
+	l.lastPruned = now
+
+	for key, e := range l.entries {
+		if l.expired(e, now) {
+			delete(l.entries, key)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package password
+
+import (
+	"crypto/subtle"
+	"errors"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Hasher hashes and verifies user passwords with bcrypt.
+type Hasher struct {
+	cost      int
+	dummyOnce sync.Once
+	dummyHash []byte
+}
+
+// NewHasher creates a new Hasher with the given bcrypt cost, clamped to the range bcrypt accepts.
+func NewHasher(cost int) *Hasher {
+	if cost < bcrypt.MinCost {
+		cost = bcrypt.DefaultCost
+	}
+	if cost > bcrypt.MaxCost {
+		cost = bcrypt.MaxCost
+	}
+	return &Hasher{cost: cost}
+}
+
+// Hash returns the bcrypt hash of a plaintext password.
+func (h *Hasher) Hash(plain string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(plain), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Verify reports whether the plaintext password matches the stored value.
+// Stored values that are not bcrypt hashes are legacy plaintext credentials and are compared in constant time.
+func (h *Hasher) Verify(stored, plain string) (bool, error) {
+	if !IsHash(stored) {
+		return subtle.ConstantTimeCompare([]byte(stored), []byte(plain)) == 1, nil
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(stored), []byte(plain))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// NeedsRehash reports whether the stored value is plaintext or was hashed with a different cost.
+func (h *Hasher) NeedsRehash(stored string) bool {
+	cost, err := bcrypt.Cost([]byte(stored))
+	if err != nil {
+		return true
+	}
+	return cost != h.cost
+}
+
+// IsHash reports whether the stored value is a bcrypt hash.
+func IsHash(stored string) bool {
+	_, err := bcrypt.Cost([]byte(stored))
+	return err == nil
+}
+
+// SimulateVerify spends the same time as verifying a real hash, so unknown usernames cannot be told apart by response time.
+func (h *Hasher) SimulateVerify(plain string) {
+	h.dummyOnce.Do(func() {
+		h.dummyHash, _ = bcrypt.GenerateFromPassword([]byte("clinic-app-dummy-password"), h.cost)
+	})
+	_ = bcrypt.CompareHashAndPassword(h.dummyHash, []byte(plain))
+}
@@ -0,0 +1,104 @@
+package recurrence
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxOccurrences is the most occurrences a rule may produce, a year of weekly visits
+const MaxOccurrences = 52
+
+// ErrInvalidRule is returned for rules outside the supported subset
+var ErrInvalidRule = errors.New("recurrence: unsupported or invalid rule")
+
+// Rule is the subset of an RFC 5545 RRULE the clinic books: weekly or every other week,
+// ending after a number of occurrences or on a day.
+type Rule struct {
+	Interval int        // Weeks between two occurrences, 1 or 2
+	Count    int        // Number of occurrences, zero when Until is set
+	Until    *time.Time // Last day an occurrence may start on, nil when Count is set
+}
+
+// Parse reads a rule such as "FREQ=WEEKLY;INTERVAL=2;COUNT=6" or "RRULE:FREQ=WEEKLY;UNTIL=20241231".
+// FREQ must be WEEKLY and exactly one of COUNT and UNTIL must be given.
+func Parse(rule string) (Rule, error) {
+	r := Rule{Interval: 1}
+	freq := false
+
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	for _, part := range strings.Split(rule, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Rule{}, ErrInvalidRule
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			if strings.ToUpper(value) != "WEEKLY" {
+				return Rule{}, ErrInvalidRule
+			}
+			freq = true
+
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 || interval > 2 {
+				return Rule{}, ErrInvalidRule
+			}
+			r.Interval = interval
+
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 || count > MaxOccurrences {
+				return Rule{}, ErrInvalidRule
+			}
+			r.Count = count
+
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return Rule{}, ErrInvalidRule
+			}
+			r.Until = &until
+
+		default:
+			return Rule{}, ErrInvalidRule
+		}
+	}
+
+	if !freq || (r.Count == 0) == (r.Until == nil) {
+		return Rule{}, ErrInvalidRule
+	}
+	return r, nil
+}
+
+// Occurrences returns the starts of the occurrences of the rule, the first one being start.
+// A rule ending on a day stops at MaxOccurrences, Parse already limits COUNT.
+func (r Rule) Occurrences(start time.Time) []time.Time {
+	var starts []time.Time
+	for i := 0; i < MaxOccurrences; i++ {
+		next := start.AddDate(0, 0, 7*r.Interval*i)
+		if r.Count > 0 && i >= r.Count {
+			break
+		}
+		day := time.Date(next.Year(), next.Month(), next.Day(), 0, 0, 0, 0, time.UTC)
+		if r.Until != nil && day.After(*r.Until) {
+			break
+		}
+		starts = append(starts, next)
+	}
+	return starts
+}
+
+// parseUntil reads UNTIL as a day (20241231) or a UTC date-time (20241231T235959Z), only the day is kept
+func parseUntil(value string) (time.Time, error) {
+	if day, err := time.Parse("20060102", value); err == nil {
+		return day, nil
+	}
+	until, err := time.Parse("20060102T150405Z", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return until.Truncate(24 * time.Hour), nil
+}
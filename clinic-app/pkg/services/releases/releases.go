@@ -0,0 +1,35 @@
+// Package releases tells the waitlist promotion when appointments are cancelled or the capacity of a doctor changes,
+// so the freed time is offered to waiting patients right away instead of on the next scheduled promotion.
+package releases
+
+import (
+	"clinic-app/internal/constants"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Notifier carries freed capacity to the waitlist promotion. A nil Notifier drops every release.
+type Notifier chan models.ReleasedCapacity
+
+// New creates a Notifier that queues up to buffer releases while the promotion is busy
+func New(buffer int) Notifier {
+	return make(Notifier, buffer)
+}
+
+// Release reports that a doctor has room on a clinic day again, a zero date stands for every day of the doctor
+// and a zero doctor for every doctor. It never blocks, a release that does not fit the queue is left to the
+// scheduled promotion.
+func (n Notifier) Release(ftx factory.Service, doctorId int, date time.Time) {
+	traceparent, _ := ftx.Context().Value(constants.TraceparentHeader).(string)
+	select {
+	case n <- models.ReleasedCapacity{DoctorID: doctorId, Date: date, TraceParent: traceparent}:
+	default:
+		if n != nil {
+			ftx.Logger().Warn("Waitlist promotion is busy, the freed capacity waits for the next scheduled run",
+				zap.Int("DoctorID", doctorId), zap.Time("Date", date))
+		}
+	}
+}
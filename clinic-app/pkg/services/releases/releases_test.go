@@ -0,0 +1,52 @@
+package releases
+
+import (
+	"clinic-app/internal/constants"
+	"clinic-app/pkg/services/factory"
+	"context"
+	"testing"
+	"time"
+)
+
+func service(t *testing.T) factory.Service {
+	t.Helper()
+	ctx := context.WithValue(context.Background(), constants.TraceparentHeader, "00-0000000000000001-00000001-01")
+	ftx, err := factory.NewFactory(nil, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ftx
+}
+
+func TestReleaseQueuesCapacity(t *testing.T) {
+	released := New(1)
+	day := time.Date(2024, 11, 3, 0, 0, 0, 0, time.UTC)
+	released.Release(service(t), 20, day)
+
+	capacity := <-released
+	if capacity.DoctorID != 20 || !capacity.Date.Equal(day) {
+		t.Errorf("released doctor %d on %v, want doctor 20 on %v", capacity.DoctorID, capacity.Date, day)
+	}
+	if capacity.TraceParent != "00-0000000000000001-00000001-01" {
+		t.Errorf("traceparent = %q, want the one of the request", capacity.TraceParent)
+	}
+}
+
+func TestReleaseNeverBlocks(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var none Notifier
+		none.Release(service(t), 20, time.Time{}) // Dropped without a promotion
+
+		full := New(1)
+		full.Release(service(t), 20, time.Time{})
+		full.Release(service(t), 21, time.Time{}) // Left to the scheduled promotion
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Release blocked")
+	}
+}
@@ -0,0 +1,143 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"clinic-app/internal/config"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// Key is one signing or verification key, identified by the kid token header.
+type Key struct {
+	ID     string
+	Method jwt.SigningMethod
+	sign   interface{} // Key used for signing, nil for verification-only keys
+	verify interface{} // Key used for verification
+	public crypto.PublicKey
+}
+
+// KeySet holds the active signing key and every key accepted for verification.
+type KeySet struct {
+	active *Key
+	keys   map[string]*Key
+}
+
+// NewKeySet parses the configured keys and selects the active signing key.
+func NewKeySet(cfg config.JWTConfig) (*KeySet, error) {
+	set := &KeySet{keys: make(map[string]*Key)}
+
+	for _, kc := range cfg.Keys {
+		if _, exists := set.keys[kc.ID]; exists {
+			return nil, fmt.Errorf("duplicate JWT key id %q", kc.ID)
+		}
+		key, err := parseKey(kc)
+		if err != nil {
+			return nil, fmt.Errorf("JWT key %q: %w", kc.ID, err)
+		}
+		set.keys[kc.ID] = key
+	}
+
+	active, ok := set.keys[cfg.ActiveKeyID]
+	if !ok {
+		return nil, fmt.Errorf("active JWT key %q is not configured", cfg.ActiveKeyID)
+	}
+	if active.sign == nil {
+		return nil, fmt.Errorf("active JWT key %q has no private part", cfg.ActiveKeyID)
+	}
+	if active.Method.Alg() != cfg.Algorithm {
+		return nil, fmt.Errorf("active JWT key %q is a %s key, not %s", cfg.ActiveKeyID, active.Method.Alg(), cfg.Algorithm)
+	}
+	set.active = active
+
+	return set, nil
+}
+
+// Active returns the key new tokens are signed with.
+func (s *KeySet) Active() *Key {
+	return s.active
+}
+
+// Lookup returns the verification key with the given kid.
+func (s *KeySet) Lookup(kid string) (*Key, bool) {
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// parseKey turns a configured secret or PEM block into a Key.
+func parseKey(kc config.SigningKeyConfig) (*Key, error) {
+	if len(kc.Secret) > 0 {
+		if len(kc.Secret) < 32 {
+			return nil, fmt.Errorf("HMAC secrets must be at least 32 bytes")
+		}
+		return &Key{ID: kc.ID, Method: jwt.SigningMethodHS256, sign: kc.Secret, verify: kc.Secret}, nil
+	}
+
+	block, _ := pem.Decode(kc.PEM)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		priv, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return privateKey(kc.ID, priv)
+
+	case "PRIVATE KEY":
+		priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return privateKey(kc.ID, priv)
+
+	case "RSA PUBLIC KEY":
+		pub, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return publicKey(kc.ID, pub)
+
+	case "PUBLIC KEY":
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return publicKey(kc.ID, pub)
+	}
+
+	return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+}
+
+// privateKey builds a signing key from a parsed private key.
+func privateKey(kid string, priv interface{}) (*Key, error) {
+	switch k := priv.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("RSA keys must be at least 2048 bits")
+		}
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, sign: k, verify: &k.PublicKey, public: &k.PublicKey}, nil
+	case ed25519.PrivateKey:
+		pub := k.Public().(ed25519.PublicKey)
+		return &Key{ID: kid, Method: jwt.SigningMethodEdDSA, sign: k, verify: pub, public: pub}, nil
+	}
+	return nil, fmt.Errorf("unsupported private key type %T", priv)
+}
+
+// publicKey builds a verification-only key from a parsed public key.
+func publicKey(kid string, pub interface{}) (*Key, error) {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, verify: k, public: k}, nil
+	case ed25519.PublicKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodEdDSA, verify: k, public: k}, nil
+	}
+	return nil, fmt.Errorf("unsupported public key type %T", pub)
+}
@@ -0,0 +1,87 @@
+package token
+
+import (
+	"sync"
+	"time"
+)
+
+// RevocationCache keeps an in-process view of the revocation list so that
+// not every request needs a database round trip.
+//
+// Revoked token IDs are remembered until the token would have expired anyway.
+// Token IDs found not to be revoked are only trusted for a short time, so a
+// revocation made by another instance is picked up quickly.
+type RevocationCache struct {
+	mu         sync.Mutex
+	revoked    map[string]time.Time // jti -> token expiry
+	valid      map[string]time.Time // jti -> time until which the lookup is trusted
+	validTTL   time.Duration
+	lastPruned time.Time
+}
+
+// NewRevocationCache creates a new cache trusting negative lookups for validTTL.
+func NewRevocationCache(validTTL time.Duration) *RevocationCache {
+	return &RevocationCache{
+		revoked:  make(map[string]time.Time),
+		valid:    make(map[string]time.Time),
+		validTTL: validTTL,
+	}
+}
+
+// Lookup returns whether the token is revoked and whether the cache knows the answer.
+func (c *RevocationCache) Lookup(jti string) (revoked bool, known bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if _, ok := c.revoked[jti]; ok {
+		return true, true
+	}
+	if until, ok := c.valid[jti]; ok && now.Before(until) {
+		return false, true
+	}
+	return false, false
+}
+
+// MarkRevoked records a revoked token until its expiry.
+func (c *RevocationCache) MarkRevoked(jti string, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.valid, jti)
+	c.revoked[jti] = expiresAt
+	c.prune()
+}
+
+// MarkValid records that the token was not revoked when last checked.
+func (c *RevocationCache) MarkValid(jti string) {
+	if c.validTTL <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.valid[jti] = time.Now().Add(c.validTTL)
+	c.prune()
+}
+
+// prune drops entries that can no longer matter, callers must hold the lock.
+func (c *RevocationCache) prune() {
+	now := time.Now()
+	if now.Sub(c.lastPruned) < time.Minute {
+		return
+	}
+	c.lastPruned = now
+
+	for jti, expiresAt := range c.revoked {
+		if now.After(expiresAt) {
+			delete(c.revoked, jti)
+		}
+	}
+	for jti, until := range c.valid {
+		if now.After(until) {
+			delete(c.valid, jti)
+		}
+	}
+}
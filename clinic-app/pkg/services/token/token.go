@@ -0,0 +1,128 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"clinic-app/pkg/domain/models"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+)
+
+// Manager signs and parses access tokens and generates refresh tokens.
+type Manager struct {
+	keys       *KeySet
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewManager creates a new Manager signing with the given keys and issuing tokens with the given lifetimes.
+func NewManager(keys *KeySet, accessTTL, refreshTTL time.Duration) *Manager {
+	return &Manager{
+		keys:       keys,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}
+}
+
+// Keys returns the signing and verification keys.
+func (m *Manager) Keys() *KeySet {
+	return m.keys
+}
+
+// AccessTTL returns the lifetime of access tokens.
+func (m *Manager) AccessTTL() time.Duration {
+	return m.accessTTL
+}
+
+// RefreshTTL returns the lifetime of refresh tokens.
+func (m *Manager) RefreshTTL() time.Duration {
+	return m.refreshTTL
+}
+
+// NewJTI returns a new unique token ID.
+func NewJTI() string {
+	return uuid.NewString()
+}
+
+// SignAccessToken creates a signed access token for the user with the given token ID and expiry.
+func (m *Manager) SignAccessToken(userID int, role, jti string, expiresAt time.Time) (string, error) {
+	return m.sign(&models.Claims{
+		UserID: userID,
+		Role:   role,
+		StandardClaims: jwt.StandardClaims{
+			Id:        jti,
+			IssuedAt:  time.Now().Unix(),
+			ExpiresAt: expiresAt.Unix(), // Token expiration
+		},
+	})
+}
+
+// SignChallengeToken creates a signed token for the second step of a login.
+// The purpose claim keeps it from being accepted as an access token.
+func (m *Manager) SignChallengeToken(userID int, role, purpose, jti string, expiresAt time.Time) (string, error) {
+	return m.sign(&models.Claims{
+		UserID:  userID,
+		Role:    role,
+		Purpose: purpose,
+		StandardClaims: jwt.StandardClaims{
+			Id:        jti,
+			IssuedAt:  time.Now().Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+	})
+}
+
+// sign signs claims with the active key and names it in the kid header so verifiers can pick the right key
+func (m *Manager) sign(claims *models.Claims) (string, error) {
+	active := m.keys.Active()
+	token := jwt.NewWithClaims(active.Method, claims)
+	token.Header["kid"] = active.ID
+	return token.SignedString(active.sign)
+}
+
+// Parse validates a signed token and returns its claims, callers check the purpose.
+func (m *Manager) Parse(tokenString string) (*models.Claims, error) {
+	claims := &models.Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		// Pick the key named by the kid header
+		kid, _ := token.Header["kid"].(string)
+		key, ok := m.keys.Lookup(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		// Only accept the algorithm that belongs to the key, never the one the token claims
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		return key.verify, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid || claims.Id == "" {
+		return nil, fmt.Errorf("invalid token")
+	}
+	return claims, nil
+}
+
+// NewOpaqueToken generates a random token, such as a refresh token, and the hash under which it is stored.
+func NewOpaqueToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	plain := base64.RawURLEncoding.EncodeToString(buf)
+	return plain, HashOpaqueToken(plain), nil
+}
+
+// HashOpaqueToken returns the hash under which an opaque token is stored, the plain token is never persisted.
+func HashOpaqueToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
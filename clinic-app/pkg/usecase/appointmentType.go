@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+)
+
+// AppointmentTypeUsecase defines methods for managing appointment types and the types doctors offer.
+type AppointmentTypeUsecase interface {
+	Types(ftx factory.Service) ([]models.AppointmentType, error)
+	CreateType(ftx factory.Service, aptmtType models.AppointmentType) (models.AppointmentType, error)
+	UpdateType(ftx factory.Service, aptmtType models.AppointmentType) (models.AppointmentType, error)
+	DeactivateType(ftx factory.Service, typeId int) error
+	DoctorTypes(ftx factory.Service, doctorId int) ([]models.AppointmentType, error)
+	SetDoctorTypes(ftx factory.Service, doctorId int, typeIds []int) error
+}
@@ -3,13 +3,27 @@ package usecase
 import (
 	"clinic-app/pkg/domain/models"
 	"clinic-app/pkg/services/factory"
+	"time"
 )
 
 // AppointmentUsecase defines methods for managing appointments.
 type AppointmentUsecase interface {
 	Book(ftx factory.Service, aptmt models.BookAppointment) error
 	ViewAppointment(ftx factory.Service, appointmentId int) (models.Appointment, error)
-	PatientHistoryForDoctor(ftx factory.Service, patientId int) ([]models.Appointment, error)
-	PatientHistory(ftx factory.Service) ([]models.Appointment, error)
-	Cancel(ftx factory.Service, appointmentId int) error
+	PatientHistoryForDoctor(ftx factory.Service, patientId int, includeCanceled bool) ([]models.Appointment, error)
+	PatientHistory(ftx factory.Service, includeCanceled bool) ([]models.Appointment, error)
+	Cancel(ftx factory.Service, cancel models.CancelAppointment) error
+	Reschedule(ftx factory.Service, reschedule models.RescheduleAppointment) (int, error)
+	BookSeries(ftx factory.Service, series models.BookSeries) (models.AppointmentSeries, []models.SeriesOccurrence, error)
+	CancelSeries(ftx factory.Service, cancel models.CancelAppointment, scope string) ([]models.AffectedAppointment, error)
+	RescheduleSeries(ftx factory.Service, reschedule models.RescheduleAppointment, scope string) ([]models.AffectedAppointment, error)
+	Hold(ftx factory.Service, aptmt models.BookAppointment) (models.AppointmentHold, error)
+	ConfirmHold(ftx factory.Service, holdId int) (int, error)
+	ReleaseHold(ftx factory.Service, holdId int) error
+	ReapHolds(ftx factory.Service) (int64, error)
+	CheckIn(ftx factory.Service, appointmentId int) error
+	Start(ftx factory.Service, appointmentId int) error
+	Complete(ftx factory.Service, appointmentId int) error
+	MarkNoShow(ftx factory.Service, appointmentId int) error
+	SweepNoShows(ftx factory.Service, grace time.Duration) (int64, error)
 }
@@ -1,14 +1,22 @@
 package appointments
 
 import (
+	"clinic-app/pkg/domain/errors"
 	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/clinictime"
 	"clinic-app/pkg/services/factory"
+	"time"
 
 	"go.uber.org/zap"
 )
 
 // Book schedules a new appointment.
 func (uc *aptmtUsecaseImpl) Book(ftx factory.Service, aptmt models.BookAppointment) error {
+	aptmt.Date = clinictime.Day(aptmt.StartTime) // The clinic day, whatever zone the caller sent the times in
+	if err := uc.applyType(ftx, &aptmt); err != nil {
+		return err
+	}
+
 	// Call the repository method to book the appointment
 	err := uc.repo.BookAppointment(ftx, aptmt)
 	if err != nil {
@@ -20,3 +28,26 @@ func (uc *aptmtUsecaseImpl) Book(ftx factory.Service, aptmt models.BookAppointme
 	// Return nil if no error occurred
 	return nil
 }
+
+// applyType checks that the doctor offers the type of a booking and that the booking lasts as long as the type allows.
+// A booking without an end gets the default duration of its type.
+func (uc *aptmtUsecaseImpl) applyType(ftx factory.Service, aptmt *models.BookAppointment) error {
+	if aptmt.TypeID == nil {
+		return nil
+	}
+
+	aptmtType, err := uc.typeRepo.GetOfferedType(ftx, aptmt.DoctorID, *aptmt.TypeID)
+	if err != nil {
+		return err
+	}
+	if aptmt.EndTime.IsZero() {
+		aptmt.EndTime = aptmt.StartTime.Add(time.Duration(aptmtType.DefaultDurationMinutes) * time.Minute)
+	}
+
+	duration := aptmt.EndTime.Sub(aptmt.StartTime)
+	if duration < time.Duration(aptmtType.MinDurationMinutes)*time.Minute ||
+		duration > time.Duration(aptmtType.MaxDurationMinutes)*time.Minute {
+		return errors.ErrTypeDuration
+	}
+	return nil
+}
@@ -1,21 +1,42 @@
 package appointments
 
 import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/clinictime"
 	"clinic-app/pkg/services/factory"
 
 	"go.uber.org/zap"
 )
 
-// Cancel removes an existing appointment based on its ID.
-func (uc *aptmtUsecaseImpl) Cancel(ftx factory.Service, aptmtId int) error {
+// Cancel marks an existing appointment as canceled, recording who canceled it and why.
+func (uc *aptmtUsecaseImpl) Cancel(ftx factory.Service, cancel models.CancelAppointment) error {
+	// Doctors may only cancel their own appointments, patients only their own upcoming ones
+	if err := uc.authorizer.Authorize(ftx, authz.AppointmentCancel, cancel.AppointmentID); err != nil {
+		return err
+	}
+
+	// Only appointments that have not started yet can be canceled
+	aptmt, err := uc.repo.GetAppointmentById(ftx, cancel.AppointmentID)
+	if err != nil {
+		return err
+	}
+	if !canTransition(aptmt.Status, models.StatusCanceled) {
+		return &errors.InvalidTransitionError{From: aptmt.Status, To: models.StatusCanceled}
+	}
+
 	// Call the repository method to cancel the appointment
-	err := uc.repo.CancelAppointment(ftx, aptmtId)
+	err = uc.repo.CancelAppointment(ftx, cancel, aptmt.Status)
 	if err != nil {
 		// Log an error if the cancellation fails
 		ftx.Logger().Error("Error cancelling appointment", zap.Error(err))
 		return err
 	}
 
+	// Offer the freed time to the waitlist
+	uc.released.Release(ftx, aptmt.DoctorID, clinictime.Day(aptmt.StartTime))
+
 	// Return nil if no error occurred
 	return nil
 }
@@ -0,0 +1,54 @@
+package appointments
+
+import (
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/clinictime"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/services/releases"
+	"context"
+	"testing"
+	"time"
+)
+
+// cancelingAppointments cancels the appointment of fakeAppointments
+type cancelingAppointments struct {
+	*fakeAppointments
+}
+
+func (r cancelingAppointments) CancelAppointment(ftx factory.Service, cancel models.CancelAppointment, from string) error {
+	r.original.Status = models.StatusCanceled
+	return nil
+}
+
+func TestCancelReleasesTheDayToTheWaitlist(t *testing.T) {
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	repo := cancelingAppointments{&fakeAppointments{original: models.Appointment{
+		AppointmentID: 1,
+		PatientID:     patientID,
+		DoctorID:      doctorID,
+		StartTime:     start,
+		EndTime:       start.Add(30 * time.Minute),
+		Status:        models.StatusScheduled,
+	}}}
+	released := releases.New(1)
+	uc := New(repo, fakeTypes{}, authz.New(fakeOwnership{}), time.Minute, released)
+
+	ftx, err := factory.NewFactory(nil, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = uc.Cancel(ftx.WithPrincipal(models.Principal{UserID: patientID, Role: "patient"}), models.CancelAppointment{AppointmentID: 1})
+	if err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+
+	select {
+	case capacity := <-released:
+		if capacity.DoctorID != doctorID || !capacity.Date.Equal(clinictime.Day(start)) {
+			t.Errorf("released doctor %d on %v, want doctor %d on %v", capacity.DoctorID, capacity.Date, doctorID, clinictime.Day(start))
+		}
+	default:
+		t.Error("cancellation was not released to the waitlist")
+	}
+}
@@ -2,15 +2,21 @@ package appointments
 
 import (
 	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/authz"
 	"clinic-app/pkg/services/factory"
 
 	"go.uber.org/zap"
 )
 
 // PatientHistoryForDoctor retrieves appointment history for a specific patient.
-func (uc *aptmtUsecaseImpl) PatientHistoryForDoctor(ftx factory.Service, patientId int) ([]models.Appointment, error) {
+func (uc *aptmtUsecaseImpl) PatientHistoryForDoctor(ftx factory.Service, patientId int, includeCanceled bool) ([]models.Appointment, error) {
+	// Doctors may only read the history of patients they have treated
+	if err := uc.authorizer.Authorize(ftx, authz.PatientHistoryRead, patientId); err != nil {
+		return nil, err
+	}
+
 	// Call the repository method to get the patient's appointment history
-	paptmt, err := uc.repo.GetPatientHistory(ftx, patientId)
+	paptmt, err := uc.repo.GetPatientHistory(ftx, patientId, includeCanceled)
 	if err != nil {
 		// Log an error if fetching the appointment history fails
 		ftx.Logger().Error("Error getting Patient Appointment History for Doctor", zap.Error(err))
@@ -22,9 +28,14 @@ func (uc *aptmtUsecaseImpl) PatientHistoryForDoctor(ftx factory.Service, patient
 }
 
 // PatientHistory retrieves all appointment history for the current patient.
-func (uc *aptmtUsecaseImpl) PatientHistory(ftx factory.Service) ([]models.Appointment, error) {
+func (uc *aptmtUsecaseImpl) PatientHistory(ftx factory.Service, includeCanceled bool) ([]models.Appointment, error) {
+	// Patients read their own history
+	if err := uc.authorizer.Authorize(ftx, authz.PatientHistoryRead, ftx.Principal().UserID); err != nil {
+		return nil, err
+	}
+
 	// Call the repository method to get the appointment history for the current patient
-	paptmt, err := uc.repo.GetPatientAppointmentHistory(ftx)
+	paptmt, err := uc.repo.GetPatientAppointmentHistory(ftx, includeCanceled)
 	if err != nil {
 		// Log an error if fetching the appointment history fails
 		ftx.Logger().Error("Error getting Patient Appointment History", zap.Error(err))
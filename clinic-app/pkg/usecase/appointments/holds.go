@@ -0,0 +1,64 @@
+package appointments
+
+import (
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/clinictime"
+	"clinic-app/pkg/services/factory"
+
+	"go.uber.org/zap"
+)
+
+// Hold reserves a time for the calling patient for the configured hold TTL.
+func (uc *aptmtUsecaseImpl) Hold(ftx factory.Service, aptmt models.BookAppointment) (models.AppointmentHold, error) {
+	aptmt.Date = clinictime.Day(aptmt.StartTime) // The clinic day, whatever zone the caller sent the times in
+	if err := uc.applyType(ftx, &aptmt); err != nil {
+		return models.AppointmentHold{}, err
+	}
+
+	hold, err := uc.repo.CreateHold(ftx, aptmt, uc.holdTTL)
+	if err != nil {
+		ftx.Logger().Error("Error holding appointment", zap.Error(err))
+		return models.AppointmentHold{}, err
+	}
+	return hold, nil
+}
+
+// ConfirmHold turns a hold into an appointment and returns the ID of the appointment.
+func (uc *aptmtUsecaseImpl) ConfirmHold(ftx factory.Service, holdId int) (int, error) {
+	if err := uc.authorizer.Authorize(ftx, authz.HoldManage, holdId); err != nil {
+		return 0, err
+	}
+
+	appointmentId, err := uc.repo.ConfirmHold(ftx, holdId)
+	if err != nil {
+		ftx.Logger().Error("Error confirming hold", zap.Error(err))
+		return 0, err
+	}
+	return appointmentId, nil
+}
+
+// ReleaseHold gives the held time back before the hold expires.
+func (uc *aptmtUsecaseImpl) ReleaseHold(ftx factory.Service, holdId int) error {
+	if err := uc.authorizer.Authorize(ftx, authz.HoldManage, holdId); err != nil {
+		return err
+	}
+
+	err := uc.repo.ReleaseHold(ftx, holdId)
+	if err != nil {
+		ftx.Logger().Error("Error releasing hold", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ReapHolds expires the holds that ran out.
+// It runs in the background without a caller, so no authorization applies.
+func (uc *aptmtUsecaseImpl) ReapHolds(ftx factory.Service) (int64, error) {
+	expired, err := uc.repo.ExpireHolds(ftx)
+	if err != nil {
+		ftx.Logger().Error("Error expiring holds", zap.Error(err))
+		return 0, err
+	}
+	return expired, nil
+}
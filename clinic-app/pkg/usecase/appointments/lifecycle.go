@@ -0,0 +1,104 @@
+package appointments
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/factory"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// transitions lists the statuses an appointment can move to from each status.
+// Completed, canceled and no-show appointments are final.
+var transitions = map[string][]string{
+	models.StatusScheduled:  {models.StatusCheckedIn, models.StatusCanceled, models.StatusNoShow},
+	models.StatusCheckedIn:  {models.StatusInProgress, models.StatusCanceled},
+	models.StatusInProgress: {models.StatusCompleted},
+}
+
+// canTransition reports whether an appointment may move from one status to another
+func canTransition(from, to string) bool {
+	return slices.Contains(transitions[from], to)
+}
+
+const (
+	visitOpens  = time.Hour     // How long before its start a patient can be checked in and the consultation started
+	visitCloses = 2 * time.Hour // How long after its start a patient can still be checked in and the consultation started
+)
+
+// checkClock checks that the move fits the time of the appointment. Patients are checked in and consultations
+// started around the start time, and a patient only misses an appointment once it has started.
+func checkClock(aptmt models.Appointment, to string, now time.Time) error {
+	switch to {
+	case models.StatusNoShow:
+		if now.Before(aptmt.StartTime) {
+			return errors.ErrNoShowTooEarly
+		}
+	case models.StatusCheckedIn, models.StatusInProgress:
+		if now.Before(aptmt.StartTime.Add(-visitOpens)) || now.After(aptmt.StartTime.Add(visitCloses)) {
+			return errors.ErrOutsideVisit
+		}
+	}
+	return nil
+}
+
+// CheckIn records that the patient arrived for their appointment.
+func (uc *aptmtUsecaseImpl) CheckIn(ftx factory.Service, aptmtId int) error {
+	return uc.transition(ftx, aptmtId, authz.AppointmentCheckIn, models.StatusCheckedIn)
+}
+
+// Start records that the doctor started the consultation.
+func (uc *aptmtUsecaseImpl) Start(ftx factory.Service, aptmtId int) error {
+	return uc.transition(ftx, aptmtId, authz.AppointmentStart, models.StatusInProgress)
+}
+
+// Complete records that the consultation is finished.
+func (uc *aptmtUsecaseImpl) Complete(ftx factory.Service, aptmtId int) error {
+	return uc.transition(ftx, aptmtId, authz.AppointmentComplete, models.StatusCompleted)
+}
+
+// MarkNoShow records that the patient did not come to their appointment.
+func (uc *aptmtUsecaseImpl) MarkNoShow(ftx factory.Service, aptmtId int) error {
+	return uc.transition(ftx, aptmtId, authz.AppointmentNoShow, models.StatusNoShow)
+}
+
+// SweepNoShows marks scheduled appointments that ended longer than grace ago as no-show.
+// It runs in the background without a caller, so no authorization applies.
+func (uc *aptmtUsecaseImpl) SweepNoShows(ftx factory.Service, grace time.Duration) (int64, error) {
+	marked, err := uc.repo.MarkNoShows(ftx, grace)
+	if err != nil {
+		ftx.Logger().Error("Error marking no-shows", zap.Error(err))
+		return 0, err
+	}
+	return marked, nil
+}
+
+// transition checks that the caller may perform the action on the appointment and that its current status
+// and time allow the move, then updates the status.
+func (uc *aptmtUsecaseImpl) transition(ftx factory.Service, aptmtId int, action authz.Action, to string) error {
+	if err := uc.authorizer.Authorize(ftx, action, aptmtId); err != nil {
+		return err
+	}
+
+	aptmt, err := uc.repo.GetAppointmentStatus(ftx, aptmtId)
+	if err != nil {
+		return err
+	}
+	from := aptmt.Status
+	if !canTransition(from, to) {
+		return &errors.InvalidTransitionError{From: from, To: to}
+	}
+	if err := checkClock(aptmt, to, time.Now()); err != nil {
+		return err
+	}
+
+	err = uc.repo.UpdateAppointmentStatus(ftx, aptmtId, from, to)
+	if err != nil {
+		ftx.Logger().Error("Error updating appointment status", zap.Error(err))
+		return err
+	}
+	return nil
+}
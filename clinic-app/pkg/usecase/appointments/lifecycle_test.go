@@ -0,0 +1,80 @@
+package appointments
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/factory"
+	"context"
+	"testing"
+	"time"
+)
+
+// statusAppointments holds one appointment in a status and records the status it is moved to
+type statusAppointments struct {
+	*fakeAppointments
+	movedTo string
+}
+
+func (r *statusAppointments) GetAppointmentStatus(ftx factory.Service, appointmentId int) (models.Appointment, error) {
+	return r.original, nil
+}
+
+func (r *statusAppointments) UpdateAppointmentStatus(ftx factory.Service, appointmentId int, from, to string) error {
+	r.movedTo = to
+	return nil
+}
+
+func TestTransitionsFollowTheClock(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   string
+		startsIn time.Duration // From now, negative when the appointment started already
+		move     func(uc *aptmtUsecaseImpl, ftx factory.Service, aptmtId int) error
+		want     error
+	}{
+		{"no-show before the start", models.StatusScheduled, 10 * time.Minute, (*aptmtUsecaseImpl).MarkNoShow, errors.ErrNoShowTooEarly},
+		{"no-show after the start", models.StatusScheduled, -10 * time.Minute, (*aptmtUsecaseImpl).MarkNoShow, nil},
+		{"no-show a day later", models.StatusScheduled, -24 * time.Hour, (*aptmtUsecaseImpl).MarkNoShow, nil},
+		{"check-in the day before", models.StatusScheduled, 24 * time.Hour, (*aptmtUsecaseImpl).CheckIn, errors.ErrOutsideVisit},
+		{"check-in shortly before the start", models.StatusScheduled, 30 * time.Minute, (*aptmtUsecaseImpl).CheckIn, nil},
+		{"check-in after the start", models.StatusScheduled, -90 * time.Minute, (*aptmtUsecaseImpl).CheckIn, nil},
+		{"check-in hours after the start", models.StatusScheduled, -3 * time.Hour, (*aptmtUsecaseImpl).CheckIn, errors.ErrOutsideVisit},
+		{"start the day before", models.StatusCheckedIn, 24 * time.Hour, (*aptmtUsecaseImpl).Start, errors.ErrOutsideVisit},
+		{"start shortly before the start", models.StatusCheckedIn, 30 * time.Minute, (*aptmtUsecaseImpl).Start, nil},
+		{"start hours after the start", models.StatusCheckedIn, -3 * time.Hour, (*aptmtUsecaseImpl).Start, errors.ErrOutsideVisit},
+		{"complete hours after the start", models.StatusInProgress, -3 * time.Hour, (*aptmtUsecaseImpl).Complete, nil},
+	}
+
+	ftx, err := factory.NewFactory(nil, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	doctor := ftx.WithPrincipal(models.Principal{UserID: doctorID, Role: "doctor"})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now().Add(tt.startsIn)
+			repo := &statusAppointments{fakeAppointments: &fakeAppointments{original: models.Appointment{
+				AppointmentID: 1,
+				PatientID:     patientID,
+				DoctorID:      doctorID,
+				StartTime:     start,
+				EndTime:       start.Add(30 * time.Minute),
+				Status:        tt.status,
+			}}}
+			uc := New(repo, fakeTypes{}, authz.New(fakeOwnership{}), time.Minute, nil).(*aptmtUsecaseImpl)
+
+			err := tt.move(uc, doctor, 1)
+			if err != tt.want {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if err != nil && repo.movedTo != "" {
+				t.Errorf("appointment moved to %s although the move was rejected", repo.movedTo)
+			}
+			if err == nil && repo.movedTo == "" {
+				t.Error("appointment was not moved")
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package appointments
+
+import (
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/clinictime"
+	"clinic-app/pkg/services/factory"
+
+	"go.uber.org/zap"
+)
+
+// Reschedule moves an appointment to a new time, optionally with another doctor, and returns the ID of the new booking.
+func (uc *aptmtUsecaseImpl) Reschedule(ftx factory.Service, reschedule models.RescheduleAppointment) (int, error) {
+	// Whoever may cancel an appointment may also move it
+	if err := uc.authorizer.Authorize(ftx, authz.AppointmentReschedule, reschedule.AppointmentID); err != nil {
+		return 0, err
+	}
+
+	reschedule.Date = clinictime.Day(reschedule.StartTime) // The clinic day, whatever zone the caller sent the times in
+
+	// The new booking keeps the type of the original, so it must fit the type like a new booking
+	original, err := uc.repo.GetAppointmentById(ftx, reschedule.AppointmentID)
+	if err != nil {
+		return 0, err
+	}
+	if err := uc.checkRescheduleType(ftx, original.TypeID, original.DoctorID, reschedule); err != nil {
+		return 0, err
+	}
+
+	// Call the repository method to reschedule the appointment
+	appointmentId, err := uc.repo.RescheduleAppointment(ftx, reschedule)
+	if err != nil {
+		// Log an error if rescheduling fails
+		ftx.Logger().Error("Error rescheduling appointment", zap.Error(err))
+		return 0, err
+	}
+
+	// Offer the time the appointment moved away from to the waitlist
+	uc.released.Release(ftx, original.DoctorID, clinictime.Day(original.StartTime))
+
+	return appointmentId, nil
+}
+
+// checkRescheduleType checks that the doctor the appointment moves to offers its type and that the new times
+// last as long as the type allows, the same checks a new booking of the type goes through
+func (uc *aptmtUsecaseImpl) checkRescheduleType(ftx factory.Service, typeId *int, doctorId int, reschedule models.RescheduleAppointment) error {
+	if reschedule.DoctorID != 0 {
+		doctorId = reschedule.DoctorID
+	}
+	return uc.applyType(ftx, &models.BookAppointment{
+		DoctorID:  doctorId,
+		StartTime: reschedule.StartTime,
+		EndTime:   reschedule.EndTime,
+		TypeID:    typeId,
+	})
+}
@@ -0,0 +1,105 @@
+package appointments
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/repository"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/factory"
+	"context"
+	"testing"
+	"time"
+)
+
+const (
+	patientID     = 10
+	doctorID      = 20
+	otherDoctorID = 21 // Offers no follow-ups
+	thirdDoctorID = 22
+	followUpID    = 2
+)
+
+// fakeAppointments holds one appointment of the patient with the doctor and records the reschedules
+type fakeAppointments struct {
+	repository.AppointmentRepository
+	original    models.Appointment
+	rescheduled []models.RescheduleAppointment
+}
+
+func (r *fakeAppointments) GetAppointmentById(ftx factory.Service, aptmtID int) (models.Appointment, error) {
+	return r.original, nil
+}
+
+func (r *fakeAppointments) RescheduleAppointment(ftx factory.Service, reschedule models.RescheduleAppointment) (int, error) {
+	r.rescheduled = append(r.rescheduled, reschedule)
+	return r.original.AppointmentID + 1, nil
+}
+
+// fakeTypes offers follow-ups of 10 to 30 minutes with every doctor but the other one
+type fakeTypes struct {
+	repository.AppointmentTypeRepository
+}
+
+func (fakeTypes) GetOfferedType(ftx factory.Service, doctorId, typeId int) (models.AppointmentType, error) {
+	if doctorId == otherDoctorID || typeId != followUpID {
+		return models.AppointmentType{}, errors.ErrTypeNotOffered
+	}
+	return models.AppointmentType{ID: followUpID, Name: "Follow-up", DefaultDurationMinutes: 15, MinDurationMinutes: 10, MaxDurationMinutes: 30}, nil
+}
+
+// fakeOwnership makes every appointment an upcoming one of the patient with the doctor
+type fakeOwnership struct {
+	repository.AuthorizationRepository
+}
+
+func (fakeOwnership) GetAppointmentParties(ftx factory.Service, appointmentId int) (models.AppointmentParties, error) {
+	return models.AppointmentParties{AppointmentID: appointmentId, PatientID: patientID, DoctorID: doctorID, Upcoming: true}, nil
+}
+
+func TestRescheduleChecksType(t *testing.T) {
+	followUp := followUpID
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	tests := []struct {
+		name     string
+		typeId   *int
+		doctorId int
+		duration time.Duration
+		want     error
+	}{
+		{"same doctor within the type", &followUp, 0, 20 * time.Minute, nil},
+		{"same doctor longer than the type", &followUp, 0, 45 * time.Minute, errors.ErrTypeDuration},
+		{"same doctor shorter than the type", &followUp, 0, 5 * time.Minute, errors.ErrTypeDuration},
+		{"doctor offering the type", &followUp, thirdDoctorID, 20 * time.Minute, nil},
+		{"doctor not offering the type", &followUp, otherDoctorID, 20 * time.Minute, errors.ErrTypeNotOffered},
+		{"untyped appointment", nil, otherDoctorID, 45 * time.Minute, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeAppointments{original: models.Appointment{
+				AppointmentID: 1,
+				PatientID:     patientID,
+				DoctorID:      doctorID,
+				TypeID:        tt.typeId,
+			}}
+			uc := New(repo, fakeTypes{}, authz.New(fakeOwnership{}), time.Minute, nil)
+
+			ftx, err := factory.NewFactory(nil, context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = uc.Reschedule(ftx.WithPrincipal(models.Principal{UserID: patientID, Role: "patient"}), models.RescheduleAppointment{
+				AppointmentID: 1,
+				DoctorID:      tt.doctorId,
+				StartTime:     start,
+				EndTime:       start.Add(tt.duration),
+			})
+			if err != tt.want {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if moved := len(repo.rescheduled) == 1; moved != (tt.want == nil) {
+				t.Errorf("moved = %v, want %v", moved, tt.want == nil)
+			}
+		})
+	}
+}
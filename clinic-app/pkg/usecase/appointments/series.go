@@ -0,0 +1,151 @@
+package appointments
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/clinictime"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/services/recurrence"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// BookSeries books the occurrences of a recurring appointment for the calling patient and reports the outcome of each.
+// All-or-nothing series are only booked when every occurrence fits, best-effort ones skip the occurrences that do not.
+func (uc *aptmtUsecaseImpl) BookSeries(ftx factory.Service, series models.BookSeries) (models.AppointmentSeries, []models.SeriesOccurrence, error) {
+	if series.Mode == "" {
+		series.Mode = models.SeriesAllOrNothing
+	}
+	if series.Mode != models.SeriesAllOrNothing && series.Mode != models.SeriesBestEffort {
+		return models.AppointmentSeries{}, nil, errors.ErrInvalidSeries
+	}
+	rule, err := recurrence.Parse(series.RRule)
+	if err != nil || !series.StartTime.Before(series.EndTime) {
+		return models.AppointmentSeries{}, nil, errors.ErrInvalidSeries
+	}
+
+	// Every occurrence lasts as long as the first one and starts at the same clinic wall-clock time,
+	// also in the weeks after a daylight saving change
+	duration := series.EndTime.Sub(series.StartTime)
+	var occurrences []models.BookAppointment
+	for _, start := range rule.Occurrences(clinictime.In(series.StartTime)) {
+		occurrences = append(occurrences, models.BookAppointment{
+			DoctorID:  series.DoctorID,
+			Date:      clinictime.Day(start),
+			StartTime: start,
+			EndTime:   start.Add(duration),
+		})
+	}
+
+	booked, outcomes, err := uc.repo.BookSeries(ftx, series, occurrences)
+	if err != nil {
+		ftx.Logger().Error("Error booking appointment series", zap.Error(err))
+		return models.AppointmentSeries{}, outcomes, err
+	}
+	return booked, outcomes, nil
+}
+
+// CancelSeries cancels the scheduled occurrences of the series of an appointment, from the appointment on
+// for ScopeFollowing and every upcoming one for ScopeAll. Each occurrence is canceled on its own.
+func (uc *aptmtUsecaseImpl) CancelSeries(ftx factory.Service, cancel models.CancelAppointment, scope string) ([]models.AffectedAppointment, error) {
+	_, occurrences, err := uc.seriesOccurrences(ftx, authz.AppointmentCancel, cancel.AppointmentID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes := make([]models.AffectedAppointment, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		outcome := models.AffectedAppointment{AppointmentID: occurrence.AppointmentID}
+		err := uc.Cancel(ftx, models.CancelAppointment{AppointmentID: occurrence.AppointmentID, Reason: cancel.Reason})
+		if err != nil {
+			outcome.Error = err.Error()
+			if appErr, ok := err.(*errors.ClinicAppError); ok {
+				outcome.Error = appErr.Message
+			}
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	return outcomes, nil
+}
+
+// RescheduleSeries moves the scheduled occurrences of the series of an appointment by as much as the appointment
+// is moved, giving them the new duration and optionally another doctor. Either every occurrence moves or none does.
+func (uc *aptmtUsecaseImpl) RescheduleSeries(ftx factory.Service, reschedule models.RescheduleAppointment, scope string) ([]models.AffectedAppointment, error) {
+	anchor, occurrences, err := uc.seriesOccurrences(ftx, authz.AppointmentReschedule, reschedule.AppointmentID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := clinictime.In(anchor.StartTime), clinictime.In(reschedule.StartTime)
+	days := daysBetween(clinictime.Day(from), clinictime.Day(to))
+	minutes := (to.Hour()*60 + to.Minute()) - (from.Hour()*60 + from.Minute())
+	duration := reschedule.EndTime.Sub(reschedule.StartTime)
+	moves := make([]models.RescheduleAppointment, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		// Occurrences may have been moved to other doctors one by one
+		if err := uc.authorizer.Authorize(ftx, authz.AppointmentReschedule, occurrence.AppointmentID); err != nil {
+			return nil, err
+		}
+		// Occurrences move by as many clinic days and wall-clock minutes as the appointment
+		local := clinictime.In(occurrence.StartTime)
+		start := time.Date(local.Year(), local.Month(), local.Day()+days, local.Hour(), local.Minute()+minutes,
+			local.Second(), 0, local.Location())
+		move := models.RescheduleAppointment{
+			AppointmentID: occurrence.AppointmentID,
+			DoctorID:      reschedule.DoctorID,
+			Date:          clinictime.Day(start),
+			StartTime:     start,
+			EndTime:       start.Add(duration),
+		}
+		// Occurrences share the type of the series, each must still fit it with its doctor
+		if err := uc.checkRescheduleType(ftx, anchor.TypeID, occurrence.DoctorID, move); err != nil {
+			return nil, err
+		}
+		moves = append(moves, move)
+	}
+
+	outcomes, err := uc.repo.RescheduleSeries(ftx, moves)
+	if err != nil {
+		ftx.Logger().Error("Error rescheduling appointment series", zap.Error(err))
+		return outcomes, err
+	}
+
+	// Offer the times the occurrences moved away from to the waitlist
+	for _, occurrence := range occurrences {
+		uc.released.Release(ftx, occurrence.DoctorID, occurrence.Date)
+	}
+	return outcomes, nil
+}
+
+// seriesOccurrences checks that the caller may perform the action on the appointment and that the scope
+// applies to it, then retrieves the appointment and the occurrences of its series in the scope
+func (uc *aptmtUsecaseImpl) seriesOccurrences(ftx factory.Service, action authz.Action, appointmentId int, scope string) (models.Appointment, []models.BookAppointment, error) {
+	if scope != models.ScopeFollowing && scope != models.ScopeAll {
+		return models.Appointment{}, nil, errors.ErrInvalidScope
+	}
+	if err := uc.authorizer.Authorize(ftx, action, appointmentId); err != nil {
+		return models.Appointment{}, nil, err
+	}
+
+	aptmt, err := uc.repo.GetAppointmentById(ftx, appointmentId)
+	if err != nil {
+		return models.Appointment{}, nil, err
+	}
+	if aptmt.SeriesID == nil {
+		return models.Appointment{}, nil, errors.ErrInvalidScope
+	}
+
+	occurrences, err := uc.repo.GetSeriesAppointments(ftx, appointmentId, scope)
+	if err != nil {
+		return models.Appointment{}, nil, err
+	}
+	return aptmt, occurrences, nil
+}
+
+// daysBetween returns the number of calendar days from one start of a day to another,
+// days around a daylight saving change are 23 or 25 hours long
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Round(24*time.Hour) / (24 * time.Hour))
+}
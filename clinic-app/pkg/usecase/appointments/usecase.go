@@ -2,16 +2,27 @@ package appointments
 
 import (
 	"clinic-app/pkg/repository"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/releases"
 	"clinic-app/pkg/usecase"
+	"time"
 )
 
 type aptmtUsecaseImpl struct {
-	repo repository.AppointmentRepository
+	repo       repository.AppointmentRepository
+	typeRepo   repository.AppointmentTypeRepository
+	authorizer *authz.Authorizer
+	holdTTL    time.Duration
+	released   releases.Notifier // Tells the waitlist about cancelled and moved appointments
 }
 
 // NewaptmtUsecase creates a new instance of aptmtUsecaseImpl and returns it as the aptmtUsecase interface
-func New(repo repository.AppointmentRepository) usecase.AppointmentUsecase {
+func New(repo repository.AppointmentRepository, typeRepo repository.AppointmentTypeRepository, authorizer *authz.Authorizer, holdTTL time.Duration, released releases.Notifier) usecase.AppointmentUsecase {
 	return &aptmtUsecaseImpl{
 		repo,
+		typeRepo,
+		authorizer,
+		holdTTL,
+		released,
 	}
 }
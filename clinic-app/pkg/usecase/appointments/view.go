@@ -2,6 +2,7 @@ package appointments
 
 import (
 	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/authz"
 	"clinic-app/pkg/services/factory"
 
 	"go.uber.org/zap"
@@ -9,6 +10,11 @@ import (
 
 // ViewAppointment retrieves details of a specific appointment by its ID.
 func (uc *aptmtUsecaseImpl) ViewAppointment(ftx factory.Service, aptmtId int) (models.Appointment, error) {
+	// Only the patient and the doctor of the appointment may view it
+	if err := uc.authorizer.Authorize(ftx, authz.AppointmentRead, aptmtId); err != nil {
+		return models.Appointment{}, err
+	}
+
 	// Call the repository method to get the appointment details by ID
 	aptmt, err := uc.repo.GetAppointmentById(ftx, aptmtId)
 	if err != nil {
@@ -0,0 +1,115 @@
+package appointmenttypes
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/factory"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Bounds of the durations of an appointment type in minutes
+const (
+	minTypeMinutes = 5
+	maxTypeMinutes = 480
+)
+
+// colorPattern matches a color written as #RRGGBB
+var colorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// Types retrieves the appointment types. Callers who manage them also see the inactive ones.
+func (uc *aptmtTypeUsecaseImpl) Types(ftx factory.Service) ([]models.AppointmentType, error) {
+	includeInactive, err := uc.authorizer.Allowed(ftx, authz.AppointmentTypeManage, 0)
+	if err != nil {
+		return nil, err
+	}
+	return uc.repo.GetTypes(ftx, includeInactive)
+}
+
+// CreateType adds an appointment type
+func (uc *aptmtTypeUsecaseImpl) CreateType(ftx factory.Service, aptmtType models.AppointmentType) (models.AppointmentType, error) {
+	if err := uc.authorizer.Authorize(ftx, authz.AppointmentTypeManage, 0); err != nil {
+		return models.AppointmentType{}, err
+	}
+	if !validType(&aptmtType) {
+		return models.AppointmentType{}, errors.ErrInvalidAptmtType
+	}
+
+	created, err := uc.repo.CreateType(ftx, aptmtType)
+	if err != nil {
+		ftx.Logger().Error("Error creating appointment type", zap.Error(err))
+		return models.AppointmentType{}, err
+	}
+	return created, nil
+}
+
+// UpdateType changes an appointment type
+func (uc *aptmtTypeUsecaseImpl) UpdateType(ftx factory.Service, aptmtType models.AppointmentType) (models.AppointmentType, error) {
+	if err := uc.authorizer.Authorize(ftx, authz.AppointmentTypeManage, aptmtType.ID); err != nil {
+		return models.AppointmentType{}, err
+	}
+	if !validType(&aptmtType) {
+		return models.AppointmentType{}, errors.ErrInvalidAptmtType
+	}
+
+	updated, err := uc.repo.UpdateType(ftx, aptmtType)
+	if err != nil {
+		ftx.Logger().Error("Error updating appointment type", zap.Error(err))
+		return models.AppointmentType{}, err
+	}
+	return updated, nil
+}
+
+// DeactivateType stops an appointment type from being booked, it is kept for the appointments that have it
+func (uc *aptmtTypeUsecaseImpl) DeactivateType(ftx factory.Service, typeId int) error {
+	if err := uc.authorizer.Authorize(ftx, authz.AppointmentTypeManage, typeId); err != nil {
+		return err
+	}
+
+	err := uc.repo.DeactivateType(ftx, typeId)
+	if err != nil {
+		ftx.Logger().Error("Error deactivating appointment type", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// DoctorTypes retrieves the types a doctor can be booked for
+func (uc *aptmtTypeUsecaseImpl) DoctorTypes(ftx factory.Service, doctorId int) ([]models.AppointmentType, error) {
+	return uc.repo.GetDoctorTypes(ftx, doctorId)
+}
+
+// SetDoctorTypes chooses the types a doctor offers, an empty list offers every active type
+func (uc *aptmtTypeUsecaseImpl) SetDoctorTypes(ftx factory.Service, doctorId int, typeIds []int) error {
+	if err := uc.authorizer.Authorize(ftx, authz.DoctorTypesManage, doctorId); err != nil {
+		return err
+	}
+
+	// Every type is recorded once
+	typeIds = slices.Clone(typeIds)
+	slices.Sort(typeIds)
+	typeIds = slices.Compact(typeIds)
+
+	err := uc.repo.SetDoctorTypes(ftx, doctorId, typeIds)
+	if err != nil {
+		ftx.Logger().Error("Error setting doctor appointment types", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// validType trims the name of an appointment type and reports whether its name, color and durations are acceptable
+func validType(aptmtType *models.AppointmentType) bool {
+	aptmtType.Name = strings.TrimSpace(aptmtType.Name)
+	if aptmtType.Name == "" || !colorPattern.MatchString(aptmtType.Color) {
+		return false
+	}
+	return aptmtType.MinDurationMinutes >= minTypeMinutes &&
+		aptmtType.MinDurationMinutes <= aptmtType.DefaultDurationMinutes &&
+		aptmtType.DefaultDurationMinutes <= aptmtType.MaxDurationMinutes &&
+		aptmtType.MaxDurationMinutes <= maxTypeMinutes
+}
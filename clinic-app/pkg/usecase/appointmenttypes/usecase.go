@@ -0,0 +1,20 @@
+package appointmenttypes
+
+import (
+	"clinic-app/pkg/repository"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/usecase"
+)
+
+type aptmtTypeUsecaseImpl struct {
+	repo       repository.AppointmentTypeRepository
+	authorizer *authz.Authorizer
+}
+
+// New creates a new instance of aptmtTypeUsecaseImpl and returns it as the AppointmentTypeUsecase interface
+func New(repo repository.AppointmentTypeRepository, authorizer *authz.Authorizer) usecase.AppointmentTypeUsecase {
+	return &aptmtTypeUsecaseImpl{
+		repo,
+		authorizer,
+	}
+}
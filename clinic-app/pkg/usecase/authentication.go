@@ -9,4 +9,28 @@ import (
 type AuthUsecase interface {
 	RegisterUser(ftx factory.Service, user models.User) (int, error)
 	LoginUser(ftx factory.Service, credentials models.Credentials) (models.User, error)
+	IssueTokens(ftx factory.Service, user models.User) (models.TokenPair, error)
+	RefreshTokens(ftx factory.Service, refreshToken string) (models.TokenPair, error)
+	Logout(ftx factory.Service, accessToken, refreshToken string) error
+	Authenticate(ftx factory.Service, accessToken string) (*models.Claims, error)
+	PublicKeys(ftx factory.Service) models.JSONWebKeySet
+	AuthenticateChallenge(ftx factory.Service, challengeToken, purpose string) (*models.Claims, error)
+	ConsumeChallenge(ftx factory.Service, claims *models.Claims) error
+	LoginChallenge(ftx factory.Service, user models.User) (*models.MFAChallenge, error)
+	CompleteMFALogin(ftx factory.Service, login models.MFALogin) (models.User, error)
+	EnrollMFA(ftx factory.Service) (models.MFAEnrollment, error)
+	ActivateMFA(ftx factory.Service, code string) ([]string, error)
+	RegenerateRecoveryCodes(ftx factory.Service, code string) ([]string, error)
+	DisableMFA(ftx factory.Service, code string) error
+	UnlockUser(ftx factory.Service, username string) error
+	UnlockIP(ftx factory.Service, ip string) error
+	VerifyEmail(ftx factory.Service, verificationToken string) error
+	ResendVerification(ftx factory.Service) error
+	ForgotPassword(ftx factory.Service, email string) error
+	ResetPassword(ftx factory.Service, reset models.ResetPassword) error
+	InviteStaff(ftx factory.Service, invite models.NewInvitation) (models.Invitation, error)
+	AcceptInvitation(ftx factory.Service, accept models.AcceptInvitation) (int, error)
+	Invitations(ftx factory.Service) ([]models.Invitation, error)
+	InvitationEvents(ftx factory.Service, invitationID int) ([]models.InvitationEvent, error)
+	RevokeInvitation(ftx factory.Service, invitationID int) error
 }
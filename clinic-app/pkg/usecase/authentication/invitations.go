@@ -0,0 +1,90 @@
+package authentication
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/services/token"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// InviteStaff creates a single-use invitation for a doctor or admin account and mails it to the invited address.
+// When the mail cannot be sent the invitation is returned together with ErrMailDelivery.
+func (uc *authUsecaseImpl) InviteStaff(ftx factory.Service, invite models.NewInvitation) (models.Invitation, error) {
+	invite.Role = strings.ToLower(strings.TrimSpace(invite.Role))
+	if invite.Role != "doctor" && invite.Role != "admin" {
+		return models.Invitation{}, errors.ErrInvalidRole
+	}
+
+	plain, hash, err := token.NewOpaqueToken()
+	if err != nil {
+		ftx.Logger().Error("Failed to generate invitation token", zap.Error(err))
+		return models.Invitation{}, err
+	}
+
+	inv, err := uc.repo.CreateInvitation(ftx, hash, invite, uc.invitationTTL)
+	if err != nil {
+		ftx.Logger().Error("Failed to create invitation", zap.Error(err))
+		return models.Invitation{}, err
+	}
+
+	// The token only ever leaves the server in this email
+	return inv, uc.send(ftx, uc.invitationMail(inv, uc.link("/accept-invitation", plain)))
+}
+
+// AcceptInvitation completes a staff account from an invitation and returns the new user ID.
+func (uc *authUsecaseImpl) AcceptInvitation(ftx factory.Service, accept models.AcceptInvitation) (int, error) {
+	// Staff passwords follow the same policy as everyone else's
+	if err := validatePassword(accept.Username, accept.Password); err != nil {
+		return 0, err
+	}
+
+	hash, err := uc.passwords.Hash(accept.Password)
+	if err != nil {
+		ftx.Logger().Error("Failed to hash password", zap.Error(err))
+		return 0, err
+	}
+
+	userID, err := uc.repo.AcceptInvitation(ftx, token.HashOpaqueToken(accept.Token), models.User{
+		Username: accept.Username,
+		Name:     accept.Name,
+		Password: hash,
+	})
+	if err != nil {
+		ftx.Logger().Error("Failed to accept invitation", zap.Error(err))
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+// Invitations lists all staff invitations.
+func (uc *authUsecaseImpl) Invitations(ftx factory.Service) ([]models.Invitation, error) {
+	invitations, err := uc.repo.GetInvitations(ftx)
+	if err != nil {
+		ftx.Logger().Error("Error getting invitations", zap.Error(err))
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// InvitationEvents returns the audit trail of an invitation.
+func (uc *authUsecaseImpl) InvitationEvents(ftx factory.Service, invitationID int) ([]models.InvitationEvent, error) {
+	events, err := uc.repo.GetInvitationEvents(ftx, invitationID)
+	if err != nil {
+		ftx.Logger().Error("Error getting invitation events", zap.Error(err))
+		return nil, err
+	}
+	return events, nil
+}
+
+// RevokeInvitation revokes an invitation that has not been accepted yet.
+func (uc *authUsecaseImpl) RevokeInvitation(ftx factory.Service, invitationID int) error {
+	if err := uc.repo.RevokeInvitation(ftx, invitationID); err != nil {
+		ftx.Logger().Error("Error revoking invitation", zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package authentication
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/services/lockout"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// attemptKey is a key of a limiter failed attempts are counted under
+type attemptKey struct {
+	limiter lockout.Limiter
+	key     string
+}
+
+// userKey counts failed logins for a username, whether or not the account exists
+func (uc *authUsecaseImpl) userKey(username string) attemptKey {
+	return attemptKey{uc.userLimiter, "user:" + strings.ToLower(strings.TrimSpace(username))}
+}
+
+// ipKey counts failed logins from a client IP
+func (uc *authUsecaseImpl) ipKey(ip string) attemptKey {
+	return attemptKey{uc.ipLimiter, "ip:" + ip}
+}
+
+// mfaKey counts wrong two-factor codes entered for a user
+func (uc *authUsecaseImpl) mfaKey(userID int) attemptKey {
+	return attemptKey{uc.userLimiter, "mfa:" + strconv.Itoa(userID)}
+}
+
+// loginKeys returns the keys a login attempt is counted under
+func (uc *authUsecaseImpl) loginKeys(username, ip string) []attemptKey {
+	keys := []attemptKey{uc.userKey(username)}
+	if ip != "" {
+		keys = append(keys, uc.ipKey(ip))
+	}
+	return keys
+}
+
+// checkAttempts returns a LockedError when any key has to wait before its next attempt
+func (uc *authUsecaseImpl) checkAttempts(ftx factory.Service, keys ...attemptKey) error {
+	wait := &errors.LockedError{}
+	for _, k := range keys {
+		retryAfter, err := k.limiter.Allow(ftx.Context(), k.key)
+		if err != nil {
+			ftx.Logger().Error("Could not check failed attempts", zap.String("Key", k.key), zap.Error(err))
+			return err
+		}
+		if retryAfter > wait.RetryAfter {
+			wait.RetryAfter = retryAfter
+		}
+	}
+
+	if wait.RetryAfter > 0 {
+		ftx.Logger().Info("Attempt blocked", zap.Duration("RetryAfter", wait.RetryAfter))
+		return wait
+	}
+	return nil
+}
+
+// recordFailure counts a failed attempt for every key and logs the keys that got locked out by it
+func (uc *authUsecaseImpl) recordFailure(ftx factory.Service, keys ...attemptKey) {
+	for _, k := range keys {
+		status, err := k.limiter.Fail(ftx.Context(), k.key)
+		if err != nil {
+			ftx.Logger().Error("Could not record failed attempt", zap.String("Key", k.key), zap.Error(err))
+			continue
+		}
+		if status.Locked {
+			ftx.Logger().Warn("Locked out after repeated failures",
+				zap.String("Key", k.key),
+				zap.Int("Failures", status.Failures),
+				zap.Duration("LockedFor", status.RetryAfter),
+			)
+		}
+	}
+}
+
+// clearAttempts forgets the failed attempts of a key after a success
+func (uc *authUsecaseImpl) clearAttempts(ftx factory.Service, k attemptKey) {
+	if err := k.limiter.Reset(ftx.Context(), k.key); err != nil {
+		ftx.Logger().Error("Could not reset failed attempts", zap.String("Key", k.key), zap.Error(err))
+	}
+}
+
+// UnlockUser lifts the lockout of a username, including the lockout of its two-factor codes.
+func (uc *authUsecaseImpl) UnlockUser(ftx factory.Service, username string) error {
+	if err := uc.userLimiter.Reset(ftx.Context(), uc.userKey(username).key); err != nil {
+		return err
+	}
+
+	user, err := uc.repo.GetUserByUsername(ftx, username)
+	if err == nil {
+		if err := uc.userLimiter.Reset(ftx.Context(), uc.mfaKey(user.ID).key); err != nil {
+			return err
+		}
+	} else if err != errors.ErrUserNotFound {
+		return err
+	}
+
+	ftx.Logger().Warn("Lockout lifted",
+		zap.String("Username", username),
+		zap.Int("AdminID", ftx.Principal().UserID),
+	)
+	return nil
+}
+
+// UnlockIP lifts the lockout of a client IP.
+func (uc *authUsecaseImpl) UnlockIP(ftx factory.Service, ip string) error {
+	if err := uc.ipLimiter.Reset(ftx.Context(), uc.ipKey(ip).key); err != nil {
+		return err
+	}
+
+	ftx.Logger().Warn("Lockout lifted",
+		zap.String("IP", ip),
+		zap.Int("AdminID", ftx.Principal().UserID),
+	)
+	return nil
+}
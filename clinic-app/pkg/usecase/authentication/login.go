@@ -1,20 +1,61 @@
 package authentication
 
 import (
+	"clinic-app/pkg/domain/errors"
 	"clinic-app/pkg/domain/models"
 	"clinic-app/pkg/services/factory"
+
+	"go.uber.org/zap"
 )
 
 // LoginUser handles user login by verifying credentials.
 func (uc *authUsecaseImpl) LoginUser(ftx factory.Service, credentials models.Credentials) (models.User, error) {
-	// Attempt to login using the provided username and password
-	user, err := uc.repo.LoginUser(ftx, credentials.Username, credentials.Password)
-	if err != nil || user.Password != credentials.Password {
-		// Log an error if credentials are invalid or the user could not be found
+	// Refuse attempts while the username or the client IP is backing off or locked out
+	keys := uc.loginKeys(credentials.Username, credentials.ClientIP)
+	if err := uc.checkAttempts(ftx, keys...); err != nil {
+		return models.User{}, err
+	}
+
+	// Look up the user by username
+	user, err := uc.repo.GetUserByUsername(ftx, credentials.Username)
+	if err != nil {
+		if err == errors.ErrUserNotFound {
+			// Spend the same time as a real check so unknown usernames are not revealed
+			uc.passwords.SimulateVerify(credentials.Password)
+			uc.recordFailure(ftx, keys...)
+		}
+		ftx.Logger().Error("Invalid credentials")
+		return models.User{}, err
+	}
+
+	// Verify the password against the stored hash
+	match, err := uc.passwords.Verify(user.Password, credentials.Password)
+	if err != nil {
+		ftx.Logger().Error("Could not verify password", zap.Error(err))
+		return models.User{}, err
+	}
+	if !match {
+		uc.recordFailure(ftx, keys...)
 		ftx.Logger().Error("Invalid credentials")
-		return user, err
+		return models.User{}, errors.ErrInvalidPassword
+	}
+
+	// The username starts over, the IP keeps its count so one working account cannot hide guesses at others
+	uc.clearAttempts(ftx, uc.userKey(credentials.Username))
+
+	// Upgrade legacy plaintext passwords and outdated hashes now that we know the plaintext
+	if uc.passwords.NeedsRehash(user.Password) {
+		hash, err := uc.passwords.Hash(credentials.Password)
+		if err == nil {
+			err = uc.repo.UpdatePassword(ftx, user.ID, hash)
+		}
+		if err != nil {
+			// The login itself succeeded, the upgrade is retried on the next login
+			ftx.Logger().Warn("Could not upgrade stored password", zap.Int("UserID", user.ID), zap.Error(err))
+		}
 	}
 
-	// Return the user details if credentials are valid
+	// Never hand the stored hash back to callers
+	user.Password = ""
 	return user, nil
 }
@@ -0,0 +1,84 @@
+package authentication
+
+import (
+	"clinic-app/pkg/adapters/mailer"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/services/token"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// link builds a link to a frontend page carrying a token
+func (uc *authUsecaseImpl) link(page, plainToken string) string {
+	return uc.appBaseURL + page + "?token=" + url.QueryEscape(plainToken)
+}
+
+// send delivers an email, failures are logged and reported as ErrMailDelivery
+func (uc *authUsecaseImpl) send(ftx factory.Service, msg mailer.Message) error {
+	if err := uc.mailer.Send(ftx.Context(), msg); err != nil {
+		ftx.Logger().Error("Failed to send email", zap.String("Subject", msg.Subject), zap.Error(err))
+		return errors.ErrMailDelivery
+	}
+	return nil
+}
+
+// sendUserToken creates a single-use token for a user and mails them the link built by compose
+func (uc *authUsecaseImpl) sendUserToken(ftx factory.Service, user models.User, purpose string, ttl time.Duration,
+	compose func(user models.User, link string) mailer.Message) error {
+	plain, hash, err := token.NewOpaqueToken()
+	if err != nil {
+		ftx.Logger().Error("Failed to generate user token", zap.Error(err))
+		return err
+	}
+
+	if err := uc.repo.CreateUserToken(ftx, user.ID, purpose, hash, ttl); err != nil {
+		return err
+	}
+
+	page := "/verify-email"
+	if purpose == models.TokenPurposeResetPassword {
+		page = "/reset-password"
+	}
+	return uc.send(ftx, compose(user, uc.link(page, plain)))
+}
+
+// verificationMail asks a user to confirm their email address
+func (uc *authUsecaseImpl) verificationMail(user models.User, link string) mailer.Message {
+	return mailer.Message{
+		To:      user.Email,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf("Hello %s,\n\n"+
+			"Please confirm your email address to start booking appointments:\n\n%s\n\n"+
+			"The link expires in %s. If you did not create an account you can ignore this email.\n",
+			user.Name, link, uc.emailVerificationTTL),
+	}
+}
+
+// resetMail sends a user a link to choose a new password
+func (uc *authUsecaseImpl) resetMail(user models.User, link string) mailer.Message {
+	return mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hello %s,\n\n"+
+			"A password reset was requested for your account %q. Choose a new password here:\n\n%s\n\n"+
+			"The link expires in %s. If you did not ask for this you can ignore this email, your password stays the same.\n",
+			user.Name, user.Username, link, uc.passwordResetTTL),
+	}
+}
+
+// invitationMail invites a staff member to create their account
+func (uc *authUsecaseImpl) invitationMail(inv models.Invitation, link string) mailer.Message {
+	return mailer.Message{
+		To:      inv.Email,
+		Subject: "You have been invited to the clinic",
+		Body: fmt.Sprintf("Hello,\n\n"+
+			"You have been invited to join the clinic as %s. Create your account here:\n\n%s\n\n"+
+			"The invitation expires on %s.\n",
+			inv.Role, link, inv.ExpiresAt.Format(time.RFC1123)),
+	}
+}
@@ -0,0 +1,230 @@
+package authentication
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/services/token"
+	"clinic-app/pkg/services/totp"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// mfaRequired reports whether users with a role must use two-factor authentication
+func (uc *authUsecaseImpl) mfaRequired(role string) bool {
+	for _, required := range uc.mfaRequiredRoles {
+		if role == required {
+			return true
+		}
+	}
+	return false
+}
+
+// LoginChallenge decides whether a user who entered the right password needs a second login step.
+// It returns nil when tokens can be issued right away.
+func (uc *authUsecaseImpl) LoginChallenge(ftx factory.Service, user models.User) (*models.MFAChallenge, error) {
+	settings, err := uc.repo.GetMFA(ftx, user.ID)
+	if err != nil && err != errors.ErrNotFound {
+		return nil, err
+	}
+
+	var purpose string
+	switch {
+	case settings.Enabled:
+		purpose = models.ChallengeMFA
+	case uc.mfaRequired(user.Role):
+		purpose = models.ChallengeEnrollMFA
+	default:
+		return nil, nil
+	}
+
+	expiresAt := time.Now().Add(uc.mfaChallengeTTL)
+	challenge, err := uc.tokens.SignChallengeToken(user.ID, user.Role, purpose, token.NewJTI(), expiresAt)
+	if err != nil {
+		ftx.Logger().Error("Failed to create challenge token", zap.Error(err))
+		return nil, err
+	}
+
+	return &models.MFAChallenge{
+		Token:     challenge,
+		Purpose:   purpose,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// CompleteMFALogin checks the code for a login challenge and returns the user to issue tokens for.
+func (uc *authUsecaseImpl) CompleteMFALogin(ftx factory.Service, login models.MFALogin) (models.User, error) {
+	claims, err := uc.AuthenticateChallenge(ftx, login.ChallengeToken, models.ChallengeMFA)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	// Wrong codes are counted per user so the six digits cannot be guessed within the challenge lifetime
+	key := uc.mfaKey(claims.UserID)
+	if err := uc.checkAttempts(ftx, key); err != nil {
+		return models.User{}, err
+	}
+	if err := uc.verifyMFACode(ftx, claims.UserID, login.Code); err != nil {
+		if err == errors.ErrInvalidMFACode {
+			uc.recordFailure(ftx, key)
+		}
+		return models.User{}, err
+	}
+	uc.clearAttempts(ftx, key)
+
+	if err := uc.ConsumeChallenge(ftx, claims); err != nil {
+		return models.User{}, err
+	}
+
+	return models.User{ID: claims.UserID, Role: claims.Role}, nil
+}
+
+// EnrollMFA creates a new TOTP secret for the caller. It only becomes active once ActivateMFA confirms a code.
+func (uc *authUsecaseImpl) EnrollMFA(ftx factory.Service) (models.MFAEnrollment, error) {
+	user, err := uc.repo.GetUserByID(ftx, ftx.Principal().UserID)
+	if err != nil {
+		return models.MFAEnrollment{}, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		ftx.Logger().Error("Failed to generate TOTP secret", zap.Error(err))
+		return models.MFAEnrollment{}, err
+	}
+	sealed, err := uc.mfaCipher.Seal(secret)
+	if err != nil {
+		ftx.Logger().Error("Failed to encrypt TOTP secret", zap.Error(err))
+		return models.MFAEnrollment{}, err
+	}
+
+	if err := uc.repo.SaveMFASecret(ftx, user.ID, sealed); err != nil {
+		return models.MFAEnrollment{}, err
+	}
+
+	return models.MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(uc.mfaIssuer, user.Username, secret),
+	}, nil
+}
+
+// ActivateMFA enables two-factor authentication for the caller with a first TOTP code and returns their recovery codes.
+func (uc *authUsecaseImpl) ActivateMFA(ftx factory.Service, code string) ([]string, error) {
+	userID := ftx.Principal().UserID
+
+	settings, err := uc.repo.GetMFA(ftx, userID)
+	if err == errors.ErrNotFound {
+		return nil, errors.ErrMFANotEnabled
+	}
+	if err != nil {
+		return nil, err
+	}
+	if settings.Enabled {
+		return nil, errors.ErrMFAAlreadyEnabled
+	}
+
+	// Only a TOTP code proves the authenticator app was set up
+	step, err := uc.matchTOTP(ftx, settings, code)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		ftx.Logger().Error("Failed to generate recovery codes", zap.Error(err))
+		return nil, err
+	}
+
+	if err := uc.repo.EnableMFA(ftx, userID, step, hashes); err != nil {
+		return nil, err
+	}
+
+	ftx.Logger().Info("Two-factor authentication enabled", zap.Int("UserID", userID))
+	return codes, nil
+}
+
+// RegenerateRecoveryCodes replaces the caller's recovery codes after checking a code.
+func (uc *authUsecaseImpl) RegenerateRecoveryCodes(ftx factory.Service, code string) ([]string, error) {
+	userID := ftx.Principal().UserID
+
+	if err := uc.verifyMFACode(ftx, userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		ftx.Logger().Error("Failed to generate recovery codes", zap.Error(err))
+		return nil, err
+	}
+
+	if err := uc.repo.ReplaceRecoveryCodes(ftx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// DisableMFA turns two-factor authentication off for the caller after checking a code.
+// Users whose role requires it cannot turn it off.
+func (uc *authUsecaseImpl) DisableMFA(ftx factory.Service, code string) error {
+	principal := ftx.Principal()
+	if uc.mfaRequired(principal.Role) {
+		return errors.ErrMFAMandatory
+	}
+
+	if err := uc.verifyMFACode(ftx, principal.UserID, code); err != nil {
+		return err
+	}
+
+	return uc.repo.DeleteMFA(ftx, principal.UserID)
+}
+
+// verifyMFACode accepts either a TOTP code or an unused recovery code of a user with two-factor authentication enabled.
+func (uc *authUsecaseImpl) verifyMFACode(ftx factory.Service, userID int, code string) error {
+	settings, err := uc.repo.GetMFA(ftx, userID)
+	if err == errors.ErrNotFound || (err == nil && !settings.Enabled) {
+		return errors.ErrMFANotEnabled
+	}
+	if err != nil {
+		return err
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits && strings.Trim(code, "0123456789") == "" {
+		step, err := uc.matchTOTP(ftx, settings, code)
+		if err != nil {
+			return err
+		}
+		return uc.repo.UseTOTPStep(ftx, userID, step)
+	}
+
+	return uc.repo.UseRecoveryCode(ftx, userID, token.HashOpaqueToken(totp.NormalizeRecoveryCode(code)))
+}
+
+// matchTOTP returns the time step a TOTP code is valid for, codes at or before the last used step are rejected
+func (uc *authUsecaseImpl) matchTOTP(ftx factory.Service, settings models.MFASettings, code string) (int64, error) {
+	secret, err := uc.mfaCipher.Open(settings.Secret)
+	if err != nil {
+		ftx.Logger().Error("Failed to decrypt TOTP secret", zap.Int("UserID", settings.UserID), zap.Error(err))
+		return 0, err
+	}
+
+	step, ok := totp.Validate(secret, code, time.Now())
+	if !ok || step <= settings.LastUsedStep {
+		return 0, errors.ErrInvalidMFACode
+	}
+	return step, nil
+}
+
+// newRecoveryCodes generates recovery codes and the hashes they are stored under
+func newRecoveryCodes() ([]string, []string, error) {
+	codes, err := totp.NewRecoveryCodes()
+	if err != nil {
+		return nil, nil, err
+	}
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = token.HashOpaqueToken(totp.NormalizeRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
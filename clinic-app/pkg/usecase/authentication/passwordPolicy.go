@@ -0,0 +1,41 @@
+package authentication
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"strings"
+	"unicode"
+)
+
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 72 // bcrypt ignores everything past 72 bytes
+)
+
+// validatePassword checks a new password against the password policy.
+func validatePassword(username, password string) error {
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return errors.ErrWeakPassword
+	}
+
+	var hasUpper, hasLower, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	if !hasUpper || !hasLower || !hasDigit {
+		return errors.ErrWeakPassword
+	}
+
+	// The password must not simply repeat the username
+	if username != "" && strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+		return errors.ErrWeakPassword
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package authentication
+
+import (
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/services/token"
+
+	"go.uber.org/zap"
+)
+
+// ForgotPassword mails a password reset link to every account registered with an email address.
+// The outcome is the same whether or not an account exists so the endpoint cannot be used to find accounts.
+func (uc *authUsecaseImpl) ForgotPassword(ftx factory.Service, email string) error {
+	users, err := uc.repo.GetUsersByEmail(ftx, email)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if err := uc.sendUserToken(ftx, user, models.TokenPurposeResetPassword, uc.passwordResetTTL, uc.resetMail); err != nil {
+			// Keep going, one failed mail should not stop the others
+			ftx.Logger().Error("Failed to send password reset", zap.Int("UserID", user.ID), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// ResetPassword sets a new password with a reset token and logs the user out everywhere.
+func (uc *authUsecaseImpl) ResetPassword(ftx factory.Service, reset models.ResetPassword) error {
+	// The account is only known once the token is used, so the username part of the policy cannot be checked
+	if err := validatePassword("", reset.Password); err != nil {
+		return err
+	}
+
+	hash, err := uc.passwords.Hash(reset.Password)
+	if err != nil {
+		ftx.Logger().Error("Failed to hash password", zap.Error(err))
+		return err
+	}
+
+	userID, revoked, err := uc.repo.ResetPassword(ftx, token.HashOpaqueToken(reset.Token), hash)
+	if err != nil {
+		return err
+	}
+	for _, t := range revoked {
+		uc.revocations.MarkRevoked(t.JTI, t.ExpiresAt)
+	}
+
+	ftx.Logger().Info("Password reset", zap.Int("UserID", userID))
+	return nil
+}
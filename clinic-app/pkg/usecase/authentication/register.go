@@ -1,6 +1,7 @@
 package authentication
 
 import (
+	"clinic-app/pkg/domain/errors"
 	"clinic-app/pkg/domain/models"
 	"clinic-app/pkg/services/factory"
 
@@ -9,6 +10,32 @@ import (
 
 // RegisterUser registers a new user and returns the user ID.
 func (uc *authUsecaseImpl) RegisterUser(ftx factory.Service, user models.User) (int, error) {
+	// Self-registration is for patients only, staff accounts come from invitations
+	if user.Role == "" {
+		user.Role = "patient"
+	}
+	if user.Role != "patient" {
+		ftx.Logger().Warn("Rejected self-registration with a staff role",
+			zap.String("Username", user.Username),
+			zap.String("Role", user.Role),
+		)
+		return 0, errors.ErrRoleNotAllowed
+	}
+
+	// Reject passwords that do not meet the password policy
+	if err := validatePassword(user.Username, user.Password); err != nil {
+		ftx.Logger().Info("Password does not meet the password policy", zap.String("Username", user.Username))
+		return 0, err
+	}
+
+	// Store only the hash of the password
+	hash, err := uc.passwords.Hash(user.Password)
+	if err != nil {
+		ftx.Logger().Error("Failed to hash password", zap.Error(err))
+		return 0, err
+	}
+	user.Password = hash
+
 	// Attempt to register the user using the provided user details
 	userID, err := uc.repo.RegisterUser(ftx, user)
 	if err != nil {
@@ -17,6 +44,13 @@ func (uc *authUsecaseImpl) RegisterUser(ftx factory.Service, user models.User) (
 		return 0, err
 	}
 
+	// Ask the user to confirm their email address. The account exists either way,
+	// a failed mail only means the user has to ask for a new link
+	user.ID = userID
+	if err := uc.sendUserToken(ftx, user, models.TokenPurposeVerifyEmail, uc.emailVerificationTTL, uc.verificationMail); err != nil {
+		ftx.Logger().Warn("Could not send email verification", zap.Int("UserID", userID), zap.Error(err))
+	}
+
 	// Return the user ID if registration is successful
 	return userID, nil
 }
@@ -0,0 +1,226 @@
+package authentication
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/services/token"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// IssueTokens starts a new token family for a user who just logged in.
+func (uc *authUsecaseImpl) IssueTokens(ftx factory.Service, user models.User) (models.TokenPair, error) {
+	refresh, refreshHash, err := token.NewOpaqueToken()
+	if err != nil {
+		ftx.Logger().Error("Failed to generate refresh token", zap.Error(err))
+		return models.TokenPair{}, err
+	}
+
+	// Sign the access token first so the refresh token can record its ID
+	jti := token.NewJTI()
+	accessExpiresAt := time.Now().Add(uc.tokens.AccessTTL())
+	access, err := uc.tokens.SignAccessToken(user.ID, user.Role, jti, accessExpiresAt)
+	if err != nil {
+		ftx.Logger().Error("Failed to create JWT token", zap.Error(err))
+		return models.TokenPair{}, err
+	}
+
+	err = uc.repo.StoreRefreshToken(ftx, user.ID, models.RefreshToken{
+		Hash:            refreshHash,
+		FamilyID:        token.NewJTI(),
+		AccessJTI:       jti,
+		AccessExpiresAt: accessExpiresAt,
+		TTL:             uc.tokens.RefreshTTL(),
+	})
+	if err != nil {
+		ftx.Logger().Error("Failed to store refresh token", zap.Error(err))
+		return models.TokenPair{}, err
+	}
+
+	return models.TokenPair{
+		AccessToken:      access,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refresh,
+		RefreshExpiresAt: time.Now().Add(uc.tokens.RefreshTTL()),
+	}, nil
+}
+
+// RefreshTokens exchanges a refresh token for a new token pair.
+// Presenting a token that was already exchanged revokes its whole family.
+func (uc *authUsecaseImpl) RefreshTokens(ftx factory.Service, refreshToken string) (models.TokenPair, error) {
+	if refreshToken == "" {
+		return models.TokenPair{}, errors.ErrInvalidToken
+	}
+
+	next, nextHash, err := token.NewOpaqueToken()
+	if err != nil {
+		ftx.Logger().Error("Failed to generate refresh token", zap.Error(err))
+		return models.TokenPair{}, err
+	}
+	jti := token.NewJTI()
+	accessExpiresAt := time.Now().Add(uc.tokens.AccessTTL())
+
+	session, err := uc.repo.RotateRefreshToken(ftx, token.HashOpaqueToken(refreshToken), models.RefreshToken{
+		Hash:            nextHash,
+		AccessJTI:       jti,
+		AccessExpiresAt: accessExpiresAt,
+		TTL:             uc.tokens.RefreshTTL(),
+	})
+	if err == errors.ErrTokenReused {
+		// Someone replayed a retired token, assume it was stolen and log every holder out
+		ftx.Logger().Warn("Revoking token family after refresh token reuse",
+			zap.Int("UserID", session.UserID),
+			zap.String("Family", session.FamilyID),
+		)
+		if revokeErr := uc.revokeFamily(ftx, session.FamilyID); revokeErr != nil {
+			return models.TokenPair{}, revokeErr
+		}
+		return models.TokenPair{}, err
+	}
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	// Sessions started before two-factor authentication became mandatory for the role end here
+	if uc.mfaRequired(session.Role) {
+		settings, err := uc.repo.GetMFA(ftx, session.UserID)
+		if err != nil && err != errors.ErrNotFound {
+			return models.TokenPair{}, err
+		}
+		if !settings.Enabled {
+			ftx.Logger().Info("Refresh refused until two-factor authentication is set up", zap.Int("UserID", session.UserID))
+			if revokeErr := uc.revokeFamily(ftx, session.FamilyID); revokeErr != nil {
+				return models.TokenPair{}, revokeErr
+			}
+			return models.TokenPair{}, errors.ErrInvalidToken
+		}
+	}
+
+	access, err := uc.tokens.SignAccessToken(session.UserID, session.Role, jti, accessExpiresAt)
+	if err != nil {
+		ftx.Logger().Error("Failed to create JWT token", zap.Error(err))
+		return models.TokenPair{}, err
+	}
+
+	return models.TokenPair{
+		AccessToken:      access,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     next,
+		RefreshExpiresAt: time.Now().Add(uc.tokens.RefreshTTL()),
+	}, nil
+}
+
+// Logout revokes the caller's access token and the family of their refresh token.
+// Either token may be empty, tokens that are already invalid are ignored.
+func (uc *authUsecaseImpl) Logout(ftx factory.Service, accessToken, refreshToken string) error {
+	if accessToken != "" {
+		if claims, err := uc.tokens.Parse(accessToken); err == nil {
+			revoked := models.RevokedToken{
+				JTI:       claims.Id,
+				ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+			}
+			if err := uc.repo.RevokeAccessToken(ftx, revoked); err != nil {
+				return err
+			}
+			uc.revocations.MarkRevoked(revoked.JTI, revoked.ExpiresAt)
+		}
+	}
+
+	if refreshToken != "" {
+		familyID, err := uc.repo.GetRefreshTokenFamily(ftx, token.HashOpaqueToken(refreshToken))
+		if err == errors.ErrInvalidToken {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return uc.revokeFamily(ftx, familyID)
+	}
+
+	return nil
+}
+
+// Authenticate validates an access token and makes sure it has not been revoked.
+func (uc *authUsecaseImpl) Authenticate(ftx factory.Service, accessToken string) (*models.Claims, error) {
+	claims, err := uc.tokens.Parse(accessToken)
+	if err != nil {
+		return nil, errors.ErrInvalidToken
+	}
+
+	// Login challenge tokens carry a purpose and never grant access on their own
+	if claims.Purpose != "" {
+		return nil, errors.ErrInvalidToken
+	}
+
+	if err := uc.checkRevoked(ftx, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// AuthenticateChallenge validates a login challenge token issued for the given purpose.
+func (uc *authUsecaseImpl) AuthenticateChallenge(ftx factory.Service, challengeToken, purpose string) (*models.Claims, error) {
+	claims, err := uc.tokens.Parse(challengeToken)
+	if err != nil || claims.Purpose != purpose {
+		return nil, errors.ErrInvalidToken
+	}
+
+	if err := uc.checkRevoked(ftx, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// ConsumeChallenge revokes a challenge token once its login step is complete so it cannot be used twice.
+func (uc *authUsecaseImpl) ConsumeChallenge(ftx factory.Service, claims *models.Claims) error {
+	revoked := models.RevokedToken{
+		JTI:       claims.Id,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}
+	if err := uc.repo.RevokeAccessToken(ftx, revoked); err != nil {
+		return err
+	}
+	uc.revocations.MarkRevoked(revoked.JTI, revoked.ExpiresAt)
+	return nil
+}
+
+// checkRevoked rejects tokens on the revocation list, asking the database only when the cache does not know the token.
+func (uc *authUsecaseImpl) checkRevoked(ftx factory.Service, claims *models.Claims) error {
+	revoked, known := uc.revocations.Lookup(claims.Id)
+	if !known {
+		var err error
+		revoked, err = uc.repo.IsAccessTokenRevoked(ftx, claims.Id)
+		if err != nil {
+			return err
+		}
+		if revoked {
+			uc.revocations.MarkRevoked(claims.Id, time.Unix(claims.ExpiresAt, 0))
+		} else {
+			uc.revocations.MarkValid(claims.Id)
+		}
+	}
+	if revoked {
+		ftx.Logger().Info("Revoked token presented", zap.Int("UserID", claims.UserID))
+		return errors.ErrInvalidToken
+	}
+	return nil
+}
+
+// PublicKeys returns the public keys other services use to verify our tokens.
+func (uc *authUsecaseImpl) PublicKeys(ftx factory.Service) models.JSONWebKeySet {
+	return uc.tokens.Keys().JWKS()
+}
+
+// revokeFamily revokes a token family and updates the in-process revocation cache.
+func (uc *authUsecaseImpl) revokeFamily(ftx factory.Service, familyID string) error {
+	revoked, err := uc.repo.RevokeTokenFamily(ftx, familyID)
+	if err != nil {
+		return err
+	}
+	for _, t := range revoked {
+		uc.revocations.MarkRevoked(t.JTI, t.ExpiresAt)
+	}
+	return nil
+}
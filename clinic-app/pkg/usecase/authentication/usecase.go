@@ -1,17 +1,70 @@
 package authentication
 
 import (
+	"clinic-app/pkg/adapters/mailer"
 	"clinic-app/pkg/repository"
+	"clinic-app/pkg/services/lockout"
+	"clinic-app/pkg/services/password"
+	"clinic-app/pkg/services/token"
+	"clinic-app/pkg/services/totp"
 	"clinic-app/pkg/usecase"
+	"time"
 )
 
 type authUsecaseImpl struct {
-	repo repository.AuthenticationRepository
+	repo          repository.AuthenticationRepository
+	passwords     *password.Hasher       // Hashes and verifies passwords
+	tokens        *token.Manager         // Signs and parses access tokens
+	revocations   *token.RevocationCache // In-process view of the revocation list
+	invitationTTL time.Duration          // How long a staff invitation can be accepted
+
+	mailer               mailer.Mailer // Sends verification, reset and invitation emails
+	emailVerificationTTL time.Duration // How long an email verification link can be used
+	passwordResetTTL     time.Duration // How long a password reset link can be used
+	appBaseURL           string        // Base URL the links in emails point to
+
+	mfaCipher        *totp.Cipher  // Encrypts TOTP secrets at rest
+	mfaIssuer        string        // Name authenticator apps show next to the account
+	mfaRequiredRoles []string      // Roles that cannot log in without two-factor authentication
+	mfaChallengeTTL  time.Duration // How long the second login step can be completed
+
+	userLimiter lockout.Limiter // Failed logins per username and failed codes per user
+	ipLimiter   lockout.Limiter // Failed logins per client IP
 }
 
 // New creates a new instance of repository with a database connection
-func New(repo repository.AuthenticationRepository) usecase.AuthUsecase {
+func New(
+	repo repository.AuthenticationRepository,
+	passwords *password.Hasher,
+	tokens *token.Manager,
+	revocations *token.RevocationCache,
+	invitationTTL time.Duration,
+	mailer mailer.Mailer,
+	emailVerificationTTL time.Duration,
+	passwordResetTTL time.Duration,
+	appBaseURL string,
+	mfaCipher *totp.Cipher,
+	mfaIssuer string,
+	mfaRequiredRoles []string,
+	mfaChallengeTTL time.Duration,
+	userLimiter lockout.Limiter,
+	ipLimiter lockout.Limiter,
+) usecase.AuthUsecase {
 	return &authUsecaseImpl{
 		repo,
+		passwords,
+		tokens,
+		revocations,
+		invitationTTL,
+		mailer,
+		emailVerificationTTL,
+		passwordResetTTL,
+		appBaseURL,
+		mfaCipher,
+		mfaIssuer,
+		mfaRequiredRoles,
+		mfaChallengeTTL,
+		userLimiter,
+		ipLimiter,
 	}
 }
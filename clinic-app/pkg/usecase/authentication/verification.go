@@ -0,0 +1,35 @@
+package authentication
+
+import (
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"clinic-app/pkg/services/token"
+
+	"go.uber.org/zap"
+)
+
+// VerifyEmail confirms the email address of the user a verification token was mailed to.
+func (uc *authUsecaseImpl) VerifyEmail(ftx factory.Service, verificationToken string) error {
+	userID, err := uc.repo.VerifyEmail(ftx, token.HashOpaqueToken(verificationToken))
+	if err != nil {
+		return err
+	}
+
+	ftx.Logger().Info("Email verified", zap.Int("UserID", userID))
+	return nil
+}
+
+// ResendVerification mails the caller a new verification link, replacing the previous one.
+func (uc *authUsecaseImpl) ResendVerification(ftx factory.Service) error {
+	user, err := uc.repo.GetUserByID(ftx, ftx.Principal().UserID)
+	if err != nil {
+		return err
+	}
+
+	// Nothing to do for addresses that are already verified
+	if user.EmailVerified {
+		return nil
+	}
+
+	return uc.sendUserToken(ftx, user, models.TokenPurposeVerifyEmail, uc.emailVerificationTTL, uc.verificationMail)
+}
@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+)
+
+// BookingPolicyUsecase defines methods for managing the booking rules of the clinic and its doctors.
+type BookingPolicyUsecase interface {
+	Policies(ftx factory.Service) (models.BookingPolicy, []models.BookingPolicy, error)
+	EffectivePolicy(ftx factory.Service, doctorId int) (models.BookingPolicy, error)
+	SetDefaultPolicy(ftx factory.Service, policy models.BookingPolicy) error
+	SetDoctorPolicy(ftx factory.Service, policy models.BookingPolicy) error
+	ClearDoctorPolicy(ftx factory.Service, doctorId int) error
+}
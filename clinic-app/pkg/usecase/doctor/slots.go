@@ -1,14 +1,20 @@
 package doctor
 
 import (
+	"clinic-app/pkg/services/authz"
 	"clinic-app/pkg/services/factory"
 
 	"go.uber.org/zap"
 )
 
 // Slots retrieves available time slots for a specific doctor.
-// If `check` is true, retrieves slots by doctor, otherwise retrieves slots by patient.
-func (uc *doctorsUsecaseImpl) Slots(ftx factory.Service, doctorId int, check bool) ([]interface{}, error) {
+// The doctor themselves and admins also see which patients booked the slots.
+func (uc *doctorsUsecaseImpl) Slots(ftx factory.Service, doctorId int) ([]interface{}, error) {
+	check, err := uc.authorizer.Allowed(ftx, authz.SlotPatientsRead, doctorId)
+	if err != nil {
+		return nil, err
+	}
+
 	// Call the repository method to get doctor slots
 	slots, err := uc.repo.GetDoctorSlots(ftx, doctorId, check)
 	if err != nil {
@@ -2,16 +2,19 @@ package doctor
 
 import (
 	"clinic-app/pkg/repository"
+	"clinic-app/pkg/services/authz"
 	"clinic-app/pkg/usecase"
 )
 
 type doctorsUsecaseImpl struct {
-	repo repository.DoctorRepository
+	repo       repository.DoctorRepository
+	authorizer *authz.Authorizer
 }
 
 // NewdoctorsUsecase creates a new instance of doctorsUsecaseImpl and returns it as the doctorsUsecase interface
-func New(repo repository.DoctorRepository) usecase.DoctorUsecase {
+func New(repo repository.DoctorRepository, authorizer *authz.Authorizer) usecase.DoctorUsecase {
 	return &doctorsUsecaseImpl{
 		repo,
+		authorizer,
 	}
 }
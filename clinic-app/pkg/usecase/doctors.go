@@ -9,5 +9,5 @@ import (
 type DoctorUsecase interface {
 	AllDoctors(ftx factory.Service) ([]models.Doctor, error)
 	DoctorById(ftx factory.Service, doctorId int) (models.Doctor, error)
-	Slots(ftx factory.Service, doctorId int, check bool) ([]interface{}, error)
+	Slots(ftx factory.Service, doctorId int) ([]interface{}, error)
 }
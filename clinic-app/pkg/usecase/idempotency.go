@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+)
+
+// IdempotencyUsecase defines methods for replaying the responses of retried requests.
+type IdempotencyUsecase interface {
+	Begin(ftx factory.Service, request models.IdempotentRequest) (*models.IdempotentResponse, error)
+	Complete(ftx factory.Service, request models.IdempotentRequest, response models.IdempotentResponse) error
+	Abandon(ftx factory.Service, request models.IdempotentRequest) error
+	PurgeExpired(ftx factory.Service) (int64, error)
+}
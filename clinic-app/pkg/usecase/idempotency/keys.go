@@ -0,0 +1,65 @@
+package idempotency
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+
+	"go.uber.org/zap"
+)
+
+// Begin claims the key of a request. It returns nil when the request should run,
+// or the stored response when it is a retry of a request that already ran.
+func (uc *idempotencyUsecaseImpl) Begin(ftx factory.Service, request models.IdempotentRequest) (*models.IdempotentResponse, error) {
+	// A key released or expired between claiming and reading is claimed once more
+	for attempt := 0; attempt < 2; attempt++ {
+		claimed, err := uc.repo.ClaimKey(ftx, request, uc.ttl, staleAfter)
+		if err != nil {
+			return nil, err
+		}
+		if claimed {
+			return nil, nil
+		}
+
+		stored, err := uc.repo.GetResponse(ftx, request)
+		if err == errors.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case stored.RequestHash != request.RequestHash:
+			ftx.Logger().Info("Idempotency key reused for a different request", zap.String("key", request.Key))
+			return nil, errors.ErrIdempotencyReuse
+		case stored.StatusCode == 0:
+			return nil, errors.ErrRequestInProgress
+		}
+
+		ftx.Logger().Info("Replaying idempotent response", zap.String("key", request.Key))
+		return &stored, nil
+	}
+	return nil, errors.ErrRequestInProgress
+}
+
+// Complete stores the response of a request, retries with the same key get it replayed
+func (uc *idempotencyUsecaseImpl) Complete(ftx factory.Service, request models.IdempotentRequest, response models.IdempotentResponse) error {
+	return uc.repo.SaveResponse(ftx, request, response)
+}
+
+// Abandon releases the key of a request that failed on the server side, so a retry runs it again
+func (uc *idempotencyUsecaseImpl) Abandon(ftx factory.Service, request models.IdempotentRequest) error {
+	return uc.repo.ReleaseKey(ftx, request)
+}
+
+// PurgeExpired removes the keys whose responses are no longer replayed.
+// It runs in the background without a caller, so no authorization applies.
+func (uc *idempotencyUsecaseImpl) PurgeExpired(ftx factory.Service) (int64, error) {
+	deleted, err := uc.repo.DeleteExpiredKeys(ftx)
+	if err != nil {
+		ftx.Logger().Error("Error deleting expired idempotency keys", zap.Error(err))
+		return 0, err
+	}
+	return deleted, nil
+}
@@ -0,0 +1,24 @@
+package idempotency
+
+import (
+	"clinic-app/pkg/repository"
+	"clinic-app/pkg/usecase"
+	"time"
+)
+
+// staleAfter is how long a request may hold its key without a response before a retry takes the key over,
+// so a request that died with the server does not block its key until it expires
+const staleAfter = time.Minute
+
+type idempotencyUsecaseImpl struct {
+	repo repository.IdempotencyRepository
+	ttl  time.Duration // How long a response is replayed
+}
+
+// New creates a new instance of idempotencyUsecaseImpl and returns it as the IdempotencyUsecase interface
+func New(repo repository.IdempotencyRepository, ttl time.Duration) usecase.IdempotencyUsecase {
+	return &idempotencyUsecaseImpl{
+		repo,
+		ttl,
+	}
+}
@@ -0,0 +1,104 @@
+package policy
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/factory"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Policies retrieves the clinic-wide default policy and the policies of the doctors that override it
+func (uc *policyUsecaseImpl) Policies(ftx factory.Service) (models.BookingPolicy, []models.BookingPolicy, error) {
+	defaultPolicy, err := uc.repo.GetDefaultPolicy(ftx)
+	if err != nil {
+		return defaultPolicy, nil, err
+	}
+
+	doctorPolicies, err := uc.repo.GetDoctorPolicies(ftx)
+	if err != nil {
+		return defaultPolicy, nil, err
+	}
+	return defaultPolicy, doctorPolicies, nil
+}
+
+// EffectivePolicy retrieves the rules that apply to a doctor
+func (uc *policyUsecaseImpl) EffectivePolicy(ftx factory.Service, doctorId int) (models.BookingPolicy, error) {
+	return uc.repo.GetEffectivePolicy(ftx, doctorId)
+}
+
+// SetDefaultPolicy changes the clinic-wide default policy, it has to set every rule
+func (uc *policyUsecaseImpl) SetDefaultPolicy(ftx factory.Service, policy models.BookingPolicy) error {
+	if err := uc.authorizer.Authorize(ftx, authz.BookingPolicyManage, 0); err != nil {
+		return err
+	}
+
+	if policy.MaxDailyAppointments == nil || policy.MaxDailyMinutes == nil ||
+		policy.MinDurationMinutes == nil || policy.MaxDurationMinutes == nil ||
+		policy.BufferMinutes == nil || policy.BookingHorizonDays == nil {
+		return errors.ErrInvalidPolicy
+	}
+	if *policy.MinDurationMinutes > *policy.MaxDurationMinutes {
+		return errors.ErrInvalidPolicy
+	}
+
+	policy.DoctorID = nil
+	if err := uc.repo.SaveDefaultPolicy(ftx, policy, uc.horizonDays); err != nil {
+		ftx.Logger().Error("Error saving default booking policy", zap.Error(err))
+		return err
+	}
+
+	// Looser caps may leave room for waiting patients with any doctor
+	uc.released.Release(ftx, 0, time.Time{})
+	return nil
+}
+
+// SetDoctorPolicy sets the policy of a doctor, the rules it leaves out fall back to the default
+func (uc *policyUsecaseImpl) SetDoctorPolicy(ftx factory.Service, policy models.BookingPolicy) error {
+	if err := uc.authorizer.Authorize(ftx, authz.BookingPolicyManage, *policy.DoctorID); err != nil {
+		return err
+	}
+
+	// The duration bounds must still fit together once merged with the default
+	defaultPolicy, err := uc.repo.GetDefaultPolicy(ftx)
+	if err != nil {
+		return err
+	}
+	minDuration, maxDuration := *defaultPolicy.MinDurationMinutes, *defaultPolicy.MaxDurationMinutes
+	if policy.MinDurationMinutes != nil {
+		minDuration = *policy.MinDurationMinutes
+	}
+	if policy.MaxDurationMinutes != nil {
+		maxDuration = *policy.MaxDurationMinutes
+	}
+	if minDuration > maxDuration {
+		return errors.ErrInvalidPolicy
+	}
+
+	if err := uc.repo.SaveDoctorPolicy(ftx, policy, uc.horizonDays); err != nil {
+		ftx.Logger().Error("Error saving doctor booking policy", zap.Error(err))
+		return err
+	}
+
+	// Looser caps may leave room for patients waiting for the doctor
+	uc.released.Release(ftx, *policy.DoctorID, time.Time{})
+	return nil
+}
+
+// ClearDoctorPolicy removes the policy of a doctor, the default applies to them again
+func (uc *policyUsecaseImpl) ClearDoctorPolicy(ftx factory.Service, doctorId int) error {
+	if err := uc.authorizer.Authorize(ftx, authz.BookingPolicyManage, doctorId); err != nil {
+		return err
+	}
+
+	if err := uc.repo.DeleteDoctorPolicy(ftx, doctorId, uc.horizonDays); err != nil {
+		ftx.Logger().Error("Error removing doctor booking policy", zap.Error(err))
+		return err
+	}
+
+	// The default may be looser than the policy the doctor had
+	uc.released.Release(ftx, doctorId, time.Time{})
+	return nil
+}
@@ -0,0 +1,25 @@
+package policy
+
+import (
+	"clinic-app/pkg/repository"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/releases"
+	"clinic-app/pkg/usecase"
+)
+
+type policyUsecaseImpl struct {
+	repo        repository.BookingPolicyRepository
+	authorizer  *authz.Authorizer
+	horizonDays int               // How many days ahead schedules are materialised, changed policies are applied to them
+	released    releases.Notifier // Tells the waitlist when doctors can take more appointments
+}
+
+// New creates a new instance of policyUsecaseImpl and returns it as the BookingPolicyUsecase interface
+func New(repo repository.BookingPolicyRepository, authorizer *authz.Authorizer, horizonDays int, released releases.Notifier) usecase.BookingPolicyUsecase {
+	return &policyUsecaseImpl{
+		repo,
+		authorizer,
+		horizonDays,
+		released,
+	}
+}
@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+)
+
+// ScheduleUsecase defines methods for managing the working hours of doctors and the schedules built from them.
+type ScheduleUsecase interface {
+	WorkingHours(ftx factory.Service, doctorId int) ([]models.WorkingHours, error)
+	SetWorkingHours(ftx factory.Service, set models.SetWorkingHours) error
+	MaterialiseSchedules(ftx factory.Service) error
+	FreeSlots(ftx factory.Service, query models.FreeSlotQuery) ([]models.FreeSlot, error)
+}
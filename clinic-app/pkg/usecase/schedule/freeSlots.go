@@ -0,0 +1,55 @@
+package schedule
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSlotDuration    = 30  // Minutes a free slot lasts unless asked otherwise
+	defaultSlotGranularity = 15  // Minutes between the starts of two free slots unless asked otherwise
+	maxFreeSlotRangeDays   = 31  // Longest range free slots are computed for at once
+	maxSlotDuration        = 480 // Longest slot asked for, the doctor's booking policy narrows it further
+	minSlotGranularity     = 5   // Finest granularity free slots are computed with
+	maxSlotGranularity     = 120 // Coarsest granularity free slots are computed with
+)
+
+// FreeSlots computes the bookable slots of a doctor between two days
+func (uc *scheduleUsecaseImpl) FreeSlots(ftx factory.Service, query models.FreeSlotQuery) ([]models.FreeSlot, error) {
+	if query.Duration == 0 {
+		query.Duration = defaultSlotDuration
+	}
+	if query.Granularity == 0 {
+		query.Granularity = defaultSlotGranularity
+	}
+	if !validFreeSlotQuery(query) {
+		return nil, errors.ErrInvalidSlotQuery
+	}
+
+	slots, err := uc.repo.GetFreeSlots(ftx, query)
+	if err != nil {
+		ftx.Logger().Error("Error computing free slots", zap.Error(err))
+		return nil, err
+	}
+	return slots, nil
+}
+
+// validFreeSlotQuery checks the day range, the duration and the granularity of a free slot query
+func validFreeSlotQuery(query models.FreeSlotQuery) bool {
+	from, err := time.Parse("2006-01-02", query.From)
+	if err != nil {
+		return false
+	}
+	to, err := time.Parse("2006-01-02", query.To)
+	if err != nil || to.Before(from) || to.Sub(from) > maxFreeSlotRangeDays*24*time.Hour {
+		return false
+	}
+	if query.Duration <= 0 || query.Duration > maxSlotDuration {
+		return false
+	}
+	return query.Granularity >= minSlotGranularity && query.Granularity <= maxSlotGranularity
+}
@@ -0,0 +1,25 @@
+package schedule
+
+import (
+	"clinic-app/pkg/repository"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/releases"
+	"clinic-app/pkg/usecase"
+)
+
+type scheduleUsecaseImpl struct {
+	repo        repository.ScheduleRepository
+	authorizer  *authz.Authorizer
+	horizonDays int               // How many days ahead schedules are materialised
+	released    releases.Notifier // Tells the waitlist when doctors work more hours
+}
+
+// New creates a new instance of scheduleUsecaseImpl and returns it as the ScheduleUsecase interface
+func New(repo repository.ScheduleRepository, authorizer *authz.Authorizer, horizonDays int, released releases.Notifier) usecase.ScheduleUsecase {
+	return &scheduleUsecaseImpl{
+		repo,
+		authorizer,
+		horizonDays,
+		released,
+	}
+}
@@ -0,0 +1,89 @@
+package schedule
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/factory"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// WorkingHours retrieves the current and future working hours of a doctor
+func (uc *scheduleUsecaseImpl) WorkingHours(ftx factory.Service, doctorId int) ([]models.WorkingHours, error) {
+	hours, err := uc.repo.GetWorkingHours(ftx, doctorId)
+	if err != nil {
+		ftx.Logger().Error("Error getting working hours", zap.Error(err))
+		return nil, err
+	}
+	return hours, nil
+}
+
+// SetWorkingHours replaces the working hours of a doctor from the day the new ones take effect
+func (uc *scheduleUsecaseImpl) SetWorkingHours(ftx factory.Service, set models.SetWorkingHours) error {
+	// Doctors may only set their own hours
+	if err := uc.authorizer.Authorize(ftx, authz.WorkingHoursManage, set.DoctorID); err != nil {
+		return err
+	}
+
+	if !validWorkingHours(set) {
+		return errors.ErrInvalidHours
+	}
+
+	if err := uc.repo.ReplaceWorkingHours(ftx, set, uc.horizonDays); err != nil {
+		ftx.Logger().Error("Error setting working hours", zap.Error(err))
+		return err
+	}
+
+	// New hours may leave room for patients waiting for the doctor
+	uc.released.Release(ftx, set.DoctorID, time.Time{})
+	return nil
+}
+
+// MaterialiseSchedules extends the schedules of every doctor up to the horizon
+func (uc *scheduleUsecaseImpl) MaterialiseSchedules(ftx factory.Service) error {
+	return uc.repo.MaterialiseSchedules(ftx, uc.horizonDays)
+}
+
+// validWorkingHours checks the dates and that the blocks of each weekday are well formed and do not overlap
+func validWorkingHours(set models.SetWorkingHours) bool {
+	from, err := time.Parse("2006-01-02", set.EffectiveFrom)
+	if err != nil {
+		return false
+	}
+	if set.EffectiveTo != nil {
+		to, err := time.Parse("2006-01-02", *set.EffectiveTo)
+		if err != nil || to.Before(from) {
+			return false
+		}
+	}
+
+	type block struct{ start, end time.Time }
+	days := make(map[int][]block)
+	for _, b := range set.Blocks {
+		if b.Weekday < 0 || b.Weekday > 6 {
+			return false
+		}
+		start, err := time.Parse("15:04", b.StartTime)
+		if err != nil {
+			return false
+		}
+		end, err := time.Parse("15:04", b.EndTime)
+		if err != nil || !start.Before(end) {
+			return false
+		}
+		days[b.Weekday] = append(days[b.Weekday], block{start, end})
+	}
+
+	for _, blocks := range days {
+		sort.Slice(blocks, func(i, j int) bool { return blocks[i].start.Before(blocks[j].start) })
+		for i := 1; i < len(blocks); i++ {
+			if blocks[i].start.Before(blocks[i-1].end) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,19 @@
+package usecase
+
+import (
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+)
+
+// TimeOffUsecase defines methods for requesting and approving the time off of doctors.
+type TimeOffUsecase interface {
+	Request(ftx factory.Service, request models.RequestTimeOff) (models.TimeOff, []models.Appointment, error)
+	DoctorTimeOff(ftx factory.Service, doctorId int) ([]models.TimeOff, error)
+	ByStatus(ftx factory.Service, status string) ([]models.TimeOff, error)
+	Approve(ftx factory.Service, timeOffId int) error
+	Reject(ftx factory.Service, timeOffId int) error
+	Withdraw(ftx factory.Service, timeOffId int) error
+	AffectedAppointments(ftx factory.Service, timeOffId int) ([]models.Appointment, error)
+	CancelAffected(ftx factory.Service, timeOffId int, reason string) ([]models.AffectedAppointment, error)
+	ReassignAffected(ftx factory.Service, timeOffId int, doctorId int) ([]models.AffectedAppointment, error)
+}
@@ -0,0 +1,105 @@
+package timeoff
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/clinictime"
+	"clinic-app/pkg/services/factory"
+	stderrors "errors"
+)
+
+// defaultCancelReason is recorded on appointments canceled for a time off when no reason is given
+const defaultCancelReason = "Doctor unavailable"
+
+// AffectedAppointments retrieves the upcoming appointments that fall into a time off
+func (uc *timeOffUsecaseImpl) AffectedAppointments(ftx factory.Service, timeOffId int) ([]models.Appointment, error) {
+	if err := uc.authorizer.Authorize(ftx, authz.TimeOffManage, timeOffId); err != nil {
+		return nil, err
+	}
+	return uc.repo.GetAffectedAppointments(ftx, timeOffId)
+}
+
+// CancelAffected cancels every upcoming appointment that falls into a time off.
+// Appointments that cannot be canceled are left as they are and reported with the reason.
+func (uc *timeOffUsecaseImpl) CancelAffected(ftx factory.Service, timeOffId int, reason string) ([]models.AffectedAppointment, error) {
+	if reason == "" {
+		reason = defaultCancelReason
+	}
+
+	affected, err := uc.activeAffected(ftx, timeOffId)
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes := make([]models.AffectedAppointment, 0, len(affected))
+	for _, aptmt := range affected {
+		err := uc.appointments.Cancel(ftx, models.CancelAppointment{
+			AppointmentID: aptmt.AppointmentID,
+			Reason:        reason,
+		})
+		outcomes = append(outcomes, models.AffectedAppointment{
+			AppointmentID: aptmt.AppointmentID,
+			Error:         outcomeError(err),
+		})
+	}
+	return outcomes, nil
+}
+
+// ReassignAffected moves every upcoming appointment that falls into a time off to another doctor at the same time.
+// Appointments the other doctor cannot take are left as they are and reported with the reason.
+func (uc *timeOffUsecaseImpl) ReassignAffected(ftx factory.Service, timeOffId int, doctorId int) ([]models.AffectedAppointment, error) {
+	affected, err := uc.activeAffected(ftx, timeOffId)
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes := make([]models.AffectedAppointment, 0, len(affected))
+	for _, aptmt := range affected {
+		newId, err := uc.appointments.Reschedule(ftx, models.RescheduleAppointment{
+			AppointmentID: aptmt.AppointmentID,
+			DoctorID:      doctorId,
+			Date:          clinictime.Day(aptmt.StartTime),
+			StartTime:     aptmt.StartTime,
+			EndTime:       aptmt.EndTime,
+		})
+		outcomes = append(outcomes, models.AffectedAppointment{
+			AppointmentID:    aptmt.AppointmentID,
+			NewAppointmentID: newId,
+			Error:            outcomeError(err),
+		})
+	}
+	return outcomes, nil
+}
+
+// activeAffected authorizes the caller and retrieves the appointments affected by a time off that still applies
+func (uc *timeOffUsecaseImpl) activeAffected(ftx factory.Service, timeOffId int) ([]models.Appointment, error) {
+	if err := uc.authorizer.Authorize(ftx, authz.TimeOffManage, timeOffId); err != nil {
+		return nil, err
+	}
+
+	timeOff, err := uc.repo.GetTimeOff(ftx, timeOffId)
+	if err != nil {
+		return nil, err
+	}
+	if timeOff.Status != models.TimeOffPending && timeOff.Status != models.TimeOffApproved {
+		return nil, errors.ErrTimeOffStatus
+	}
+
+	return uc.repo.GetAffectedAppointments(ftx, timeOffId)
+}
+
+// outcomeError describes why an appointment could not be canceled or reassigned, empty when it was
+func outcomeError(err error) string {
+	if err == nil {
+		return ""
+	}
+	if appErr, ok := err.(*errors.ClinicAppError); ok {
+		return appErr.Message
+	}
+	var appErr *errors.ClinicAppError
+	if stderrors.As(err, &appErr) {
+		return err.Error() // Typed errors name the status or appointment in the way
+	}
+	return errors.ErrDatabase.Message
+}
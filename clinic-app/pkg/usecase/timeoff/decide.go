@@ -0,0 +1,64 @@
+package timeoff
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/factory"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// transitions lists the statuses a time off can move to from each status.
+// Rejected and withdrawn time off is final, approved time off can still be withdrawn.
+var transitions = map[string][]string{
+	models.TimeOffPending:  {models.TimeOffApproved, models.TimeOffRejected, models.TimeOffWithdrawn},
+	models.TimeOffApproved: {models.TimeOffWithdrawn},
+}
+
+// statuses lists every status a time off can have
+var statuses = []string{models.TimeOffPending, models.TimeOffApproved, models.TimeOffRejected, models.TimeOffWithdrawn}
+
+// Approve grants a pending time off
+func (uc *timeOffUsecaseImpl) Approve(ftx factory.Service, timeOffId int) error {
+	return uc.transition(ftx, timeOffId, authz.TimeOffApprove, models.TimeOffApproved)
+}
+
+// Reject turns a pending time off down, the doctor can be booked at that time again
+func (uc *timeOffUsecaseImpl) Reject(ftx factory.Service, timeOffId int) error {
+	return uc.transition(ftx, timeOffId, authz.TimeOffApprove, models.TimeOffRejected)
+}
+
+// Withdraw takes back a pending or approved time off
+func (uc *timeOffUsecaseImpl) Withdraw(ftx factory.Service, timeOffId int) error {
+	return uc.transition(ftx, timeOffId, authz.TimeOffManage, models.TimeOffWithdrawn)
+}
+
+// transition checks that the caller may perform the action on the time off and that its current status
+// allows the move, then updates the status.
+func (uc *timeOffUsecaseImpl) transition(ftx factory.Service, timeOffId int, action authz.Action, to string) error {
+	if err := uc.authorizer.Authorize(ftx, action, timeOffId); err != nil {
+		return err
+	}
+	timeOff, err := uc.repo.GetTimeOff(ftx, timeOffId)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(transitions[timeOff.Status], to) {
+		return errors.ErrTimeOffStatus
+	}
+
+	err = uc.repo.UpdateTimeOffStatus(ftx, timeOffId, timeOff.Status, to)
+	if err != nil {
+		ftx.Logger().Error("Error updating time off status", zap.Error(err))
+		return err
+	}
+
+	// The doctor can be booked at that time again
+	if to == models.TimeOffRejected || to == models.TimeOffWithdrawn {
+		uc.released.Release(ftx, timeOff.DoctorID, time.Time{})
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package timeoff
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/clinictime"
+	"clinic-app/pkg/services/factory"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+// Request records time off for a doctor, pending until an admin decides on it.
+// It returns the appointments the time off affects so they can be canceled or reassigned.
+func (uc *timeOffUsecaseImpl) Request(ftx factory.Service, request models.RequestTimeOff) (models.TimeOff, []models.Appointment, error) {
+	// Doctors may only request time off for themselves
+	if err := uc.authorizer.Authorize(ftx, authz.TimeOffRequest, request.DoctorID); err != nil {
+		return models.TimeOff{}, nil, err
+	}
+
+	if request.AllDay {
+		// Whole clinic days, which are not always 24 hours long
+		request.StartTime = clinictime.Day(request.StartTime)
+		request.EndTime = clinictime.EndOfDay(request.EndTime)
+	}
+	if !validTimeOff(request) {
+		return models.TimeOff{}, nil, errors.ErrInvalidTimeOff
+	}
+
+	timeOff, err := uc.repo.CreateTimeOff(ftx, request)
+	if err != nil {
+		ftx.Logger().Error("Error requesting time off", zap.Error(err))
+		return models.TimeOff{}, nil, err
+	}
+
+	affected, err := uc.repo.GetAffectedAppointments(ftx, timeOff.ID)
+	if err != nil {
+		return timeOff, nil, err
+	}
+	return timeOff, affected, nil
+}
+
+// DoctorTimeOff retrieves the time off of a doctor that has not ended yet
+func (uc *timeOffUsecaseImpl) DoctorTimeOff(ftx factory.Service, doctorId int) ([]models.TimeOff, error) {
+	// Doctors may only view their own time off
+	if err := uc.authorizer.Authorize(ftx, authz.TimeOffRead, doctorId); err != nil {
+		return nil, err
+	}
+
+	timeOffs, err := uc.repo.GetDoctorTimeOff(ftx, doctorId)
+	if err != nil {
+		ftx.Logger().Error("Error getting doctor time off", zap.Error(err))
+		return nil, err
+	}
+	return timeOffs, nil
+}
+
+// ByStatus retrieves the time off with a status that has not ended yet, admins use it to find pending requests
+func (uc *timeOffUsecaseImpl) ByStatus(ftx factory.Service, status string) ([]models.TimeOff, error) {
+	if !slices.Contains(statuses, status) {
+		return nil, errors.ErrBadRequest
+	}
+
+	timeOffs, err := uc.repo.GetTimeOffByStatus(ftx, status)
+	if err != nil {
+		ftx.Logger().Error("Error getting time off by status", zap.Error(err))
+		return nil, err
+	}
+	return timeOffs, nil
+}
+
+// validTimeOff checks that a time off ends in the future after it starts.
+// Repeated time off must be shorter than a week, so its occurrences do not overlap, and repeat for at most a year.
+func validTimeOff(request models.RequestTimeOff) bool {
+	if !request.StartTime.Before(request.EndTime) || !request.EndTime.After(time.Now()) {
+		return false
+	}
+	if request.RepeatUntil == nil {
+		return true
+	}
+
+	until, err := time.ParseInLocation("2006-01-02", *request.RepeatUntil, clinictime.Location())
+	if err != nil {
+		return false
+	}
+	start := clinictime.Day(request.StartTime)
+	return request.EndTime.Sub(request.StartTime) < week &&
+		!until.Before(start) &&
+		until.Sub(start) <= 366*day
+}
@@ -0,0 +1,25 @@
+package timeoff
+
+import (
+	"clinic-app/pkg/repository"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/releases"
+	"clinic-app/pkg/usecase"
+)
+
+type timeOffUsecaseImpl struct {
+	repo         repository.TimeOffRepository
+	appointments usecase.AppointmentUsecase // Cancels and moves the appointments a time off affects
+	authorizer   *authz.Authorizer
+	released     releases.Notifier // Tells the waitlist when a doctor is available again
+}
+
+// New creates a new instance of timeOffUsecaseImpl and returns it as the TimeOffUsecase interface
+func New(repo repository.TimeOffRepository, appointments usecase.AppointmentUsecase, authorizer *authz.Authorizer, released releases.Notifier) usecase.TimeOffUsecase {
+	return &timeOffUsecaseImpl{
+		repo,
+		appointments,
+		authorizer,
+		released,
+	}
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/factory"
+)
+
+// WaitlistUsecase defines methods for waiting for a doctor and answering the slots offered from the waitlist.
+type WaitlistUsecase interface {
+	Join(ftx factory.Service, request models.JoinWaitlist) (models.WaitlistEntry, error)
+	MyEntries(ftx factory.Service) ([]models.WaitlistEntry, error)
+	DoctorWaitlist(ftx factory.Service, doctorId int) ([]models.WaitlistEntry, error)
+	Leave(ftx factory.Service, entryId int) error
+	AcceptOffer(ftx factory.Service, offerId int) (int, error)
+	DeclineOffer(ftx factory.Service, offerId int) error
+	Promote(ftx factory.Service) (int, error)
+	PromoteReleased(ftx factory.Service, released models.ReleasedCapacity) (int, error)
+}
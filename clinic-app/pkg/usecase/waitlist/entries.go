@@ -0,0 +1,111 @@
+package waitlist
+
+import (
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/clinictime"
+	"clinic-app/pkg/services/factory"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDuration = 30  // Minutes a patient waits for unless asked otherwise
+	minDuration     = 5   // Shortest appointment a patient can wait for
+	maxDuration     = 480 // Longest appointment a patient can wait for, the doctor's booking policy narrows it further
+	maxRangeDays    = 90  // Longest range of days an entry can wait for
+	maxWindows      = 10  // Most preferred times of day an entry can have
+)
+
+// Join puts the calling patient on the waitlist of a doctor
+func (uc *waitlistUsecaseImpl) Join(ftx factory.Service, request models.JoinWaitlist) (models.WaitlistEntry, error) {
+	request.PatientID = ftx.Principal().UserID
+	if err := uc.authorizer.Authorize(ftx, authz.WaitlistJoin, request.PatientID); err != nil {
+		return models.WaitlistEntry{}, err
+	}
+
+	if request.DurationMinutes == 0 {
+		request.DurationMinutes = defaultDuration
+	}
+	if !validEntry(request) {
+		return models.WaitlistEntry{}, errors.ErrInvalidWaitlist
+	}
+
+	entry, err := uc.repo.CreateEntry(ftx, request)
+	if err != nil {
+		ftx.Logger().Error("Error joining waitlist", zap.Error(err))
+		return models.WaitlistEntry{}, err
+	}
+	return entry, nil
+}
+
+// MyEntries retrieves every waitlist entry of the calling patient with the offers made to it
+func (uc *waitlistUsecaseImpl) MyEntries(ftx factory.Service) ([]models.WaitlistEntry, error) {
+	entries, err := uc.repo.GetPatientEntries(ftx, ftx.Principal().UserID)
+	if err != nil {
+		ftx.Logger().Error("Error getting waitlist entries", zap.Error(err))
+		return nil, err
+	}
+	return entries, nil
+}
+
+// DoctorWaitlist retrieves the patients waiting for a doctor in the order they are offered slots
+func (uc *waitlistUsecaseImpl) DoctorWaitlist(ftx factory.Service, doctorId int) ([]models.WaitlistEntry, error) {
+	// Doctors may only view their own waitlist
+	if err := uc.authorizer.Authorize(ftx, authz.WaitlistRead, doctorId); err != nil {
+		return nil, err
+	}
+
+	entries, err := uc.repo.GetDoctorEntries(ftx, doctorId)
+	if err != nil {
+		ftx.Logger().Error("Error getting doctor waitlist", zap.Error(err))
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Leave takes an entry off the waitlist, a slot held for it is released
+func (uc *waitlistUsecaseImpl) Leave(ftx factory.Service, entryId int) error {
+	// Patients may only take their own entries off the waitlist
+	if err := uc.authorizer.Authorize(ftx, authz.WaitlistLeave, entryId); err != nil {
+		return err
+	}
+	return uc.repo.LeaveWaitlist(ftx, entryId)
+}
+
+// validEntry checks the days, the duration and the windows of a waitlist entry.
+// The days must not lie in the past and the windows must fit the duration.
+func validEntry(request models.JoinWaitlist) bool {
+	from, err := time.ParseInLocation("2006-01-02", request.DateFrom, clinictime.Location())
+	if err != nil {
+		return false
+	}
+	to, err := time.ParseInLocation("2006-01-02", request.DateTo, clinictime.Location())
+	if err != nil || to.Before(from) || to.After(from.AddDate(0, 0, maxRangeDays)) {
+		return false
+	}
+	if to.Before(clinictime.Today()) {
+		return false
+	}
+	if request.DurationMinutes < minDuration || request.DurationMinutes > maxDuration {
+		return false
+	}
+
+	if len(request.Windows) > maxWindows {
+		return false
+	}
+	duration := time.Duration(request.DurationMinutes) * time.Minute
+	for _, window := range request.Windows {
+		start, err := time.Parse("15:04", window.StartTime)
+		if err != nil {
+			return false
+		}
+		end, err := time.Parse("15:04", window.EndTime)
+		if err != nil || end.Sub(start) < duration {
+			return false
+		}
+	}
+	return true
+}
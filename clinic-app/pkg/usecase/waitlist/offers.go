@@ -0,0 +1,132 @@
+package waitlist
+
+import (
+	"clinic-app/pkg/adapters/mailer"
+	"clinic-app/pkg/domain/errors"
+	"clinic-app/pkg/domain/models"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/services/clinictime"
+	"clinic-app/pkg/services/factory"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const offerGranularity = 15 // Minutes between the starts of the slots considered for an offer
+
+// AcceptOffer books the offered slot for the calling patient and records the acceptance.
+// It returns the ID of the booked appointment.
+func (uc *waitlistUsecaseImpl) AcceptOffer(ftx factory.Service, offerId int) (int, error) {
+	// Patients may only answer the offers made to them
+	if err := uc.authorizer.Authorize(ftx, authz.WaitlistRespond, offerId); err != nil {
+		return 0, err
+	}
+
+	offer, err := uc.repo.GetOffer(ftx, offerId)
+	if err != nil {
+		return 0, err
+	}
+	if offer.Status != models.OfferOpen || !offer.ExpiresAt.After(time.Now()) {
+		return 0, errors.ErrOfferClosed
+	}
+
+	// The hold lets the patient book the slot even though it is kept from everybody else
+	err = uc.appointments.Book(ftx, models.BookAppointment{
+		DoctorID:  offer.DoctorID,
+		Date:      clinictime.Day(offer.StartTime),
+		StartTime: offer.StartTime,
+		EndTime:   offer.EndTime,
+	})
+	if err != nil {
+		ftx.Logger().Info("Could not book waitlist offer", zap.Int("Waitlist Offer ID", offerId), zap.Error(err))
+		return 0, err
+	}
+
+	return uc.repo.AcceptOffer(ftx, offerId)
+}
+
+// DeclineOffer records that the calling patient does not want the offered slot.
+// The slot goes to the next patient in line and the patient keeps waiting for another one.
+func (uc *waitlistUsecaseImpl) DeclineOffer(ftx factory.Service, offerId int) error {
+	// Patients may only answer the offers made to them
+	if err := uc.authorizer.Authorize(ftx, authz.WaitlistRespond, offerId); err != nil {
+		return err
+	}
+	return uc.repo.DeclineOffer(ftx, offerId)
+}
+
+// Promote expires unanswered offers and passed entries, then offers each waiting entry, first come
+// first served, the earliest free slot that suits it. It returns how many slots were offered.
+func (uc *waitlistUsecaseImpl) Promote(ftx factory.Service) (int, error) {
+	if _, err := uc.repo.ExpireOffers(ftx); err != nil {
+		return 0, err
+	}
+	if _, err := uc.repo.ExpireEntries(ftx); err != nil {
+		return 0, err
+	}
+
+	entryIds, err := uc.repo.GetWaitingEntries(ftx)
+	if err != nil {
+		return 0, err
+	}
+	return uc.offer(ftx, entryIds)
+}
+
+// PromoteReleased offers the capacity a doctor has again to the entries waiting for it, first come first served,
+// as soon as an appointment is cancelled or the doctor's capacity changes. Promote still runs as a backstop.
+// It returns how many slots were offered.
+func (uc *waitlistUsecaseImpl) PromoteReleased(ftx factory.Service, released models.ReleasedCapacity) (int, error) {
+	// Unanswered offers may keep the freed time from the next patient
+	if _, err := uc.repo.ExpireOffers(ftx); err != nil {
+		return 0, err
+	}
+
+	entryIds, err := uc.repo.GetReleasedEntries(ftx, released.DoctorID, released.Date)
+	if err != nil {
+		return 0, err
+	}
+	return uc.offer(ftx, entryIds)
+}
+
+// offer offers each entry the earliest free slot that suits it and tells the patient, it returns how many slots were offered
+func (uc *waitlistUsecaseImpl) offer(ftx factory.Service, entryIds []int) (int, error) {
+	offered := 0
+	for _, entryId := range entryIds {
+		notice, err := uc.repo.OfferSlot(ftx, entryId, uc.offerTTL, offerGranularity)
+		if err != nil {
+			return offered, err
+		}
+		if notice == nil {
+			continue
+		}
+		offered++
+
+		// The offer stands even when the mail does not go out, the patient also sees it under /waitlist
+		uc.notify(ftx, *notice)
+	}
+	return offered, nil
+}
+
+// notify tells the patient about a slot offered to them
+func (uc *waitlistUsecaseImpl) notify(ftx factory.Service, notice models.OfferNotice) {
+	msg := mailer.Message{
+		To:      notice.PatientEmail,
+		Subject: "An appointment slot opened up",
+		Body: fmt.Sprintf("Hello %s,\n\n"+
+			"A slot with %s opened up on %s from %s to %s and is held for you until %s.\n\n"+
+			"Accept or decline it here:\n\n%s\n\n"+
+			"If you do not answer in time, the slot is offered to the next patient on the waitlist.",
+			notice.PatientName,
+			notice.DoctorName,
+			clinictime.In(notice.StartTime).Format("Monday, 2 January 2006"),
+			clinictime.In(notice.StartTime).Format("15:04"),
+			clinictime.In(notice.EndTime).Format("15:04"),
+			clinictime.In(notice.ExpiresAt).Format("15:04"),
+			uc.appBaseURL+"/waitlist",
+		),
+	}
+	if err := uc.mailer.Send(ftx.Context(), msg); err != nil {
+		ftx.Logger().Error("Failed to send waitlist offer", zap.Int("Waitlist Offer ID", notice.ID), zap.Error(err))
+	}
+}
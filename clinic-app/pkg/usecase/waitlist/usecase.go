@@ -0,0 +1,30 @@
+package waitlist
+
+import (
+	"clinic-app/pkg/adapters/mailer"
+	"clinic-app/pkg/repository"
+	"clinic-app/pkg/services/authz"
+	"clinic-app/pkg/usecase"
+	"time"
+)
+
+type waitlistUsecaseImpl struct {
+	repo         repository.WaitlistRepository
+	appointments usecase.AppointmentUsecase // Books the offered slots
+	authorizer   *authz.Authorizer
+	mailer       mailer.Mailer // Tells patients about the slots offered to them
+	offerTTL     time.Duration // How long an offered slot is held for the patient
+	appBaseURL   string        // Base URL the links in emails point to
+}
+
+// New creates a new instance of waitlistUsecaseImpl and returns it as the WaitlistUsecase interface
+func New(repo repository.WaitlistRepository, appointments usecase.AppointmentUsecase, authorizer *authz.Authorizer, mailer mailer.Mailer, offerTTL time.Duration, appBaseURL string) usecase.WaitlistUsecase {
+	return &waitlistUsecaseImpl{
+		repo,
+		appointments,
+		authorizer,
+		mailer,
+		offerTTL,
+		appBaseURL,
+	}
+}